@@ -70,7 +70,7 @@ func addBotFields(p *turnplayer.BaseTurnPlayer, conf *BotConfig, botType pb.BotR
 		}
 		c2 := &equity.OpeningAdjustmentCalculator{}
 		c3, err := equity.NewPreEndgameAdjustmentCalculator(
-			&conf.Config, p.LexiconName(), conf.PEGAdjustmentFile)
+			&conf.Config, p.LexiconName(), conf.PEGAdjustmentFile, p.RackSize())
 		if err != nil {
 			return nil, err
 		}
@@ -89,7 +89,7 @@ func addBotFields(p *turnplayer.BaseTurnPlayer, conf *BotConfig, botType pb.BotR
 	// If it is a simming bot, add more fields.
 	if hasSimming(botType) {
 		c, err := equity.NewCombinedStaticCalculator(
-			p.LexiconName(), p.Config(), "", equity.PEGAdjustmentFilename)
+			p.LexiconName(), p.Config(), "", equity.PEGAdjustmentFilename, p.RackSize())
 		if err != nil {
 			return nil, err
 		}
@@ -113,7 +113,7 @@ func (p *BotTurnPlayer) GenerateMoves(numPlays int) []*move.Move {
 	curRack := p.RackFor(p.PlayerOnTurn())
 	oppRack := p.RackFor(p.NextPlayer())
 	gen := p.MoveGenerator()
-	gen.GenAll(curRack, p.Bag().TilesRemaining() >= game.ExchangeLimit)
+	gen.GenAll(curRack, p.Bag().TilesRemaining() >= p.ExchangeLimit())
 
 	plays := gen.Plays()
 
@@ -130,7 +130,10 @@ func (p *BotTurnPlayer) GenerateMoves(numPlays int) []*move.Move {
 }
 
 func (p *BotTurnPlayer) BestPlay(ctx context.Context) (*move.Move, error) {
-	if hasSimming(p.botType) || hasEndgame(p.botType) || HasInfer(p.botType) {
+	// Sims, endgames and inference only understand two-player games; with
+	// more players every bot plays its static best move.
+	if p.NumPlayers() == 2 &&
+		(hasSimming(p.botType) || hasEndgame(p.botType) || HasInfer(p.botType)) {
 		return eliteBestPlay(ctx, p)
 	}
 	return p.GenerateMoves(1)[0], nil
@@ -17,6 +17,10 @@ import (
 
 const InferencesSimLimit = 400
 
+// MaxInferenceTime is the longest the bot spends inferring its opponent's
+// leave before simming.
+const MaxInferenceTime = 5 * time.Second
+
 // Elite bot uses Monte Carlo simulations to rank plays, plays an endgame,
 // a pre-endgame (when ready).
 
@@ -31,13 +35,15 @@ func eliteBestPlay(ctx context.Context, p *BotTurnPlayer) (*move.Move, error) {
 	opp := p.Game.RackFor(p.Game.NextPlayer()).NumTiles()
 	// If this is an annotated game, we may not have full rack info.
 	unseen := int(opp) + tr
-	// Assume our own rack is fully known, however. So if unseen == 7, the bag
-	// is empty and we should assign the oppRack accordingly.
+	// Assume our own rack is fully known, however. So if unseen is at most
+	// a full rack, the bag is empty and we should assign the oppRack accordingly.
 	useEndgame := false
 	endgamePlies := 0
 	simPlies := 0
 
-	if unseen <= 7 {
+	rackSize := p.Game.RackSize()
+
+	if unseen <= rackSize {
 		useEndgame = true
 		if tr > 0 {
 			log.Debug().Msg("assigning all unseen to opp")
@@ -53,7 +59,7 @@ func eliteBestPlay(ctx context.Context, p *BotTurnPlayer) (*move.Move, error) {
 		}
 		// Just some sort of estimate
 		endgamePlies = unseen + int(p.Game.RackFor(p.Game.PlayerOnTurn()).NumTiles())
-	} else if unseen > 7 && unseen <= 14 {
+	} else if unseen > rackSize && unseen <= 2*rackSize {
 		// at some point check for the specific case of 1 or 2 PEG when
 		// the code is ready.
 		moves = p.GenerateMoves(80)
@@ -102,12 +108,30 @@ func endGameBest(ctx context.Context, p *BotTurnPlayer, endgamePlies int) (*move
 	}
 	v, seq, err := p.endgamer.Solve(ctx, endgamePlies)
 	if err != nil {
+		if ctx.Err() != nil {
+			// We ran out of time before finding any sequence.
+			log.Info().AnErr("endgame-error", err).Msg("endgame-out-of-time")
+			return p.GenerateMoves(1)[0], nil
+		}
 		return nil, err
 	}
 	log.Debug().Float32("best-endgame-val", v).Interface("seq", seq).Msg("endgame-solve-done")
 	return seq[0], nil
 }
 
+// inferenceTime returns how long to spend on inference: MaxInferenceTime,
+// or a quarter of the time left before the deadline of ctx, to leave most
+// of it for the sim.
+func inferenceTime(ctx context.Context) time.Duration {
+	t := MaxInferenceTime
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline) / 4; left < t {
+			t = left
+		}
+	}
+	return t
+}
+
 func nonEndgameBest(ctx context.Context, p *BotTurnPlayer, simPlies int, moves []*move.Move) (*move.Move, error) {
 	// use montecarlo if we have it.
 	if !hasSimming(p.botType) {
@@ -126,8 +150,7 @@ func nonEndgameBest(ctx context.Context, p *BotTurnPlayer, simPlies int, moves [
 			// ignore all errors and move on.
 			log.Debug().AnErr("inference-prepare-error", err).Msg("probably-ok")
 		} else {
-			inferTimeout, cancel = context.WithTimeout(context.Background(),
-				time.Duration(5*int(time.Second)))
+			inferTimeout, cancel = context.WithTimeout(ctx, inferenceTime(ctx))
 			defer cancel()
 			err = p.inferencer.Infer(inferTimeout)
 			if err != nil {
@@ -141,7 +164,10 @@ func nonEndgameBest(ctx context.Context, p *BotTurnPlayer, simPlies int, moves [
 	if p.simThreads != 0 {
 		p.simmer.SetThreads(p.simThreads)
 	}
-	p.simmer.PrepareSim(simPlies, moves)
+	err := p.simmer.PrepareSim(simPlies, moves)
+	if err != nil {
+		return nil, err
+	}
 	p.simmer.SetStoppingCondition(montecarlo.Stop99)
 
 	if HasInfer(p.botType) && len(p.inferencer.Inferences()) > InferencesSimLimit {
@@ -149,11 +175,17 @@ func nonEndgameBest(ctx context.Context, p *BotTurnPlayer, simPlies int, moves [
 		p.simmer.SetInferences(p.inferencer.Inferences(), montecarlo.InferenceCycle)
 	}
 
-	// Simulate is a blocking play:
-	err := p.simmer.Simulate(ctx)
+	// Simulate is a blocking play. It stops at the deadline of ctx, if it
+	// gets there before the stopping condition.
+	err = p.simmer.Simulate(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if p.simmer.Iterations() == 0 {
+		// There was no time to sim at all.
+		log.Info().Msg("sim-out-of-time")
+		return moves[0], nil
+	}
 	plays := p.simmer.WinningPlays()
 	log.Debug().Interface("winning-move", plays[0].Move().String()).Msg("sim-done")
 	return plays[0].Move(), nil
@@ -0,0 +1,24 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestInferenceTime(t *testing.T) {
+	is := is.New(t)
+	is.Equal(inferenceTime(context.Background()), MaxInferenceTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	is.Equal(inferenceTime(ctx), MaxInferenceTime)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+	it := inferenceTime(ctx)
+	is.True(it <= time.Second)
+	is.True(it > 900*time.Millisecond)
+}
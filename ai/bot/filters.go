@@ -115,6 +115,27 @@ func filter(cfg *config.Config, g *game.Game, rack *tilemapping.Rack, plays []*m
 	return passMove
 }
 
+// Findability estimates how likely a player is to see a word, from 0 to 1,
+// by how many ways its letters can be drawn: rarer combinations of letters
+// are harder to find, and more so in longer words.
+func Findability(dist *tilemapping.LetterDistribution, word tilemapping.MachineWord) float64 {
+	if len(word) < 2 {
+		return 1
+	}
+	combos := Combinations(dist, word)
+	if combos == 0 {
+		return 0
+	}
+	return probableFindability(len(word), combos)
+}
+
+// Combinations returns the number of ways the letters of word can be drawn
+// from a full bag of the distribution, using up to two blanks. The more
+// combinations, the more probable the word.
+func Combinations(dist *tilemapping.LetterDistribution, word tilemapping.MachineWord) uint64 {
+	return combinations(dist, createSubCombos(dist), word, true)
+}
+
 func probableFindability(wordLen int, combos uint64) float64 {
 	// This assumes the following preconditions:
 	//   len(word) >= 2
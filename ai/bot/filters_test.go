@@ -18,3 +18,23 @@ func TestCombinations(t *testing.T) {
 	cmbs := combinations(ld, scc, []tilemapping.MachineLetter{1, 5, 8, 10}, true)
 	is.Equal(cmbs, uint64(1121))
 }
+
+func TestFindability(t *testing.T) {
+	is := is.New(t)
+	cfg := config.DefaultConfig()
+	ld, err := tilemapping.EnglishLetterDistribution(&cfg)
+	is.NoErr(err)
+	word := func(s string) tilemapping.MachineWord {
+		mw, err := tilemapping.ToMachineWord(s, ld.TileMapping())
+		is.NoErr(err)
+		return mw
+	}
+
+	is.Equal(Findability(ld, word("A")), 1.0)
+	is.Equal(Findability(ld, word("HAJE")), 1.0)
+	// Common letters are easy to find, even in long words; rare ones are not.
+	is.Equal(Findability(ld, word("RETINAS")), 1.0)
+	muzjiks := Findability(ld, word("MUZJIKS"))
+	is.True(muzjiks > 0)
+	is.True(muzjiks < 1)
+}
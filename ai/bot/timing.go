@@ -0,0 +1,26 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/domino14/macondo/game"
+)
+
+// MinThinkingTime is the least amount of time a bot spends on a move in a
+// timed game, even if it is already in overtime.
+const MinThinkingTime = 500 * time.Millisecond
+
+// ThinkingTime returns how long the player on turn should think about
+// their next move, given the time left on their clock and the increment
+// they get after every turn. The remaining time is spread evenly over the
+// turns the player can still expect to take.
+func ThinkingTime(g *game.Game, remaining, increment time.Duration) time.Duration {
+	// Every round, each player draws about four tiles. Keep a couple of
+	// turns in reserve for the endgame.
+	turnsLeft := 2 + g.Bag().TilesRemaining()/(4*g.NumPlayers())
+	budget := remaining/time.Duration(turnsLeft) + increment
+	if budget < MinThinkingTime {
+		budget = MinThinkingTime
+	}
+	return budget
+}
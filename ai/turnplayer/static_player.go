@@ -86,7 +86,7 @@ func (p *AIStaticTurnPlayer) GenerateMoves(numPlays int) []*move.Move {
 	curRack := p.RackFor(p.PlayerOnTurn())
 	oppRack := p.RackFor(p.NextPlayer())
 
-	p.gen.GenAll(curRack, p.Bag().TilesRemaining() >= game.ExchangeLimit)
+	p.gen.GenAll(curRack, p.Bag().TilesRemaining() >= p.ExchangeLimit())
 
 	plays := p.gen.Plays()
 
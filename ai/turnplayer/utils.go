@@ -18,6 +18,6 @@ func GenBestStaticTurn(g *game.Game, p AITurnPlayer, playerIdx int) *move.Move {
 	mg.(*movegen.GordonGenerator).SetGame(g)
 
 	// Add an exchange only if there are 7 or more tiles in the bag.
-	mg.GenAll(g.RackFor(playerIdx), g.Bag().TilesRemaining() >= game.ExchangeLimit)
+	mg.GenAll(g.RackFor(playerIdx), g.Bag().TilesRemaining() >= g.ExchangeLimit())
 	return mg.Plays()[0]
 }
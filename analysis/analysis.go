@@ -0,0 +1,414 @@
+// Package analysis analyzes positions and games for requests in a JSON
+// schema that is shared by the analysis server and the wasm build. See
+// docs/manual/server.md for the schema.
+package analysis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/cgp"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/endgame/alphabeta"
+	"github.com/domino14/macondo/equity"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/gcgio"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/lexicon"
+	"github.com/domino14/macondo/montecarlo"
+	"github.com/domino14/macondo/movegen"
+	"github.com/domino14/macondo/rangefinder"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var (
+	ErrGameOver    = errors.New("the game is over")
+	ErrBagNotEmpty = errors.New("the endgame search needs an empty bag")
+)
+
+const (
+	DefaultNumPlays   = 10
+	DefaultPlies      = 2
+	DefaultCandidates = 40
+	// DefaultInferTime is how long an infer job runs without a time limit.
+	DefaultInferTime = 5 * time.Second
+	// DefaultProgressInterval is how often a sim reports its progress.
+	DefaultProgressInterval = time.Second
+	// iterationPollInterval is how often a sim checks if it reached its
+	// iteration limit.
+	iterationPollInterval = 50 * time.Millisecond
+)
+
+// Analyzer analyzes with macondo's own move generator, simmer, endgame
+// solver and inference engine.
+type Analyzer struct {
+	cfg              *config.Config
+	progressInterval time.Duration
+}
+
+func NewAnalyzer(cfg *config.Config) *Analyzer {
+	return &Analyzer{cfg: cfg, progressInterval: DefaultProgressInterval}
+}
+
+// SetProgressInterval sets how often a sim reports its progress.
+func (b *Analyzer) SetProgressInterval(d time.Duration) {
+	b.progressInterval = d
+}
+
+// Player sets up the position of a request.
+func (b *Analyzer) Player(req *Request) (*bot.BotTurnPlayer, error) {
+	cfg := *b.cfg
+	if req.Lexicon != "" {
+		cfg.DefaultLexicon = req.Lexicon
+	}
+	var g *game.Game
+	var err error
+	if req.CGP != "" {
+		// A lexicon in the position itself takes precedence.
+		g, err = cgp.ParseCGP(&cfg, req.CGP)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		history, err := gcgio.ParseGCGFromReader(&cfg, strings.NewReader(req.GCG))
+		if err != nil {
+			return nil, err
+		}
+		if history.Lexicon == "" {
+			history.Lexicon = cfg.DefaultLexicon
+		}
+		boardLayout, ldName, variant := game.HistoryToVariant(history)
+		rules, err := game.NewBasicGameRules(&cfg, history.Lexicon, boardLayout, ldName, game.CrossScoreAndSet, variant)
+		if err != nil {
+			return nil, err
+		}
+		g, err = game.NewFromHistory(history, rules, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p, err := bot.NewBotTurnPlayerFromGame(g, &bot.BotConfig{Config: cfg}, pb.BotRequest_HASTY_BOT)
+	if err != nil {
+		return nil, err
+	}
+	p.SetBackupMode(game.InteractiveGameplayMode)
+	p.SetStateStackLength(1)
+	if req.CGP != "" {
+		p.RecalculateBoard()
+		return p, nil
+	}
+	turn := len(p.History().Events)
+	if req.Turn != nil {
+		turn = *req.Turn
+	}
+	if err := p.PlayToTurn(turn); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// playing sets up the position of a request, which must not be over.
+func (b *Analyzer) playing(req *Request) (*bot.BotTurnPlayer, error) {
+	p, err := b.Player(req)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsPlaying() {
+		return nil, ErrGameOver
+	}
+	return p, nil
+}
+
+func threads(req *Request) int {
+	if req.Threads > 0 {
+		return req.Threads
+	}
+	return int(math.Max(1, float64(runtime.NumCPU()-1)))
+}
+
+func orDefault(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
+
+func (b *Analyzer) Generate(ctx context.Context, req *Request) (*GenerateResult, error) {
+	p, err := b.playing(req)
+	if err != nil {
+		return nil, err
+	}
+	res := &GenerateResult{Plays: []Move{}}
+	for _, m := range p.GenerateMoves(orDefault(req.NumPlays, DefaultNumPlays)) {
+		res.Plays = append(res.Plays, MakeMove(m))
+	}
+	return res, nil
+}
+
+func (b *Analyzer) Sim(ctx context.Context, req *Request, progress func(*SimResult)) (*SimResult, error) {
+	p, err := b.playing(req)
+	if err != nil {
+		return nil, err
+	}
+	calc, err := equity.NewCombinedStaticCalculator(
+		p.LexiconName(), p.Config(), "", equity.PEGAdjustmentFilename, p.RackSize())
+	if err != nil {
+		return nil, err
+	}
+	simmer := &montecarlo.Simmer{}
+	simmer.Init(p.Game, []equity.EquityCalculator{calc}, calc, p.Config())
+	simmer.SetThreads(threads(req))
+	err = simmer.PrepareSim(orDefault(req.Plies, DefaultPlies),
+		p.GenerateMoves(orDefault(req.Candidates, DefaultCandidates)))
+	if err != nil {
+		return nil, err
+	}
+	// The stopping condition only applies when the client leaves it up to
+	// the server when to stop.
+	if req.TimeMs == 0 && req.Iterations == 0 {
+		switch req.StopCondition {
+		case "95":
+			simmer.SetStoppingCondition(montecarlo.Stop95)
+		case "98":
+			simmer.SetStoppingCondition(montecarlo.Stop98)
+		case "", "99":
+			simmer.SetStoppingCondition(montecarlo.Stop99)
+		}
+	}
+
+	numPlays := orDefault(req.NumPlays, DefaultNumPlays)
+	standings := func() *SimResult {
+		res := &SimResult{Iterations: simmer.Iterations(), Plays: []SimPlay{}}
+		for _, sp := range simmer.WinningPlays() {
+			if len(res.Plays) == numPlays {
+				break
+			}
+			res.Plays = append(res.Plays, SimPlay{
+				Move:      MakeMove(sp.Move()),
+				WinPct:    100 * sp.WinProb(),
+				SimEquity: sp.EquityMean(),
+			})
+		}
+		return res
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tk := time.NewTicker(iterationPollInterval)
+		defer tk.Stop()
+		lastReport := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tk.C:
+				if req.Iterations > 0 && simmer.Iterations() >= req.Iterations {
+					cancel()
+					return
+				}
+				if time.Since(lastReport) >= b.progressInterval {
+					progress(standings())
+					lastReport = time.Now()
+				}
+			}
+		}
+	}()
+	err = simmer.Simulate(ctx)
+	cancel()
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return standings(), nil
+}
+
+func (b *Analyzer) Endgame(ctx context.Context, req *Request) (*EndgameResult, error) {
+	p, err := b.playing(req)
+	if err != nil {
+		return nil, err
+	}
+	if p.Bag().TilesRemaining() > 0 {
+		return nil, ErrBagNotEmpty
+	}
+	// By default, search enough plies for both players to play out one
+	// tile at a time.
+	plies := orDefault(req.Depth, int(p.RackFor(0).NumTiles())+int(p.RackFor(1).NumTiles()))
+	gd, err := kwg.Get(p.Config(), p.LexiconName())
+	if err != nil {
+		return nil, err
+	}
+	gameCopy := p.Game.Copy()
+	gameCopy.SetBackupMode(game.SimulationMode)
+	gameCopy.SetStateStackLength(plies)
+	ld := p.Rules().LetterDistribution()
+	solver := &alphabeta.Solver{}
+	err = solver.Init(movegen.NewGordonGenerator(gd, gameCopy.Board(), ld),
+		movegen.NewGordonGenerator(gd, gameCopy.Board(), ld), gameCopy, p.Config())
+	if err != nil {
+		return nil, err
+	}
+	v, seq, err := solver.Solve(ctx, plies)
+	if err != nil {
+		return nil, err
+	}
+	res := &EndgameResult{Spread: int(math.Round(float64(v))), Depth: plies, PV: []Move{}}
+	for _, m := range seq {
+		res.PV = append(res.PV, MakeMove(m))
+	}
+	return res, nil
+}
+
+func (b *Analyzer) Infer(ctx context.Context, req *Request) (*InferResult, error) {
+	p, err := b.playing(req)
+	if err != nil {
+		return nil, err
+	}
+	calc, err := equity.NewCombinedStaticCalculator(
+		p.LexiconName(), p.Config(), "", equity.PEGAdjustmentFilename, p.RackSize())
+	if err != nil {
+		return nil, err
+	}
+	rf := &rangefinder.RangeFinder{}
+	rf.Init(p.Game, []equity.EquityCalculator{calc}, p.Config())
+	rf.SetThreads(threads(req))
+	err = rf.PrepareFinder(p.RackFor(p.PlayerOnTurn()).TilesOn())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultInferTime)
+		defer cancel()
+	}
+	if err := rf.Infer(ctx); err != nil {
+		return nil, err
+	}
+	return inferResult(rf.Inferences(), p.Alphabet()), nil
+}
+
+// inferResult counts how often each distinct leave was inferred.
+func inferResult(inferences [][]tilemapping.MachineLetter, alph *tilemapping.TileMapping) *InferResult {
+	counts := map[string]int{}
+	for _, inf := range inferences {
+		leave := make([]tilemapping.MachineLetter, len(inf))
+		copy(leave, inf)
+		sort.Slice(leave, func(i, j int) bool { return leave[i] < leave[j] })
+		counts[tilemapping.MachineWord(leave).UserVisible(alph)]++
+	}
+	res := &InferResult{Inferences: len(inferences), Leaves: []InferredLeave{}}
+	for leave, ct := range counts {
+		res.Leaves = append(res.Leaves, InferredLeave{
+			Leave: leave,
+			Count: ct,
+			Pct:   100 * float64(ct) / float64(len(inferences)),
+		})
+	}
+	sort.Slice(res.Leaves, func(i, j int) bool {
+		if res.Leaves[i].Count != res.Leaves[j].Count {
+			return res.Leaves[i].Count > res.Leaves[j].Count
+		}
+		return res.Leaves[i].Leave < res.Leaves[j].Leave
+	})
+	return res
+}
+
+func (b *Analyzer) Load(ctx context.Context, req *Request) (*LoadResult, error) {
+	p, err := b.Player(req)
+	if err != nil {
+		return nil, err
+	}
+	return loadResult(p.Game), nil
+}
+
+// loadResult describes the position of g, and the turns of its history.
+func loadResult(g *game.Game) *LoadResult {
+	alph := g.Alphabet()
+	bd := g.Board()
+	res := &LoadResult{
+		Lexicon: g.LexiconName(),
+		Variant: string(g.Rules().Variant()),
+		Board:   make([]string, bd.Dim()),
+		OnTurn:  g.PlayerOnTurn(),
+		Bag:     g.Bag().TilesRemaining(),
+		Turn:    g.Turn(),
+		Events:  []Event{},
+		Playing: g.Playing() == pb.PlayState_PLAYING,
+	}
+	for r := 0; r < bd.Dim(); r++ {
+		var row strings.Builder
+		for c := 0; c < bd.Dim(); c++ {
+			if bd.HasLetter(r, c) {
+				row.WriteString(alph.Letter(bd.GetLetter(r, c)))
+			} else {
+				row.WriteByte('.')
+			}
+		}
+		res.Board[r] = row.String()
+	}
+	for i, pi := range g.History().Players {
+		res.Players = append(res.Players, PlayerState{
+			Nickname: pi.Nickname,
+			Score:    g.PointsFor(i),
+			Rack:     g.RackLettersFor(i),
+		})
+	}
+	for _, evt := range g.History().Events {
+		e := Event{
+			Player:     int(evt.PlayerIndex),
+			Type:       evt.Type.String(),
+			Score:      int(evt.Score),
+			Cumulative: int(evt.Cumulative),
+		}
+		if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE || evt.Type == pb.GameEvent_EXCHANGE {
+			e.Notation = EventNotation(evt)
+		}
+		res.Events = append(res.Events, e)
+	}
+	return res
+}
+
+func (b *Analyzer) CheckWords(ctx context.Context, req *Request) (*CheckWordsResult, error) {
+	lex := req.Lexicon
+	if lex == "" {
+		lex = b.cfg.DefaultLexicon
+	}
+	gd, err := kwg.Get(b.cfg, lex)
+	if err != nil {
+		return nil, err
+	}
+	return checkWords(kwg.Lexicon{KWG: *gd}, req.Words), nil
+}
+
+// checkWords looks up words in lex. Words with letters that aren't in the
+// alphabet are not valid.
+func checkWords(lex lexicon.Lexicon, words []string) *CheckWordsResult {
+	res := &CheckWordsResult{Lexicon: lex.Name(), Words: []WordCheck{}, Valid: true}
+	for _, w := range words {
+		w = strings.ToUpper(strings.TrimSpace(w))
+		mw, err := tilemapping.ToMachineWord(w, lex.GetAlphabet())
+		valid := err == nil && lex.HasWord(mw)
+		res.Words = append(res.Words, WordCheck{Word: w, Valid: valid})
+		res.Valid = res.Valid && valid
+	}
+	return res
+}
+
+// EventNotation writes a play or exchange event the same way as a Move.
+func EventNotation(evt *pb.GameEvent) string {
+	if evt.Type == pb.GameEvent_EXCHANGE {
+		return "-" + evt.Exchanged
+	}
+	return evt.Position + " " + evt.PlayedTiles
+}
@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/lexicon"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func TestMakeMove(t *testing.T) {
+	is := is.New(t)
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	alph := ld.TileMapping()
+
+	m := MakeMove(move.NewScoringMoveSimple(20, "8D", "ST.IR", "AEN", alph))
+	is.Equal(m, Move{Notation: "8D ST.IR", Action: "play", Coords: "8D", Tiles: "ST.IR",
+		Leave: "AEN", Score: 20})
+
+	tiles, err := tilemapping.ToMachineWord("QV", alph)
+	is.NoErr(err)
+	leave, err := tilemapping.ToMachineWord("AEIRS", alph)
+	is.NoErr(err)
+	m = MakeMove(move.NewExchangeMove(tiles, leave, alph))
+	is.Equal(m.Notation, "-QV")
+	is.Equal(m.Action, "exchange")
+	is.Equal(MakeMove(move.NewPassMove(leave, alph)).Notation, "-")
+}
+
+func TestInferResult(t *testing.T) {
+	is := is.New(t)
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	alph := ld.TileMapping()
+
+	word := func(s string) []tilemapping.MachineLetter {
+		w, err := tilemapping.ToMachineWord(s, alph)
+		is.NoErr(err)
+		return w
+	}
+	res := inferResult([][]tilemapping.MachineLetter{word("SE"), word("ES"), word("Q"), word("ES")}, alph)
+	is.Equal(res.Inferences, 4)
+	is.Equal(res.Leaves, []InferredLeave{
+		{Leave: "ES", Count: 3, Pct: 75},
+		{Leave: "Q", Count: 1, Pct: 25},
+	})
+}
+
+func TestValidate(t *testing.T) {
+	is := is.New(t)
+	is.Equal((&Request{}).Validate(KindGenerate), ErrNoPosition)
+	is.Equal((&Request{}).Validate(KindCheckWords), ErrNeedWords)
+	is.NoErr((&Request{Words: []string{"QI"}}).Validate(KindCheckWords))
+	is.NoErr((&Request{CGP: "x"}).Validate(KindLoad))
+}
+
+func TestLoadResult(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, game.VarClassic)
+	is.NoErr(err)
+	g, err := game.NewGame(rules, []*pb.PlayerInfo{
+		{Nickname: "p1", RealName: "Player 1"},
+		{Nickname: "p2", RealName: "Player 2"},
+	})
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	is.NoErr(g.SetRackFor(0, tilemapping.RackFromString("?EINRST", g.Alphabet())))
+	m, err := g.CreateAndScorePlacementMove("8D", "STaIR", "?EINRST")
+	is.NoErr(err)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.NoErr(g.SetRackFor(1, tilemapping.RackFromString("AEGLNOT", g.Alphabet())))
+
+	res := loadResult(g)
+	is.Equal(len(res.Board), 15)
+	is.Equal(res.Board[7], "...STaIR.......")
+	is.Equal(res.Board[0], "...............")
+	is.Equal(res.OnTurn, 1)
+	is.Equal(res.Turn, 1)
+	is.Equal(res.Bag, 100-7-7-5)
+	is.True(res.Playing)
+	// p1 drew new tiles after playing.
+	is.Equal(res.Players[0].Nickname, "p1")
+	is.Equal(res.Players[0].Score, m.Score())
+	is.Equal(len(res.Players[0].Rack), 7)
+	is.Equal(res.Players[1].Rack, "AEGLNOT")
+	is.Equal(res.Events, []Event{{Player: 0, Type: "TILE_PLACEMENT_MOVE", Notation: "8D STaIR",
+		Score: m.Score(), Cumulative: m.Score()}})
+}
+
+type oneWordLexicon struct {
+	lexicon.AcceptAll
+	word string
+}
+
+func (l oneWordLexicon) HasWord(w lexicon.Word) bool {
+	return w.UserVisible(l.Alph) == l.word
+}
+
+func TestCheckWords(t *testing.T) {
+	is := is.New(t)
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	lex := oneWordLexicon{AcceptAll: lexicon.AcceptAll{Alph: ld.TileMapping()}, word: "QI"}
+
+	res := checkWords(lex, []string{"qi", " QI "})
+	is.True(res.Valid)
+	is.Equal(res.Words, []WordCheck{{"QI", true}, {"QI", true}})
+
+	res = checkWords(lex, []string{"QI", "ZA", "Q!"})
+	is.True(!res.Valid)
+	is.Equal(res.Words, []WordCheck{{"QI", true}, {"ZA", false}, {"Q!", false}})
+}
@@ -0,0 +1,313 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/domino14/macondo/move"
+)
+
+// The kinds of requests. Each has its own endpoint on the server.
+const (
+	KindGenerate     = "generate"
+	KindSim          = "sim"
+	KindEndgame      = "endgame"
+	KindInfer        = "infer"
+	KindEvaluateGame = "evaluate-game"
+	KindLoad         = "load"
+	KindCheckWords   = "check-words"
+)
+
+// The kinds of evaluation evaluate-game can do.
+const (
+	EvaluationStatic = "static"
+	EvaluationSim    = "sim"
+)
+
+var Kinds = []string{KindGenerate, KindSim, KindEndgame, KindInfer, KindEvaluateGame,
+	KindLoad, KindCheckWords}
+
+var (
+	ErrNoPosition   = errors.New("send either a cgp or a gcg")
+	ErrTwoPositions = errors.New("send a cgp or a gcg, not both")
+	ErrNeedGCG      = errors.New("evaluate-game needs a gcg")
+	ErrNeedUser     = errors.New("evaluate-game needs a user")
+	ErrNeedWords    = errors.New("check-words needs some words")
+)
+
+// Request is the body of a request to start a job. Fields that do not
+// apply to a job's kind are ignored, and zero values mean defaults.
+type Request struct {
+	// CGP is the position to analyze.
+	CGP string `json:"cgp,omitempty"`
+	// GCG is a game to analyze, as the contents of a GCG file.
+	GCG string `json:"gcg,omitempty"`
+	// Turn is the turn of the GCG to analyze, starting at 0. By default the
+	// position after the last event is analyzed.
+	Turn *int `json:"turn,omitempty"`
+	// Lexicon is the lexicon to use if the CGP or GCG does not name one.
+	Lexicon string `json:"lexicon,omitempty"`
+	// TimeMs is the maximum number of milliseconds the job may run for.
+	TimeMs int `json:"timeMs,omitempty"`
+
+	// NumPlays is the number of plays to return, for generate and sim.
+	NumPlays int `json:"numPlays,omitempty"`
+	// Threads is the number of threads to sim or infer with.
+	Threads int `json:"threads,omitempty"`
+
+	// Plies is the number of plies to sim.
+	Plies int `json:"plies,omitempty"`
+	// Candidates is the number of plays, by static equity, to sim.
+	Candidates int `json:"candidates,omitempty"`
+	// Iterations stops a sim after this many iterations.
+	Iterations int `json:"iterations,omitempty"`
+	// StopCondition is one of "95", "98", "99" or "none". It only applies
+	// to sims with no time or iteration limit, and defaults to "99".
+	StopCondition string `json:"stopCondition,omitempty"`
+
+	// Depth is the number of plies to solve an endgame to.
+	Depth int `json:"depth,omitempty"`
+
+	// User is the nickname of the player whose moves evaluate-game
+	// evaluates.
+	User string `json:"user,omitempty"`
+	// Evaluation is "static", the default, or "sim". Sim evaluations use
+	// Plies, Candidates and Iterations.
+	Evaluation string `json:"evaluation,omitempty"`
+	// PhonyChallengeChance is how likely the user's phonies are to be
+	// challenged, from 0 to 1. By default it depends on the challenge rule.
+	PhonyChallengeChance float64 `json:"phonyChallengeChance,omitempty"`
+
+	// Words are the words check-words looks up in the lexicon. It needs no
+	// position.
+	Words []string `json:"words,omitempty"`
+}
+
+// Validate returns an error if the request does not make sense for the
+// kind.
+func (r *Request) Validate(kind string) error {
+	if kind == KindCheckWords {
+		if len(r.Words) == 0 {
+			return ErrNeedWords
+		}
+	} else if r.CGP == "" && r.GCG == "" {
+		return ErrNoPosition
+	}
+	switch {
+	case r.CGP != "" && r.GCG != "":
+		return ErrTwoPositions
+	case r.TimeMs < 0 || r.NumPlays < 0 || r.Threads < 0 || r.Plies < 0 ||
+		r.Candidates < 0 || r.Iterations < 0 || r.Depth < 0:
+		return errors.New("numbers in the request cannot be negative")
+	case r.Turn != nil && *r.Turn < 0:
+		return errors.New("turn cannot be negative")
+	case r.PhonyChallengeChance < 0 || r.PhonyChallengeChance > 1:
+		return errors.New("phonyChallengeChance must be between 0 and 1")
+	}
+	switch r.StopCondition {
+	case "", "95", "98", "99", "none":
+	default:
+		return fmt.Errorf("unknown stop condition %v", r.StopCondition)
+	}
+	switch r.Evaluation {
+	case "", EvaluationStatic, EvaluationSim:
+	default:
+		return fmt.Errorf("unknown evaluation %v", r.Evaluation)
+	}
+	if kind == KindEvaluateGame {
+		if r.GCG == "" {
+			return ErrNeedGCG
+		}
+		if r.User == "" {
+			return ErrNeedUser
+		}
+	}
+	return nil
+}
+
+// TimeLimit is how long the request may run for, or 0 for no limit.
+func (r *Request) TimeLimit() time.Duration {
+	return time.Duration(r.TimeMs) * time.Millisecond
+}
+
+// Move is a move as it is returned.
+type Move struct {
+	// Notation is the move as written in GCG files: "8D STAIR" with a "."
+	// for every tile played through, "-QV" for an exchange and "-" for a
+	// pass.
+	Notation string `json:"notation"`
+	// Action is play, exchange or pass.
+	Action string  `json:"action"`
+	Coords string  `json:"coords,omitempty"`
+	Tiles  string  `json:"tiles,omitempty"`
+	Leave  string  `json:"leave"`
+	Score  int     `json:"score"`
+	Equity float64 `json:"equity"`
+}
+
+// MakeMove converts a move to its JSON form.
+func MakeMove(m *move.Move) Move {
+	j := Move{
+		Notation: m.Notation(),
+		Leave:    m.LeaveString(),
+		Score:    m.Score(),
+		Equity:   m.Equity(),
+	}
+	switch m.Action() {
+	case move.MoveTypePlay:
+		j.Action = "play"
+		j.Coords = m.BoardCoords()
+		j.Tiles = m.TilesString()
+	case move.MoveTypeExchange:
+		j.Action = "exchange"
+		j.Tiles = m.TilesStringExchange()
+	default:
+		j.Action = "pass"
+	}
+	return j
+}
+
+// GenerateResult is the result of a generate request: the best plays by
+// static equity.
+type GenerateResult struct {
+	Plays []Move `json:"plays"`
+}
+
+// SimPlay is a play and how it fared in a sim.
+type SimPlay struct {
+	Move
+	// WinPct is the estimated win percentage, from 0 to 100.
+	WinPct float64 `json:"winPct"`
+	// SimEquity is the mean equity over all iterations.
+	SimEquity float64 `json:"simEquity"`
+}
+
+// SimResult is both the progress and the result of a sim. Plays are
+// sorted from best to worst.
+type SimResult struct {
+	Iterations int       `json:"iterations"`
+	Plays      []SimPlay `json:"plays"`
+}
+
+// EndgameResult is the result of an endgame request.
+type EndgameResult struct {
+	// Spread is the change in spread the side to move can expect.
+	Spread int `json:"spread"`
+	Depth  int `json:"depth"`
+	// PV is the best move followed by the best replies.
+	PV []Move `json:"pv"`
+}
+
+// InferredLeave is a leave the opponent may have kept after their last
+// play, and how often it was inferred.
+type InferredLeave struct {
+	Leave string  `json:"leave"`
+	Count int     `json:"count"`
+	Pct   float64 `json:"pct"`
+}
+
+// InferResult is the result of an infer request. Leaves are sorted from most
+// to least likely.
+type InferResult struct {
+	Inferences int             `json:"inferences"`
+	Leaves     []InferredLeave `json:"leaves"`
+}
+
+// MoveEvaluation compares a move the user made to the best move. Win
+// percentages go from 0 to 100.
+type MoveEvaluation struct {
+	Turn             int     `json:"turn"`
+	Played           string  `json:"played"`
+	TopMove          string  `json:"topMove"`
+	EquityLoss       float64 `json:"equityLoss"`
+	WinPctLoss       float64 `json:"winPctLoss"`
+	MissedBingo      bool    `json:"missedBingo"`
+	PossibleStarPlay bool    `json:"possibleStarPlay"`
+	MissedStarPlay   bool    `json:"missedStarPlay"`
+	TopIsBingo       bool    `json:"topIsBingo"`
+	// These are only set by sim evaluations.
+	PlayedWinPct      float64 `json:"playedWinPct,omitempty"`
+	TopWinPct         float64 `json:"topWinPct,omitempty"`
+	SimIterations     int     `json:"simIterations,omitempty"`
+	EndgameSolved     bool    `json:"endgameSolved,omitempty"`
+	EndgameSpreadLoss int     `json:"endgameSpreadLoss,omitempty"`
+	// Phony is set when the move formed a word not in the lexicon. Its
+	// equity and win percentage are then weighted by the chance of it
+	// being challenged off.
+	Phony                bool    `json:"phony,omitempty"`
+	PhonyChallengeChance float64 `json:"phonyChallengeChance,omitempty"`
+}
+
+// ChallengeEvaluation is an opponent play that the user challenged or that
+// formed words not in the lexicon.
+type ChallengeEvaluation struct {
+	Turn              int      `json:"turn"`
+	Play              string   `json:"play"`
+	WordsFormed       []string `json:"wordsFormed"`
+	Phonies           []string `json:"phonies"`
+	Challenged        bool     `json:"challenged"`
+	MissedChallenge   bool     `json:"missedChallenge"`
+	WrongfulChallenge bool     `json:"wrongfulChallenge"`
+}
+
+// EvaluationResult is the result of an evaluate-game request.
+type EvaluationResult struct {
+	User       string                `json:"user"`
+	Plays      []MoveEvaluation      `json:"plays"`
+	Challenges []ChallengeEvaluation `json:"challenges"`
+}
+
+// PlayerState is a player's score and rack in a position. The rack of a
+// player is empty if it isn't known.
+type PlayerState struct {
+	Nickname string `json:"nickname"`
+	Score    int    `json:"score"`
+	Rack     string `json:"rack"`
+}
+
+// Event is a turn of a game.
+type Event struct {
+	Player int `json:"player"`
+	// Type is the type of the event as in macondo.proto, such as
+	// TILE_PLACEMENT_MOVE or EXCHANGE.
+	Type string `json:"type"`
+	// Notation is set for plays and exchanges, and is written like a
+	// Move's.
+	Notation   string `json:"notation,omitempty"`
+	Score      int    `json:"score"`
+	Cumulative int    `json:"cumulative"`
+}
+
+// LoadResult is the result of a load request: the position of a CGP, or of
+// a GCG at the requested turn, and the turns of the GCG.
+type LoadResult struct {
+	Lexicon string `json:"lexicon"`
+	Variant string `json:"variant"`
+	// Board has a string per row. Empty squares are "." and blanks are
+	// lowercase.
+	Board   []string      `json:"board"`
+	Players []PlayerState `json:"players"`
+	OnTurn  int           `json:"onTurn"`
+	// Bag is the number of tiles in the bag.
+	Bag int `json:"bag"`
+	// Turn is the number of events played to get to the position.
+	Turn    int     `json:"turn"`
+	Events  []Event `json:"events"`
+	Playing bool    `json:"playing"`
+}
+
+// WordCheck is whether a word is in the lexicon.
+type WordCheck struct {
+	Word  string `json:"word"`
+	Valid bool   `json:"valid"`
+}
+
+// CheckWordsResult is the result of a check-words request. Words are in
+// the order they were asked for.
+type CheckWordsResult struct {
+	Lexicon string      `json:"lexicon"`
+	Words   []WordCheck `json:"words"`
+	// Valid is whether every word is valid.
+	Valid bool `json:"valid"`
+}
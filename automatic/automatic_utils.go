@@ -20,6 +20,7 @@ import (
 
 	"github.com/domino14/macondo/ai/bot"
 	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
 	pb "github.com/domino14/macondo/gen/api/proto/macondo"
 )
 
@@ -58,6 +59,9 @@ func (r *GameRunner) playFull(addToHistory bool, gidx int) error {
 	log.Trace().Msgf("playing full, game %v", r.game.History().Uid)
 
 	for r.game.Playing() == pb.PlayState_PLAYING {
+		if r.game.CheckTimeout() {
+			break
+		}
 		err := r.PlayBestTurn(r.game.PlayerOnTurn(), addToHistory)
 		if err != nil {
 			return err
@@ -115,7 +119,7 @@ type Job struct{ gidx int }
 func StartCompVCompStaticGames(ctx context.Context, cfg *config.Config,
 	numGames int, block bool, threads int,
 	outputFilename, lexicon, letterDistribution string,
-	players []AutomaticRunnerPlayer) error {
+	players []AutomaticRunnerPlayer, timeControl *game.TimeControl) error {
 
 	if IsPlaying.Value() > 0 {
 		return errors.New("games are already being played, please wait till complete")
@@ -150,6 +154,10 @@ func StartCompVCompStaticGames(ctx context.Context, cfg *config.Config,
 	}) {
 		addToHistory = true
 	}
+	if timeControl != nil {
+		// The game clocks only run for moves added to the history.
+		addToHistory = true
+	}
 
 	for i := 1; i <= threads; i++ {
 		wg.Add(1)
@@ -157,7 +165,8 @@ func StartCompVCompStaticGames(ctx context.Context, cfg *config.Config,
 		g.Go(func() error {
 			defer wg.Done()
 			r := GameRunner{logchan: logChan, gamechan: gameChan,
-				config: cfg, lexicon: lexicon, letterDistribution: letterDistribution}
+				config: cfg, lexicon: lexicon, letterDistribution: letterDistribution,
+				timeControl: timeControl}
 			err := r.Init(players)
 			if err != nil {
 				log.Err(err).Msg("error initializing runner")
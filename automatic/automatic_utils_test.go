@@ -107,7 +107,7 @@ func TestCompVCompSeries(t *testing.T) {
 		[]AutomaticRunnerPlayer{
 			{"", "", macondo.BotRequest_HASTY_BOT, 0},
 			{"", "", macondo.BotRequest_NO_LEAVE_BOT, 0},
-		})
+		}, nil)
 
 	is.NoErr(err)
 
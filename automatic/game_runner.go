@@ -38,6 +38,8 @@ type GameRunner struct {
 	gamechan           chan string
 	aiplayers          [2]aiturnplayer.AITurnPlayer
 	order              [2]int
+	// timeControl is nil for untimed games.
+	timeControl *game.TimeControl
 }
 
 // NewGameRunner just instantiates and initializes a game runner.
@@ -58,6 +60,12 @@ type AutomaticRunnerPlayer struct {
 	MinSimPlies int
 }
 
+// SetTimeControl makes the runner play timed games. It must be called
+// before Init. Moves must be added to the history for the clocks to run.
+func (r *GameRunner) SetTimeControl(tc game.TimeControl) {
+	r.timeControl = &tc
+}
+
 // Init initializes the runner
 func (r *GameRunner) Init(players []AutomaticRunnerPlayer) error {
 
@@ -65,6 +73,11 @@ func (r *GameRunner) Init(players []AutomaticRunnerPlayer) error {
 	if err != nil {
 		return err
 	}
+	if r.timeControl != nil {
+		if err = rules.SetTimeControl(*r.timeControl); err != nil {
+			return err
+		}
+	}
 
 	pnames := playerNames(players)
 
@@ -149,6 +162,11 @@ func (r *GameRunner) genBestMoveForBot(playerIdx int) *move.Move {
 		log.Debug().Msg("runner-bag-is-empty")
 		maxTime = MaxTimePerEndgame
 	}
+	if clock := r.game.Clock(); clock != nil {
+		maxTime = bot.ThinkingTime(r.game, clock.Remaining(playerIdx),
+			clock.TimeControl().Increment)
+		log.Debug().Dur("think", maxTime).Msg("runner-budgeted-time")
+	}
 	// Otherwise use the bot's GenerateMoves function.
 	ctx, cancel := context.WithTimeout(context.Background(), maxTime)
 	defer cancel()
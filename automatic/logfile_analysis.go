@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/domino14/macondo/game"
 	"github.com/domino14/macondo/gcgio"
 	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
 	"github.com/domino14/macondo/stats"
 	"github.com/domino14/macondo/tilemapping"
 	"github.com/domino14/macondo/turnplayer"
@@ -137,16 +139,112 @@ func AnalyzeLogFile(filepath string) (string, error) {
 
 func ExportGCG(cfg *config.Config, filename, letterdist, lexicon, boardlayout, gid string,
 	out io.Writer) error {
-	if letterdist == "" {
-		letterdist = "english"
+
+	gameLines := [][]string{}
+	err := readLogLines(filename, func(record []string) error {
+		if record[1] == gid {
+			gameLines = append(gameLines, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	if boardlayout == "" {
-		boardlayout = board.CrosswordGameLayout
+	if len(gameLines) == 0 {
+		return errors.New("gameID not found in log file")
 	}
-	if lexicon == "" {
-		lexicon = "CSW21"
+	history, err := replayLogGame(cfg, letterdist, lexicon, boardlayout, gameLines)
+	if err != nil {
+		return err
+	}
+	contents, err := gcgio.GameHistoryToGCG(history, true)
+	if err != nil {
+		return err
 	}
+	_, err = out.Write([]byte(contents))
+	return err
+}
+
+// logGame is a game being rebuilt from an autoplay log, one line at a time.
+type logGame struct {
+	// seq is the order in which the game started in the log.
+	seq int
+	// first is the game's first line, until its second line names the
+	// other player and the game can start.
+	first []string
+	g     *turnplayer.BaseTurnPlayer
+}
 
+// ForEachLogGame rebuilds every game in the given autoplay log file and
+// calls fn with the history of each, whose Uid is the game's ID in the log.
+// The log is read line by line, and each game is passed to fn as soon as it
+// ends, so only the games in progress are kept in memory. Games that never
+// end, because the log was cut short, come last, in the order they
+// started. It stops at the first error fn returns.
+func ForEachLogGame(cfg *config.Config, filename, letterdist, lexicon, boardlayout string,
+	fn func(*pb.GameHistory) error) error {
+
+	games := map[string]*logGame{}
+	started := 0
+	emit := func(gid string, g *turnplayer.BaseTurnPlayer) error {
+		history := g.History()
+		history.Uid = gid
+		return fn(history)
+	}
+	err := readLogLines(filename, func(record []string) error {
+		gid := record[1]
+		lg, ok := games[gid]
+		if !ok {
+			games[gid] = &logGame{seq: started, first: record}
+			started++
+			return nil
+		}
+		var err error
+		if lg.g == nil {
+			lg.g, err = startLogGame(cfg, letterdist, lexicon, boardlayout, lg.first, record)
+			if err == nil {
+				err = playLogLine(lg.g, lg.first)
+			}
+			lg.first = nil
+		}
+		if err == nil {
+			err = playLogLine(lg.g, record)
+		}
+		if err != nil {
+			return fmt.Errorf("game %v: %w", gid, err)
+		}
+		if lg.g.Playing() == pb.PlayState_PLAYING {
+			return nil
+		}
+		// The lines of a game are no longer needed once it is over.
+		delete(games, gid)
+		return emit(gid, lg.g)
+	})
+	if err != nil {
+		return err
+	}
+
+	unfinished := make([]string, 0, len(games))
+	for gid := range games {
+		unfinished = append(unfinished, gid)
+	}
+	sort.Slice(unfinished, func(i, j int) bool {
+		return games[unfinished[i]].seq < games[unfinished[j]].seq
+	})
+	for _, gid := range unfinished {
+		if games[gid].g == nil {
+			return fmt.Errorf("game %v: %w", gid, errTooFewLogTurns)
+		}
+		if err := emit(gid, games[gid].g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLogLines calls fn with every record of the given log file, besides
+// the header. It stops at the first error fn returns.
+func readLogLines(filename string, fn func(record []string) error) error {
 	file, err := cache.Open(filename)
 	if err != nil {
 		return err
@@ -154,7 +252,6 @@ func ExportGCG(cfg *config.Config, filename, letterdist, lexicon, boardlayout, g
 	defer file.Close()
 	r := csv.NewReader(file)
 
-	gameLines := [][]string{}
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -167,23 +264,58 @@ func ExportGCG(cfg *config.Config, filename, letterdist, lexicon, boardlayout, g
 			// this is the header line
 			continue
 		}
-		if record[1] != gid {
-			continue
+		if err := fn(record); err != nil {
+			return err
 		}
-		gameLines = append(gameLines, record)
 	}
-	if len(gameLines) == 0 {
-		return errors.New("gameID not found in log file")
+	return nil
+}
+
+var errTooFewLogTurns = errors.New("game has fewer than two turns in log file")
+
+// replayLogGame plays the moves in the log lines of a single game and
+// returns its history.
+func replayLogGame(cfg *config.Config, letterdist, lexicon, boardlayout string,
+	gameLines [][]string) (*pb.GameHistory, error) {
+
+	if len(gameLines) < 2 {
+		return nil, errTooFewLogTurns
+	}
+	g, err := startLogGame(cfg, letterdist, lexicon, boardlayout, gameLines[0], gameLines[1])
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range gameLines {
+		if err := playLogLine(g, row); err != nil {
+			return nil, err
+		}
+	}
+	return g.History(), nil
+}
+
+// startLogGame starts a game between the players of its first two log
+// lines.
+func startLogGame(cfg *config.Config, letterdist, lexicon, boardlayout string,
+	first, second []string) (*turnplayer.BaseTurnPlayer, error) {
+
+	if letterdist == "" {
+		letterdist = "english"
+	}
+	if boardlayout == "" {
+		boardlayout = board.CrosswordGameLayout
+	}
+	if lexicon == "" {
+		lexicon = "CSW21"
 	}
 
 	rules, err := game.NewBasicGameRules(cfg, lexicon, boardlayout,
 		letterdist, game.CrossScoreOnly, game.VarClassic)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	players := []*pb.PlayerInfo{
-		{Nickname: gameLines[0][0], RealName: gameLines[0][0]},
-		{Nickname: gameLines[1][0], RealName: gameLines[1][0]},
+		{Nickname: first[0], RealName: first[0]},
+		{Nickname: second[0], RealName: second[0]},
 	}
 
 	g, err := turnplayer.BaseTurnPlayerFromRules(&turnplayer.GameOptions{
@@ -191,55 +323,35 @@ func ExportGCG(cfg *config.Config, filename, letterdist, lexicon, boardlayout, g
 		Variant:         game.VarClassic,
 	}, players, rules)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	g.StartGame()
+	return g, nil
+}
 
-	for _, row := range gameLines {
-		pidx := 0
-		if g.History().Players[1].Nickname == row[0] {
-			pidx = 1
-		}
-		err = g.SetRackFor(pidx, tilemapping.RackFromString(row[3], g.Alphabet()))
-		if err != nil {
-			return err
-		}
-		if strings.HasPrefix(row[4], "(exch") {
-			cmd := strings.Split(row[4], " ")
-			exchanged := strings.TrimSuffix(cmd[1], ")")
-			m, err := g.NewExchangeMove(pidx, exchanged)
-			if err != nil {
-				return err
-			}
-			err = g.PlayMove(m, true, 0)
-			if err != nil {
-				return err
-			}
-		} else if row[4] == "(Pass)" {
-			m, err := g.NewPassMove(pidx)
-			if err != nil {
-				return err
-			}
-			err = g.PlayMove(m, true, 0)
-			if err != nil {
-				return err
-			}
-		} else {
-			play := strings.Split(strings.TrimSpace(row[4]), " ")
-			m, err := g.NewPlacementMove(pidx, play[0], play[1])
-			if err != nil {
-				return err
-			}
-			err = g.PlayMove(m, true, 0)
-			if err != nil {
-				return err
-			}
-		}
+// playLogLine plays the move in a log line.
+func playLogLine(g *turnplayer.BaseTurnPlayer, row []string) error {
+	pidx := 0
+	if g.History().Players[1].Nickname == row[0] {
+		pidx = 1
 	}
-	contents, err := gcgio.GameHistoryToGCG(g.History(), true)
+	err := g.SetRackFor(pidx, tilemapping.RackFromString(row[3], g.Alphabet()))
 	if err != nil {
 		return err
 	}
-	_, err = out.Write([]byte(contents))
-	return err
+	var m *move.Move
+	if strings.HasPrefix(row[4], "(exch") {
+		cmd := strings.Split(row[4], " ")
+		exchanged := strings.TrimSuffix(cmd[1], ")")
+		m, err = g.NewExchangeMove(pidx, exchanged)
+	} else if row[4] == "(Pass)" {
+		m, err = g.NewPassMove(pidx)
+	} else {
+		play := strings.Split(strings.TrimSpace(row[4]), " ")
+		m, err = g.NewPlacementMove(pidx, play[0], play[1])
+	}
+	if err != nil {
+		return err
+	}
+	return g.PlayMove(m, true, 0)
 }
@@ -38,6 +38,13 @@ const (
 	RightDirection WordDirection = 1
 )
 
+const (
+	// DefaultBingoTiles and DefaultBingoBonus are the bingo parameters
+	// for a standard game. They can be changed with SetBingoParams.
+	DefaultBingoTiles = 7
+	DefaultBingoBonus = 50
+)
+
 const (
 	// Bonus4WS is a quadruple word score
 	Bonus4WS BonusSquare = '~'
@@ -101,6 +108,11 @@ type GameBoard struct {
 
 	rowMul int
 	colMul int
+
+	// bingoTiles is the number of tiles that must be played at once to
+	// earn the bingoBonus.
+	bingoTiles int
+	bingoBonus int
 }
 
 // MakeBoard creates a board from a description string.
@@ -142,6 +154,8 @@ func MakeBoard(desc []string) *GameBoard {
 		vAnchors:     vAs,
 		rowMul:       len(desc),
 		colMul:       1,
+		bingoTiles:   DefaultBingoTiles,
+		bingoBonus:   DefaultBingoBonus,
 	}
 	// Call Clear to set all crosses.
 	g.Clear()
@@ -726,8 +740,8 @@ func (g *GameBoard) ScoreWord(word tilemapping.MachineWord, row, col, tilesPlaye
 	mainWordScore := 0
 	crossScores := 0
 	bingoBonus := 0
-	if tilesPlayed == 7 {
-		bingoBonus = 50
+	if tilesPlayed == g.bingoTiles {
+		bingoBonus = g.bingoBonus
 	}
 	wordMultiplier := 1
 
@@ -807,6 +821,8 @@ func (g *GameBoard) Copy() *GameBoard {
 	newg.dim = g.dim
 	newg.rowMul = g.rowMul
 	newg.colMul = g.colMul
+	newg.bingoTiles = g.bingoTiles
+	newg.bingoBonus = g.bingoBonus
 	// newg.playHistory = append([]string{}, g.playHistory...)
 	return newg
 }
@@ -829,6 +845,20 @@ func (g *GameBoard) CopyFrom(b *GameBoard) {
 	g.tilesPlayed = b.tilesPlayed
 	g.rowMul = b.rowMul
 	g.colMul = b.colMul
+	g.bingoTiles = b.bingoTiles
+	g.bingoBonus = b.bingoBonus
+}
+
+// SetBingoParams sets how many tiles must be played at once for a bingo,
+// and how many bonus points a bingo earns.
+func (g *GameBoard) SetBingoParams(tiles, bonus int) {
+	g.bingoTiles = tiles
+	g.bingoBonus = bonus
+}
+
+// BingoBonus returns the bonus awarded for a bingo on this board.
+func (g *GameBoard) BingoBonus() int {
+	return g.bingoBonus
 }
 
 func (g *GameBoard) GetSquares() []tilemapping.MachineLetter {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/matryer/is"
 
+	"github.com/domino14/macondo/config"
 	"github.com/domino14/macondo/move"
 	"github.com/domino14/macondo/tilemapping"
 )
@@ -148,3 +149,20 @@ func TestFormedWordsBlank(t *testing.T) {
 	}
 	is.Equal(uvWords, []string{"TAEL", "TA", "AN", "RESPONDED", "LO"})
 }
+
+func TestScoreWordBingoParams(t *testing.T) {
+	is := is.New(t)
+	cfg := config.DefaultConfig()
+	ld, err := tilemapping.EnglishLetterDistribution(&cfg)
+	is.NoErr(err)
+	alph := ld.TileMapping()
+	b := MakeBoard(CrosswordGameBoard)
+	word, err := tilemapping.ToMachineWord("QUIXOTES", alph)
+	is.NoErr(err)
+	// 8D QUIXOTES: no bingo with a 7-tile rack.
+	is.Equal(b.ScoreWord(word, 7, 3, 8, HorizontalDirection, ld), 68)
+	b.SetBingoParams(8, 40)
+	is.Equal(b.ScoreWord(word, 7, 3, 8, HorizontalDirection, ld), 108)
+	is.Equal(b.Copy().ScoreWord(word, 7, 3, 8, HorizontalDirection, ld), 108)
+	is.Equal(b.ScoreWord(word[:7], 7, 3, 7, HorizontalDirection, ld), 66)
+}
@@ -1,6 +1,8 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -8,13 +10,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/avast/retry-go"
-	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/domino14/macondo/ai/bot"
 	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/external"
 	"github.com/domino14/macondo/game"
 	pb "github.com/domino14/macondo/gen/api/proto/macondo"
 	"github.com/domino14/macondo/move"
@@ -23,6 +24,9 @@ import (
 
 const (
 	StarPlayThreshold = 10.0 // equity
+	// ResponseMargin is the most a bot keeps out of a request's time
+	// budget for sending its answer.
+	ResponseMargin = 200 * time.Millisecond
 )
 
 func debugWriteln(msg string) {
@@ -30,11 +34,14 @@ func debugWriteln(msg string) {
 	io.WriteString(os.Stderr, "\n")
 }
 
+var errNoExternalMoves = errors.New("external engine returned no moves")
+
 type Bot struct {
 	config  *config.Config
 	options *turnplayer.GameOptions
 
-	game *bot.BotTurnPlayer
+	game   *bot.BotTurnPlayer
+	engine external.ExternalEngine
 }
 
 func NewBot(config *config.Config, options *turnplayer.GameOptions) *Bot {
@@ -71,11 +78,15 @@ func errorResponse(message string, err error) *pb.BotResponse {
 }
 
 func (bot *Bot) Deserialize(data []byte) (*game.Game, *pb.EvaluationRequest, pb.BotRequest_BotCode, error) {
-	req := pb.BotRequest{}
-	err := proto.Unmarshal(data, &req)
+	req := &pb.BotRequest{}
+	err := proto.Unmarshal(data, req)
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	return bot.deserializeRequest(req)
+}
+
+func (bot *Bot) deserializeRequest(req *pb.BotRequest) (*game.Game, *pb.EvaluationRequest, pb.BotRequest_BotCode, error) {
 	history := req.GameHistory
 	boardLayout, ldName, variant := game.HistoryToVariant(history)
 	rules, err := game.NewBasicGameRules(bot.config, history.Lexicon, boardLayout, ldName, game.CrossScoreAndSet, variant)
@@ -92,7 +103,31 @@ func (bot *Bot) Deserialize(data []byte) (*game.Game, *pb.EvaluationRequest, pb.
 	return ng, req.EvaluationRequest, req.BotType, nil
 }
 
-func evalSingleMove(g *bot.BotTurnPlayer, evtIdx int) *pb.SingleEvaluation {
+// EvaluateMove evaluates the play or exchange made in the event at evtIdx
+// against the moves the static evaluator would have made instead. It
+// leaves the game at that turn.
+func EvaluateMove(g *bot.BotTurnPlayer, evtIdx int, req *pb.EvaluationRequest) (*pb.SingleEvaluation, error) {
+	eval, _, _, err := staticEvaluation(g, evtIdx, req)
+	return eval, err
+}
+
+// playedMove is the move that was made in an evaluated turn.
+type playedMove struct {
+	m *move.Move
+	// idx is the index of the move among the moves that could have been
+	// made, or -1 if it was not found, which means it was a phony.
+	idx int
+	// pass is what the player is left with if their phony is challenged
+	// off, and chance is how likely that is. pass is nil for valid moves.
+	pass   *move.Move
+	chance float64
+}
+
+// staticEvaluation also returns all the moves that could have been made,
+// best first, and the move that was made.
+func staticEvaluation(g *bot.BotTurnPlayer, evtIdx int, req *pb.EvaluationRequest) (
+	*pb.SingleEvaluation, []*move.Move, *playedMove, error) {
+
 	evts := g.History().Events
 	playedEvt := evts[evtIdx]
 
@@ -100,14 +135,12 @@ func evalSingleMove(g *bot.BotTurnPlayer, evtIdx int) *pb.SingleEvaluation {
 	moves := g.GenerateMoves(100000)
 	// find the played move in the list of moves
 	topEquity := moves[0].Equity()
-	topIsBingo := moves[0].TilesPlayed() == game.RackTileLimit && moves[0].Action() == move.MoveTypePlay
-	foundEquity := float64(0)
-	playedBingo := false
+	topIsBingo := g.IsBingo(moves[0])
+	played := &playedMove{idx: -1}
 	hasStarPlay := false
 	if len(moves) > 1 && moves[1].Equity() < topEquity-StarPlayThreshold {
 		hasStarPlay = true
 	}
-	missedStarPlay := false
 	for idx, m := range moves {
 		evt := g.EventFromMove(m)
 		if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE || evt.Type == pb.GameEvent_EXCHANGE {
@@ -115,43 +148,96 @@ func evalSingleMove(g *bot.BotTurnPlayer, evtIdx int) *pb.SingleEvaluation {
 				evt.Exchanged == playedEvt.Exchanged &&
 				evt.Score == playedEvt.Score {
 
-				if idx > 0 && hasStarPlay {
-					// A star play is a stand-alone play that is better than anything else.
-					missedStarPlay = true
-				}
 				// Same move
-				foundEquity = m.Equity()
-				playedBingo = m.TilesPlayed() == game.RackTileLimit && m.Action() == move.MoveTypePlay
+				played.m = m
+				played.idx = idx
 				break
 			}
 		}
 	}
-	// if we don't find the move it means the user played a phony. This is ok. In the
-	// absence of a better metric, we can evaluate the phony as a 0.
-	return &pb.SingleEvaluation{
-		EquityLoss:       foundEquity - topEquity,
+	eval := &pb.SingleEvaluation{
 		TopIsBingo:       topIsBingo,
-		MissedBingo:      topIsBingo && !playedBingo,
 		PossibleStarPlay: hasStarPlay,
-		MissedStarPlay:   missedStarPlay,
+		TopMove:          moves[0].Notation(),
 	}
+	if played.m == nil {
+		// If we don't find the move, it formed a word that isn't in the
+		// lexicon. A phony is worth what it scores if it stands, and a lost
+		// turn if it gets challenged off.
+		err := played.evaluatePhony(g, playedEvt, req)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		eval.Phony = played.pass != nil
+		eval.PhonyChallengeChance = played.chance
+	}
+	// A star play is a stand-alone play that is better than anything else.
+	eval.MissedStarPlay = hasStarPlay && played.idx != 0
+	eval.MissedBingo = topIsBingo && !g.IsBingo(played.m)
+	eval.EquityLoss = played.equity() - topEquity
+	return eval, moves, played, nil
 }
 
-func (bot *Bot) evaluationResponse(req *pb.EvaluationRequest) *pb.BotResponse {
+// evaluatePhony builds the move made in evt, which the move generator did
+// not come up with, and assigns it an equity.
+func (p *playedMove) evaluatePhony(g *bot.BotTurnPlayer, evt *pb.GameEvent, req *pb.EvaluationRequest) error {
+	m, err := game.MoveFromEvent(evt, g.Alphabet(), g.Board())
+	if err != nil {
+		return err
+	}
+	oppRack := g.RackFor(g.NextPlayer())
+	g.AssignEquity([]*move.Move{m}, g.Board(), g.Bag(), oppRack)
+	p.m = m
+	if m.Action() != move.MoveTypePlay {
+		return nil
+	}
+	words, err := g.Board().FormedWords(m)
+	if err != nil {
+		return err
+	}
+	if g.ValidateWords(g.Lexicon(), words) == nil {
+		return nil
+	}
+	p.pass = move.NewPassMove(g.RackFor(g.PlayerOnTurn()).TilesOn(), g.Alphabet())
+	g.AssignEquity([]*move.Move{p.pass}, g.Board(), g.Bag(), oppRack)
+	p.chance = phonyChallengeChance(g.History().ChallengeRule, req.GetPhonyChallengeChance())
+	return nil
+}
 
-	evts := bot.game.History().Events
-	players := bot.game.History().Players
-	evals := []*pb.SingleEvaluation{}
+// equity is the static equity of the played move. For a phony, it is the
+// expected equity given the chance of it being challenged off.
+func (p *playedMove) equity() float64 {
+	if p.pass == nil {
+		return p.m.Equity()
+	}
+	return p.expected(p.m.Equity(), p.pass.Equity())
+}
+
+// expected weighs the value of a phony standing and the value of it being
+// challenged off by the chance of it being challenged.
+func (p *playedMove) expected(phonyVal, passVal float64) float64 {
+	return (1-p.chance)*phonyVal + p.chance*passVal
+}
 
-	for idx, evt := range evts {
-		evtNickname := players[evt.PlayerIndex].Nickname
-		userMatches := strings.EqualFold(evtNickname, req.User)
+// EvaluatedTurns returns the indexes of the events in the history that are
+// plays or exchanges made by the given user.
+func EvaluatedTurns(history *pb.GameHistory, user string) []int {
+	turns := []int{}
+	for idx, evt := range history.Events {
+		evtNickname := history.Players[evt.PlayerIndex].Nickname
+		userMatches := strings.EqualFold(evtNickname, user)
 		if userMatches && (evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE || evt.Type == pb.GameEvent_EXCHANGE) {
-			eval := evalSingleMove(bot.game, idx)
-			evals = append(evals, eval)
+			turns = append(turns, idx)
 		}
 	}
-	evaluation := &pb.Evaluation{PlayEval: evals}
+	return turns
+}
+
+func (bot *Bot) evaluationResponse(req *pb.EvaluationRequest) *pb.BotResponse {
+	evaluation, err := Evaluate(context.Background(), bot.game, req)
+	if err != nil {
+		return errorResponse("Could not evaluate game", err)
+	}
 	log.Info().Interface("eval", evaluation).Msg("evaluation")
 
 	return &pb.BotResponse{
@@ -160,8 +246,86 @@ func (bot *Bot) evaluationResponse(req *pb.EvaluationRequest) *pb.BotResponse {
 	}
 }
 
-func (b *Bot) handle(data []byte) *pb.BotResponse {
-	ng, evalReq, botType, err := b.Deserialize(data)
+// thinkingTime returns how long the bot may think about its next move. In a
+// timed game, it spreads the time left on its clock over the rest of the
+// game. The bot never thinks for longer than the request's budget, less a
+// margin for sending its answer. It returns 0 if the request has neither a
+// clock nor a budget.
+func thinkingTime(g *game.Game, req *pb.BotRequest) time.Duration {
+	t := clockThinkingTime(g, req)
+	if req.MillisBudget > 0 {
+		budget := time.Duration(req.MillisBudget) * time.Millisecond
+		margin := budget / 10
+		if margin > ResponseMargin {
+			margin = ResponseMargin
+		}
+		budget -= margin
+		if t == 0 || budget < t {
+			t = budget
+		}
+	}
+	return t
+}
+
+// clockThinkingTime returns how long the bot should think given its clock,
+// or 0 if the game is untimed. The clock comes from the request or, failing
+// that, from the last event the bot made.
+func clockThinkingTime(g *game.Game, req *pb.BotRequest) time.Duration {
+	increment := time.Duration(req.IncrementMillis) * time.Millisecond
+	if req.MillisRemaining != 0 {
+		remaining := time.Duration(req.MillisRemaining) * time.Millisecond
+		return bot.ThinkingTime(g, remaining, increment)
+	}
+	evts := g.History().Events
+	timed := false
+	for _, evt := range evts {
+		if evt.MillisRemaining != 0 {
+			timed = true
+			break
+		}
+	}
+	if !timed {
+		return 0
+	}
+	for i := len(evts) - 1; i >= 0; i-- {
+		if int(evts[i].PlayerIndex) == g.PlayerOnTurn() {
+			remaining := time.Duration(evts[i].MillisRemaining) * time.Millisecond
+			return bot.ThinkingTime(g, remaining, increment)
+		}
+	}
+	// The bot hasn't moved yet, so its clock is unknown.
+	return 0
+}
+
+// externalAnalyze asks the external engine for the best move in g, waiting
+// for up to think, or external.DefaultTimeout if think is 0, as an engine
+// that stalls must not hold up the bot for good. The engine is started the
+// first time it is needed.
+func (b *Bot) externalAnalyze(g *game.Game, think time.Duration) ([]*move.Move, error) {
+	if b.engine == nil {
+		eng, err := external.New(b.config)
+		if err != nil {
+			return nil, err
+		}
+		b.engine = eng
+	}
+	if think == 0 {
+		think = external.DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), think)
+	defer cancel()
+	moves, err := b.engine.Analyze(ctx, g, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(moves) < 1 {
+		return nil, errNoExternalMoves
+	}
+	return moves, nil
+}
+
+func (b *Bot) handle(req *pb.BotRequest) *pb.BotResponse {
+	ng, evalReq, botType, err := b.deserializeRequest(req)
 	if err != nil {
 		return errorResponse("Could not parse request", err)
 	}
@@ -195,15 +359,29 @@ func (b *Bot) handle(data []byte) *pb.BotResponse {
 			m, _ = g.NewPassMove(g.PlayerOnTurn())
 		} else {
 			var moves []*move.Move
-			if !isWordSmog {
-				moves = b.game.GenerateMoves(1)
-			} else {
-				moves, err = wolgesAnalyze(b.config, b.game)
+			think := thinkingTime(g.Game, req)
+			switch {
+			case isWordSmog:
+				moves, err = b.externalAnalyze(g.Game, think)
 				if err != nil {
-					log.Err(err).Msg("wolges-analyze-error")
+					log.Err(err).Msg("external-analyze-error")
 					// Just generate a move using the regular generator.
 					moves = b.game.GenerateMoves(1)
 				}
+			case think == 0:
+				// Without a clock or a budget there is no time to sim or
+				// solve in, so play the best move by static equity.
+				moves = b.game.GenerateMoves(1)
+			default:
+				ctx, cancel := context.WithTimeout(context.Background(), think)
+				best, err := b.game.BestPlay(ctx)
+				cancel()
+				if err != nil {
+					log.Err(err).Msg("best-play-error")
+					moves = b.game.GenerateMoves(1)
+				} else {
+					moves = []*move.Move{best}
+				}
 			}
 			m = moves[0]
 		}
@@ -223,61 +401,14 @@ func (b *Bot) handle(data []byte) *pb.BotResponse {
 // caller would need to block and wait for a response. Instead, we must
 // send a bot move on a separate per-game-id channel when ready.
 func Main(channel string, bot *Bot) {
-	err := bot.newGame()
+	s, err := NewService(channel, bot)
 	if err != nil {
 		log.Fatal().AnErr("newGameErr", err).Msg(":(")
 	}
-	nc, err := nats.Connect(bot.config.NatsURL)
+	err = s.Start()
 	if err != nil {
 		log.Fatal().AnErr("natsConnectErr", err).Msg(":(")
 	}
-	// A user of the bot should send the data to only one bot instance.
-	// Using a QueueSubscribe guarantees that only one listening bot will
-	// receive a message.
-	nc.QueueSubscribe(channel, "bot_queue", func(m *nats.Msg) {
-		log.Info().Str("replyChannel", m.Reply).Msgf("RECV: %d bytes", len(m.Data))
-		resp := bot.handle(m.Data)
-		// debugWriteln(proto.MarshalTextString(resp))
-		data, err := proto.Marshal(resp)
-		if err != nil {
-			// Should never happen, ideally, but we need to do something sensible here.
-			m.Respond([]byte(err.Error()))
-		} else {
-			if m.Reply != "" {
-				err = m.Respond(data)
-				if err != nil {
-					log.Err(err).Msg("error-responding")
-				}
-			} else {
-				err := retry.Do(
-					func() error {
-						_, err := nc.Request(
-							"bot.publish_event."+resp.GameId, data, 3*time.Second)
-						if err != nil {
-							return err
-						}
-						// We're just waiting for an acknowledgement. The actual
-						// data doesn't matter.
-						return nil
-					},
-					retry.DelayType(func(n uint, err error, config *retry.Config) time.Duration {
-						log.Err(err).Uint("n", n).Str("gameID", resp.GameId).
-							Msg("did-not-receive-ack-try-again")
-						return retry.BackOffDelay(n, err, config)
-					}),
-				)
-				if err != nil {
-					log.Err(err).Msg("bot-move-failed")
-				}
-			}
-		}
-	})
-	nc.Flush()
-
-	if err := nc.LastError(); err != nil {
-		log.Fatal()
-	}
-	log.Info().Msgf("Listening on [%s]", channel)
 
 	runtime.Goexit()
 	fmt.Println("exiting")
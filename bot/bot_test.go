@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/external"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+)
+
+func TestThinkingTime(t *testing.T) {
+	is := is.New(t)
+	g := loadGame(is, "../gcgio/testdata/doug_v_emely_double_challenge.gcg")
+	g.PlayToTurn(10)
+	clock := bot.ThinkingTime(g, 10*time.Minute, 5*time.Second)
+	is.True(clock > 2*time.Second)
+
+	for _, tc := range []struct {
+		req  *pb.BotRequest
+		want time.Duration
+	}{
+		// The history has no clock.
+		{&pb.BotRequest{}, 0},
+		{&pb.BotRequest{MillisBudget: 3000}, 2800 * time.Millisecond},
+		{&pb.BotRequest{MillisBudget: 1000}, 900 * time.Millisecond},
+		{&pb.BotRequest{MillisRemaining: 600000, IncrementMillis: 5000}, clock},
+		{&pb.BotRequest{MillisRemaining: 600000, IncrementMillis: 5000, MillisBudget: 2000},
+			1800 * time.Millisecond},
+		{&pb.BotRequest{MillisRemaining: 600000, IncrementMillis: 5000, MillisBudget: 600000}, clock},
+	} {
+		is.Equal(thinkingTime(g, tc.req), tc.want)
+	}
+}
+
+// hangingEngine is an external engine that never answers.
+type hangingEngine struct{}
+
+func (hangingEngine) Analyze(ctx context.Context, g *game.Game, count int) ([]*move.Move, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (hangingEngine) AnalyzeRaw(ctx context.Context, g *game.Game, count int) ([]external.AnalyzeResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (hangingEngine) Close() error { return nil }
+
+func TestExternalAnalyzeHangs(t *testing.T) {
+	is := is.New(t)
+	g := loadGame(is, "../gcgio/testdata/doug_v_emely_double_challenge.gcg")
+	defer func(d time.Duration) { external.DefaultTimeout = d }(external.DefaultTimeout)
+	external.DefaultTimeout = 100 * time.Millisecond
+	b := &Bot{engine: hangingEngine{}}
+
+	// A request without a clock or a budget still gives up on the engine.
+	start := time.Now()
+	_, err := b.externalAnalyze(g, thinkingTime(g, &pb.BotRequest{}))
+	is.Equal(err, context.DeadlineExceeded)
+	is.True(time.Since(start) < time.Second)
+
+	_, err = b.externalAnalyze(g, 50*time.Millisecond)
+	is.Equal(err, context.DeadlineExceeded)
+}
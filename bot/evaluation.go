@@ -0,0 +1,304 @@
+package bot
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/endgame/alphabeta"
+	"github.com/domino14/macondo/equity"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/lexicon"
+	"github.com/domino14/macondo/montecarlo"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/movegen"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// Defaults for sim evaluations.
+const (
+	DefaultEvalSimPlies      = 2
+	DefaultEvalSimCandidates = 10
+	DefaultEvalSimIterations = 1000
+)
+
+// DefaultPhonyChallengeChance is how likely a phony is to be challenged
+// under each challenge rule, unless the request says otherwise. Phonies are
+// challenged more often when a challenge costs less. GCG files don't record
+// the rule, so VOID is treated like DOUBLE.
+var DefaultPhonyChallengeChance = map[pb.ChallengeRule]float64{
+	pb.ChallengeRule_VOID:       0.4,
+	pb.ChallengeRule_SINGLE:     0.8,
+	pb.ChallengeRule_FIVE_POINT: 0.6,
+	pb.ChallengeRule_TEN_POINT:  0.5,
+	pb.ChallengeRule_DOUBLE:     0.4,
+	pb.ChallengeRule_TRIPLE:     0.2,
+}
+
+// phonyChallengeChance returns the requested chance of a phony being
+// challenged, or the default for the rule.
+func phonyChallengeChance(rule pb.ChallengeRule, requested float64) float64 {
+	if requested > 0 {
+		return math.Min(requested, 1)
+	}
+	return DefaultPhonyChallengeChance[rule]
+}
+
+// Evaluate evaluates every play and exchange the user in the request made,
+// in order, and every opponent play they challenged or should have
+// challenged.
+func Evaluate(ctx context.Context, g *bot.BotTurnPlayer, req *pb.EvaluationRequest) (*pb.Evaluation, error) {
+	evals := []*pb.SingleEvaluation{}
+	for _, idx := range EvaluatedTurns(g.History(), req.User) {
+		var eval *pb.SingleEvaluation
+		var err error
+		if req.Type != pb.EvaluationRequest_SIM {
+			eval, err = EvaluateMove(g, idx, req)
+		} else {
+			eval, err = SimEvaluateMove(ctx, g, idx, req)
+		}
+		if err != nil {
+			return nil, err
+		}
+		evals = append(evals, eval)
+	}
+	challenges, err := EvaluateChallenges(g.Game, g.Lexicon(), req.User)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Evaluation{PlayEval: evals, ChallengeEval: challenges}, nil
+}
+
+func orDefault(n int32, def int) int {
+	if n > 0 {
+		return int(n)
+	}
+	return def
+}
+
+// SimEvaluateMove evaluates the move made in the event at evtIdx like
+// EvaluateMove, and also sims it against the best moves by static equity to
+// find how much win percentage it lost. Once the bag is empty it solves the
+// endgame exactly instead. Games with more than two players only get a
+// static evaluation. It leaves the game at that turn.
+func SimEvaluateMove(ctx context.Context, g *bot.BotTurnPlayer, evtIdx int,
+	req *pb.EvaluationRequest) (*pb.SingleEvaluation, error) {
+
+	eval, moves, played, err := staticEvaluation(g, evtIdx, req)
+	if err != nil {
+		return nil, err
+	}
+	if g.NumPlayers() != 2 {
+		return eval, nil
+	}
+	if g.Bag().TilesRemaining() == 0 {
+		return endgameEvaluation(ctx, g.Game, eval, played)
+	}
+
+	ncands := orDefault(req.SimCandidates, DefaultEvalSimCandidates)
+	if ncands > len(moves) {
+		ncands = len(moves)
+	}
+	cands := make([]*move.Move, ncands, ncands+2)
+	copy(cands, moves)
+	if played.idx < 0 || played.idx >= ncands {
+		cands = append(cands, played.m)
+	}
+	if played.pass != nil {
+		cands = append(cands, played.pass)
+	}
+
+	calc, err := equity.NewCombinedStaticCalculator(
+		g.LexiconName(), g.Config(), "", equity.PEGAdjustmentFilename, g.RackSize())
+	if err != nil {
+		return nil, err
+	}
+	simmer := &montecarlo.Simmer{}
+	simmer.Init(g.Game, []equity.EquityCalculator{calc}, calc, g.Config())
+	simmer.SetMaxIterations(orDefault(req.SimIterations, DefaultEvalSimIterations))
+	err = simmer.PrepareSim(orDefault(req.SimPlies, DefaultEvalSimPlies), cands)
+	if err != nil {
+		return nil, err
+	}
+	err = simmer.Simulate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plays := simmer.WinningPlays()
+	top := plays[0]
+	var playedWin, passWin float64
+	for _, sp := range plays {
+		switch sp.Move() {
+		case played.m:
+			playedWin = 100 * sp.WinProb()
+		case played.pass:
+			passWin = 100 * sp.WinProb()
+		}
+	}
+	eval.PlayedWinPct = playedWin
+	if played.pass != nil {
+		eval.PlayedWinPct = played.expected(playedWin, passWin)
+	}
+	eval.TopWinPct = 100 * top.WinProb()
+	eval.WinPctLoss = eval.TopWinPct - eval.PlayedWinPct
+	eval.TopMove = top.Move().Notation()
+	eval.SimIterations = int32(simmer.Iterations())
+	log.Debug().Int("turn", evtIdx).Str("top", eval.TopMove).
+		Float64("win-pct-loss", eval.WinPctLoss).Msg("sim-evaluation")
+	return eval, nil
+}
+
+// endgameEvaluation finds how much spread the played move lost by solving
+// the endgame both after the best move and after the played move.
+func endgameEvaluation(ctx context.Context, g *game.Game, eval *pb.SingleEvaluation,
+	played *playedMove) (*pb.SingleEvaluation, error) {
+
+	spread := g.SpreadFor(g.PlayerOnTurn())
+	plies := int(g.RackFor(0).NumTiles()) + int(g.RackFor(1).NumTiles())
+	bestVal, seq, err := solveEndgame(ctx, g, plies)
+	if err != nil {
+		return nil, err
+	}
+	playedVal := float64(bestVal)
+	if !seq[0].Equals(played.m, false, true) {
+		playedVal, err = endgameValue(ctx, g, played.m, plies)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if played.pass != nil {
+		passVal, err := endgameValue(ctx, g, played.pass, plies)
+		if err != nil {
+			return nil, err
+		}
+		playedVal = played.expected(playedVal, passVal)
+	}
+	eval.EndgameSolved = true
+	eval.EndgameSpreadLoss = int32(math.Round(float64(bestVal) - playedVal))
+	eval.TopWinPct = winPct(spread + int(math.Round(float64(bestVal))))
+	eval.PlayedWinPct = winPct(spread + int(math.Round(playedVal)))
+	eval.WinPctLoss = eval.TopWinPct - eval.PlayedWinPct
+	eval.TopMove = seq[0].Notation()
+	return eval, nil
+}
+
+// endgameValue is the change in spread for the player on turn if they make
+// move m and both players play perfectly afterwards.
+func endgameValue(ctx context.Context, g *game.Game, m *move.Move, plies int) (float64, error) {
+	user := g.PlayerOnTurn()
+	after := g.Copy()
+	after.SetBackupMode(game.SimulationMode)
+	after.SetStateStackLength(1)
+	err := after.PlayMove(m, false, 0)
+	if err != nil {
+		return 0, err
+	}
+	val := float64(after.SpreadFor(user) - g.SpreadFor(user))
+	if after.Playing() == pb.PlayState_PLAYING {
+		oppVal, _, err := solveEndgame(ctx, after, plies-1)
+		if err != nil {
+			return 0, err
+		}
+		val -= float64(oppVal)
+	}
+	return val, nil
+}
+
+// winPct is the win percentage of a game that ends with this spread.
+func winPct(finalSpread int) float64 {
+	switch {
+	case finalSpread > 0:
+		return 100
+	case finalSpread == 0:
+		return 50
+	}
+	return 0
+}
+
+// solveEndgame solves the endgame of a copy of the game, from the point of
+// view of the player on turn.
+func solveEndgame(ctx context.Context, g *game.Game, plies int) (float32, []*move.Move, error) {
+	gd, err := kwg.Get(g.Config(), g.LexiconName())
+	if err != nil {
+		return 0, nil, err
+	}
+	gameCopy := g.Copy()
+	gameCopy.SetBackupMode(game.SimulationMode)
+	gameCopy.SetStateStackLength(plies)
+	ld := g.Rules().LetterDistribution()
+	solver := &alphabeta.Solver{}
+	err = solver.Init(movegen.NewGordonGenerator(gd, gameCopy.Board(), ld),
+		movegen.NewGordonGenerator(gd, gameCopy.Board(), ld), gameCopy, g.Config())
+	if err != nil {
+		return 0, nil, err
+	}
+	return solver.Solve(ctx, plies)
+}
+
+// EvaluateChallenges looks at every play an opponent made right before the
+// user's turn, and evaluates the ones the user challenged or that formed
+// words not in lex. It leaves the game at the last turn it looked at.
+func EvaluateChallenges(g *game.Game, lex lexicon.Lexicon, user string) ([]*pb.ChallengeEvaluation, error) {
+	history := g.History()
+	evts := history.Events
+	nplayers := len(history.Players)
+	evals := []*pb.ChallengeEvaluation{}
+	for idx, evt := range evts {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+			continue
+		}
+		player := history.Players[evt.PlayerIndex].Nickname
+		next := history.Players[(int(evt.PlayerIndex)+1)%nplayers].Nickname
+		if strings.EqualFold(player, user) || !strings.EqualFold(next, user) {
+			continue
+		}
+		err := g.PlayToTurn(idx)
+		if err != nil {
+			return nil, err
+		}
+		m, err := game.MoveFromEvent(evt, g.Alphabet(), g.Board())
+		if err != nil {
+			return nil, err
+		}
+		words, err := g.Board().FormedWords(m)
+		if err != nil {
+			return nil, err
+		}
+		eval := &pb.ChallengeEvaluation{
+			EventIndex: int32(idx),
+			Play:       m.Notation(),
+			Challenged: challenged(evts, idx),
+		}
+		for _, w := range words {
+			word := w.UserVisible(g.Alphabet())
+			eval.WordsFormed = append(eval.WordsFormed, word)
+			if g.ValidateWords(lex, []tilemapping.MachineWord{w}) != nil {
+				eval.Phonies = append(eval.Phonies, word)
+			}
+		}
+		eval.MissedChallenge = len(eval.Phonies) > 0 && !eval.Challenged
+		eval.WrongfulChallenge = len(eval.Phonies) == 0 && eval.Challenged
+		if len(eval.Phonies) > 0 || eval.Challenged {
+			evals = append(evals, eval)
+		}
+	}
+	return evals, nil
+}
+
+// challenged returns whether the play in the event at idx was challenged.
+func challenged(evts []*pb.GameEvent, idx int) bool {
+	if idx+1 >= len(evts) {
+		return false
+	}
+	switch evts[idx+1].Type {
+	case pb.GameEvent_PHONY_TILES_RETURNED, pb.GameEvent_CHALLENGE_BONUS,
+		pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS, pb.GameEvent_CHALLENGE:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/gcgio"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/lexicon"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// fakeLexicon has every word but the phonies.
+type fakeLexicon struct {
+	lexicon.AcceptAll
+	phonies []string
+}
+
+func (l fakeLexicon) HasWord(word lexicon.Word) bool {
+	for _, p := range l.phonies {
+		if word.UserVisible(l.Alph) == p {
+			return false
+		}
+	}
+	return true
+}
+
+func loadGame(is *is.I, path string) *game.Game {
+	history, err := gcgio.ParseGCG(&DefaultConfig, path)
+	is.NoErr(err)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, game.VarClassic)
+	is.NoErr(err)
+	g, err := game.NewFromHistory(history, rules, 0)
+	is.NoErr(err)
+	return g
+}
+
+func TestEvaluateChallenges(t *testing.T) {
+	is := is.New(t)
+	g := loadGame(is, "../gcgio/testdata/doug_v_emely_double_challenge.gcg")
+	lex := fakeLexicon{
+		AcceptAll: lexicon.AcceptAll{Alph: g.Alphabet()},
+		phonies:   []string{"GALE", "TILAX"},
+	}
+
+	evals, err := EvaluateChallenges(g, lex, "doug")
+	is.NoErr(err)
+	is.Equal(len(evals), 2)
+	// doug let GALE stand.
+	is.Equal(evals[0].EventIndex, int32(1))
+	is.Equal(evals[0].Play, "7C GALE")
+	is.Equal(evals[0].Phonies, []string{"GALE"})
+	is.True(evals[0].MissedChallenge)
+	is.True(!evals[0].Challenged)
+	// and challenged TILAX off.
+	is.Equal(evals[1].EventIndex, int32(5))
+	is.Equal(evals[1].Phonies, []string{"TILAX"})
+	is.True(evals[1].Challenged)
+	is.True(!evals[1].MissedChallenge)
+	is.True(!evals[1].WrongfulChallenge)
+}
+
+func TestEvaluateWrongfulChallenge(t *testing.T) {
+	is := is.New(t)
+	g := loadGame(is, "../gcgio/testdata/doug_v_emely_double_challenge.gcg")
+	lex := lexicon.AcceptAll{Alph: g.Alphabet()}
+
+	evals, err := EvaluateChallenges(g, lex, "doug")
+	is.NoErr(err)
+	is.Equal(len(evals), 1)
+	is.Equal(evals[0].EventIndex, int32(5))
+	is.True(evals[0].Challenged)
+	is.True(evals[0].WrongfulChallenge)
+	is.Equal(len(evals[0].Phonies), 0)
+
+	// emely never challenged anything, and doug played no phonies.
+	evals, err = EvaluateChallenges(g, lex, "emely")
+	is.NoErr(err)
+	is.Equal(len(evals), 0)
+}
+
+func TestPhonyChallengeChance(t *testing.T) {
+	is := is.New(t)
+	is.Equal(phonyChallengeChance(pb.ChallengeRule_SINGLE, 0), 0.8)
+	is.Equal(phonyChallengeChance(pb.ChallengeRule_VOID, 0),
+		phonyChallengeChance(pb.ChallengeRule_DOUBLE, 0))
+	is.Equal(phonyChallengeChance(pb.ChallengeRule_DOUBLE, 0.75), 0.75)
+	is.Equal(phonyChallengeChance(pb.ChallengeRule_DOUBLE, 3), 1.0)
+
+	p := &playedMove{chance: 0.25}
+	is.Equal(p.expected(40, 0), 30.0)
+}
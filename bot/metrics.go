@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency
+// histogram.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15, 20, 30, 60}
+
+// histogram is a Prometheus histogram. counts[i] is the number of
+// observations no larger than latencyBuckets[i], and is not cumulative.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(latencyBuckets, v)
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+// metrics counts the requests the bot service handles. It writes them in
+// the Prometheus text format, so that there is no need for a client
+// library.
+type metrics struct {
+	sync.Mutex
+	requests map[string]uint64
+	errors   map[string]uint64
+	latency  map[string]*histogram
+	inFlight int
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: map[string]uint64{},
+		errors:   map[string]uint64{},
+		latency:  map[string]*histogram{},
+	}
+}
+
+// start records the start of a request by the given kind of bot. It
+// returns a function to call once the request is answered.
+func (m *metrics) start(botType string) func(failed bool) {
+	m.Lock()
+	m.inFlight++
+	m.Unlock()
+	t := time.Now()
+	return func(failed bool) {
+		elapsed := time.Since(t).Seconds()
+		m.Lock()
+		defer m.Unlock()
+		m.inFlight--
+		m.requests[botType]++
+		if failed {
+			m.errors[botType]++
+		}
+		h, ok := m.latency[botType]
+		if !ok {
+			h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+			m.latency[botType] = h
+		}
+		h.observe(elapsed)
+	}
+}
+
+func (m *metrics) write(w io.Writer) {
+	m.Lock()
+	defer m.Unlock()
+	writeCounter(w, "macondo_bot_requests_total", "Requests answered, by bot type.", m.requests)
+	writeCounter(w, "macondo_bot_request_errors_total", "Requests answered with an error, by bot type.", m.errors)
+
+	name := "macondo_bot_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Time taken to answer requests, by bot type.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, bt := range sortedKeys(m.latency) {
+		h := m.latency[bt]
+		cumulative := uint64(0)
+		for i, le := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{bot_type=%q,le=%q} %d\n", name, bt,
+				strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{bot_type=%q,le=\"+Inf\"} %d\n", name, bt, h.count)
+		fmt.Fprintf(w, "%s_sum{bot_type=%q} %g\n", name, bt, h.sum)
+		fmt.Fprintf(w, "%s_count{bot_type=%q} %d\n", name, bt, h.count)
+	}
+
+	name = "macondo_bot_requests_in_flight"
+	fmt.Fprintf(w, "# HELP %s Requests being answered.\n", name)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, m.inFlight)
+}
+
+func writeCounter(w io.Writer, name, help string, counts map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, bt := range sortedKeys(counts) {
+		fmt.Fprintf(w, "%s{bot_type=%q} %d\n", name, bt, counts[bt])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+// drainPollInterval is how often Shutdown checks whether the subscription
+// has finished draining.
+const drainPollInterval = 50 * time.Millisecond
+
+var errNotStarted = errors.New("service was not started")
+
+// handler answers bot requests. *Bot is the only real one.
+type handler interface {
+	handle(req *pb.BotRequest) *pb.BotResponse
+}
+
+// Service listens for bot requests on a NATS channel. It also serves, over
+// HTTP, health and readiness checks and metrics in the Prometheus text
+// format:
+//
+//	/healthz  200 as long as the process is up
+//	/readyz   200 while the service takes requests, 503 otherwise
+//	/metrics  request counts, errors and latencies by bot type, and the
+//	          number of requests in flight
+type Service struct {
+	natsURL string
+	channel string
+	h       handler
+
+	nc      *nats.Conn
+	sub     *nats.Subscription
+	ready   atomic.Bool
+	flight  sync.WaitGroup
+	metrics *metrics
+	mux     *http.ServeMux
+}
+
+// NewService creates a service for the bot that listens on channel once it
+// is started.
+func NewService(channel string, b *Bot) (*Service, error) {
+	err := b.newGame()
+	if err != nil {
+		return nil, err
+	}
+	return newService(b.config.NatsURL, channel, b), nil
+}
+
+func newService(natsURL, channel string, h handler) *Service {
+	s := &Service{
+		natsURL: natsURL,
+		channel: channel,
+		h:       h,
+		metrics: newMetrics(),
+		mux:     http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	s.mux.HandleFunc("/readyz", s.readyz)
+	s.mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.metrics.write(w)
+	})
+	return s
+}
+
+// Start connects to NATS and starts taking requests.
+func (s *Service) Start() error {
+	nc, err := nats.Connect(s.natsURL)
+	if err != nil {
+		return err
+	}
+	s.nc = nc
+	// A user of the bot should send the data to only one bot instance.
+	// Using a QueueSubscribe guarantees that only one listening bot will
+	// receive a message.
+	s.sub, err = nc.QueueSubscribe(s.channel, "bot_queue", s.onMessage)
+	if err != nil {
+		nc.Close()
+		return err
+	}
+	err = nc.Flush()
+	if err != nil {
+		nc.Close()
+		return err
+	}
+	s.ready.Store(true)
+	log.Info().Msgf("Listening on [%s]", s.channel)
+	return nil
+}
+
+// Shutdown stops taking new requests and waits for the ones already
+// received to be answered, or for ctx to be done, before closing the
+// connection to NATS.
+func (s *Service) Shutdown(ctx context.Context) error {
+	if s.nc == nil {
+		return errNotStarted
+	}
+	s.ready.Store(false)
+	defer s.nc.Close()
+	err := s.sub.Drain()
+	if err != nil {
+		return err
+	}
+	// The subscription stops being valid once every message it received
+	// was handed to onMessage.
+	t := time.NewTicker(drainPollInterval)
+	defer t.Stop()
+	for s.sub.IsValid() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		s.flight.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+	}
+	log.Info().Msg("bot-service-drained")
+	return nil
+}
+
+// ServeHTTP serves the health checks and metrics.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Service) readyz(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() || !s.nc.IsConnected() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+func (s *Service) onMessage(m *nats.Msg) {
+	s.flight.Add(1)
+	defer s.flight.Done()
+	log.Info().Str("replyChannel", m.Reply).Msgf("RECV: %d bytes", len(m.Data))
+
+	var resp *pb.BotResponse
+	req := &pb.BotRequest{}
+	err := proto.Unmarshal(m.Data, req)
+	if err != nil {
+		done := s.metrics.start("UNKNOWN")
+		resp = errorResponse("Could not parse request", err)
+		done(true)
+	} else {
+		done := s.metrics.start(req.BotType.String())
+		resp = s.h.handle(req)
+		done(resp.GetError() != "")
+	}
+	s.respond(m, resp)
+}
+
+func (s *Service) respond(m *nats.Msg, resp *pb.BotResponse) {
+	// debugWriteln(proto.MarshalTextString(resp))
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		// Should never happen, ideally, but we need to do something sensible here.
+		m.Respond([]byte(err.Error()))
+		return
+	}
+	if m.Reply != "" {
+		err = m.Respond(data)
+		if err != nil {
+			log.Err(err).Msg("error-responding")
+		}
+		return
+	}
+	err = retry.Do(
+		func() error {
+			_, err := s.nc.Request(
+				"bot.publish_event."+resp.GameId, data, 3*time.Second)
+			if err != nil {
+				return err
+			}
+			// We're just waiting for an acknowledgement. The actual
+			// data doesn't matter.
+			return nil
+		},
+		retry.DelayType(func(n uint, err error, config *retry.Config) time.Duration {
+			log.Err(err).Uint("n", n).Str("gameID", resp.GameId).
+				Msg("did-not-receive-ack-try-again")
+			return retry.BackOffDelay(n, err, config)
+		}),
+	)
+	if err != nil {
+		log.Err(err).Msg("bot-move-failed")
+	}
+}
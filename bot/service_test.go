@@ -0,0 +1,159 @@
+package bot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+// fakeHandler answers every request with a pass, once it is released, or
+// with an error if the request has no game.
+type fakeHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeHandler) handle(req *pb.BotRequest) *pb.BotResponse {
+	if req.GameHistory == nil {
+		return errorResponse("Could not parse request", nil)
+	}
+	f.started <- struct{}{}
+	<-f.release
+	return &pb.BotResponse{
+		Response: &pb.BotResponse_Move{Move: &pb.GameEvent{Type: pb.GameEvent_PASS}},
+		GameId:   req.GameHistory.Uid,
+	}
+}
+
+func startService(t *testing.T) (*Service, *fakeHandler, *nats.Conn) {
+	is := is.New(t)
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	ns := natsserver.RunServer(&opts)
+	t.Cleanup(ns.Shutdown)
+
+	h := &fakeHandler{started: make(chan struct{}, 10), release: make(chan struct{})}
+	s := newService(ns.ClientURL(), "bot.commands", h)
+	is.NoErr(s.Start())
+	nc, err := nats.Connect(ns.ClientURL())
+	is.NoErr(err)
+	t.Cleanup(nc.Close)
+	return s, h, nc
+}
+
+func request(uid string, botType pb.BotRequest_BotCode) []byte {
+	req := &pb.BotRequest{BotType: botType}
+	if uid != "" {
+		req.GameHistory = &pb.GameHistory{Uid: uid}
+	}
+	data, _ := proto.Marshal(req)
+	return data
+}
+
+func get(t *testing.T, s *Service, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestServiceMetrics(t *testing.T) {
+	is := is.New(t)
+	s, h, nc := startService(t)
+	defer s.Shutdown(context.Background())
+
+	code, _ := get(t, s, "/healthz")
+	is.Equal(code, http.StatusOK)
+	code, _ = get(t, s, "/readyz")
+	is.Equal(code, http.StatusOK)
+
+	close(h.release)
+	msg, err := nc.Request("bot.commands", request("game1", pb.BotRequest_LEVEL3_PROBABILISTIC), time.Second)
+	is.NoErr(err)
+	resp := &pb.BotResponse{}
+	is.NoErr(proto.Unmarshal(msg.Data, resp))
+	is.Equal(resp.GameId, "game1")
+
+	_, err = nc.Request("bot.commands", request("", pb.BotRequest_HASTY_BOT), time.Second)
+	is.NoErr(err)
+	_, err = nc.Request("bot.commands", []byte("not a request"), time.Second)
+	is.NoErr(err)
+
+	code, body := get(t, s, "/metrics")
+	is.Equal(code, http.StatusOK)
+	for _, line := range []string{
+		`macondo_bot_requests_total{bot_type="HASTY_BOT"} 1`,
+		`macondo_bot_requests_total{bot_type="LEVEL3_PROBABILISTIC"} 1`,
+		`macondo_bot_requests_total{bot_type="UNKNOWN"} 1`,
+		`macondo_bot_request_errors_total{bot_type="HASTY_BOT"} 1`,
+		`macondo_bot_request_errors_total{bot_type="UNKNOWN"} 1`,
+		`macondo_bot_request_duration_seconds_bucket{bot_type="LEVEL3_PROBABILISTIC",le="+Inf"} 1`,
+		`macondo_bot_request_duration_seconds_count{bot_type="LEVEL3_PROBABILISTIC"} 1`,
+		`macondo_bot_requests_in_flight 0`,
+	} {
+		is.True(strings.Contains(body, line+"\n")) // metric is missing
+	}
+	is.True(!strings.Contains(body, `macondo_bot_request_errors_total{bot_type="LEVEL3_PROBABILISTIC"}`))
+}
+
+func TestServiceDrain(t *testing.T) {
+	is := is.New(t)
+	s, h, nc := startService(t)
+
+	reply := make(chan *nats.Msg, 1)
+	go func() {
+		msg, err := nc.Request("bot.commands", request("game1", pb.BotRequest_HASTY_BOT), 5*time.Second)
+		if err != nil {
+			t.Error(err)
+		}
+		reply <- msg
+	}()
+	<-h.started
+	_, body := get(t, s, "/metrics")
+	is.True(strings.Contains(body, "macondo_bot_requests_in_flight 1\n"))
+
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- s.Shutdown(context.Background())
+	}()
+	// The service stops being ready right away, but waits for the move it
+	// is working on.
+	time.Sleep(100 * time.Millisecond)
+	code, _ := get(t, s, "/readyz")
+	is.Equal(code, http.StatusServiceUnavailable)
+	select {
+	case <-shutdown:
+		t.Fatal("shut down before answering")
+	default:
+	}
+
+	close(h.release)
+	is.NoErr(<-shutdown)
+	msg := <-reply
+	is.True(msg != nil)
+	code, _ = get(t, s, "/healthz")
+	is.Equal(code, http.StatusOK)
+}
+
+func TestServiceShutdownTimeout(t *testing.T) {
+	is := is.New(t)
+	s, h, nc := startService(t)
+	defer close(h.release)
+
+	nc.Publish("bot.commands", request("game1", pb.BotRequest_HASTY_BOT))
+	<-h.started
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	is.Equal(s.Shutdown(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,101 @@
+package cgp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// ToCGP returns the position in g as a CGP string. Racks and scores start
+// with the player on turn. If opponentRacks is false, only the rack of the
+// player on turn is given, as in a puzzle. Operations are only added for
+// what differs from ParseCGP's defaults, besides the lexicon.
+func ToCGP(g *game.Game, opponentRacks bool) string {
+	var sb strings.Builder
+	tm := g.Alphabet()
+	bd := g.Board()
+	for r := 0; r < bd.Dim(); r++ {
+		if r > 0 {
+			sb.WriteByte('/')
+		}
+		empty := 0
+		for c := 0; c < bd.Dim(); c++ {
+			ml := bd.GetLetter(r, c)
+			if ml == 0 {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteString(tileString(ml, tm))
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	racks := make([]string, g.NumPlayers())
+	scores := make([]string, g.NumPlayers())
+	for i := range racks {
+		pidx := (g.PlayerOnTurn() + i) % g.NumPlayers()
+		if i == 0 || opponentRacks {
+			var rack strings.Builder
+			for _, ml := range g.RackFor(pidx).TilesOn() {
+				rack.WriteString(tileString(ml, tm))
+			}
+			racks[i] = rack.String()
+		}
+		scores[i] = strconv.Itoa(g.PointsFor(pidx))
+	}
+	fmt.Fprintf(&sb, " %s %s %d", strings.Join(racks, "/"), strings.Join(scores, "/"),
+		g.ScorelessTurns())
+
+	ops := []string{}
+	rules := g.Rules()
+	if lex := g.LexiconName(); lex != "" {
+		ops = append(ops, "lex "+lex)
+	}
+	if name := rules.LetterDistributionName(); name != "" && !strings.EqualFold(name, "english") {
+		ops = append(ops, "ld "+name)
+	}
+	if name := rules.BoardName(); name != "" && name != board.CrosswordGameLayout {
+		ops = append(ops, "bdn "+name)
+	}
+	if v := rules.Variant(); v != "" && v != game.VarClassic {
+		ops = append(ops, "var "+string(v))
+	}
+	if n := rules.RackSize(); n != game.RackTileLimit {
+		ops = append(ops, "rs "+strconv.Itoa(n))
+	}
+	if n := rules.BingoBonus(); n != game.DefaultBingoBonus {
+		ops = append(ops, "bb "+strconv.Itoa(n))
+	}
+	if n := rules.ExchangeLimit(); n != rules.RackSize() {
+		ops = append(ops, "etl "+strconv.Itoa(n))
+	}
+	if gid := g.Uid(); gid != "" {
+		ops = append(ops, "gid "+gid)
+	}
+	for _, op := range ops {
+		fmt.Fprintf(&sb, " %s;", op)
+	}
+	return sb.String()
+}
+
+// tileString returns a tile as CGP writes it: blanks on the board are
+// lowercase, blanks on racks are ?, and tiles of more than one codepoint
+// are bracketed.
+func tileString(ml tilemapping.MachineLetter, tm *tilemapping.TileMapping) string {
+	s := ml.UserVisible(tm, false)
+	if utf8.RuneCountInString(s) > 1 {
+		return "[" + s + "]"
+	}
+	return s
+}
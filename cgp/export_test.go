@@ -0,0 +1,43 @@
+package cgp
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func TestToCGP(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, game.VarClassic)
+	is.NoErr(err)
+	is.NoErr(rules.SetBingoBonus(35))
+	g, err := game.NewGame(rules, []*pb.PlayerInfo{
+		{Nickname: "p1", RealName: "Player 1"},
+		{Nickname: "p2", RealName: "Player 2"},
+	})
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	is.NoErr(g.SetRackFor(0, tilemapping.RackFromString("?EINRST", g.Alphabet())))
+	m, err := g.CreateAndScorePlacementMove("8D", "STaIR", "?EINRST")
+	is.NoErr(err)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.NoErr(g.SetRacksForBoth([]*tilemapping.Rack{
+		tilemapping.RackFromString("?EINQST", g.Alphabet()),
+		tilemapping.RackFromString("AEGLNOT", g.Alphabet()),
+	}))
+	g.History().Uid = "abc"
+
+	board := "15/15/15/15/15/15/15/3STaIR7/15/15/15/15/15/15/15"
+	is.Equal(ToCGP(g, false), board+" AEGLNOT/ 0/10 0 lex AcceptAll; bb 35; gid abc;")
+	is.Equal(ToCGP(g, true), board+" AEGLNOT/?EINQST 0/10 0 lex AcceptAll; bb 35; gid abc;")
+}
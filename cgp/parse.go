@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/domino14/macondo/config"
 	"github.com/domino14/macondo/game"
@@ -38,8 +39,8 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 	if len(playerRacks) != len(playerScores) {
 		return nil, errors.New("player racks and scores do not match")
 	}
-	if len(playerRacks) != 2 {
-		return nil, errors.New("only 2-player games are supported at the moment")
+	if len(playerRacks) < 2 || len(playerRacks) > game.MaxPlayers {
+		return nil, fmt.Errorf("only games with 2 to %d players are supported", game.MaxPlayers)
 	}
 	scores := make([]int, len(playerScores))
 	for i, s := range playerScores {
@@ -67,6 +68,11 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 	maxScorelessTurns := game.DefaultMaxScorelessTurns
 	variant := game.VarClassic
 	gid := ""
+	rackSize := game.RackTileLimit
+	bingoBonus := game.DefaultBingoBonus
+	exchangeLimit := 0
+	var timers []time.Duration
+	var increment, maxOvertime time.Duration
 
 	for _, op := range ops {
 		op := strings.TrimSpace(op)
@@ -75,11 +81,27 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 		}
 		opWithParams := strings.SplitN(op, " ", 2)
 		switch opWithParams[0] {
+		case "bb":
+			if len(opWithParams) != 2 {
+				return nil, errors.New("wrong number of arguments for bb operation")
+			}
+			bingoBonus, err = strconv.Atoi(opWithParams[1])
+			if err != nil {
+				return nil, err
+			}
 		case "bdn":
 			if len(opWithParams) != 2 {
 				return nil, errors.New("wrong number of arguments for bdn operation")
 			}
 			boardLayoutName = opWithParams[1]
+		case "etl":
+			if len(opWithParams) != 2 {
+				return nil, errors.New("wrong number of arguments for etl operation")
+			}
+			exchangeLimit, err = strconv.Atoi(opWithParams[1])
+			if err != nil {
+				return nil, err
+			}
 		case "gid":
 			if len(opWithParams) != 2 {
 				return nil, errors.New("wrong number of arguments for gid operation")
@@ -107,6 +129,49 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 				return nil, err
 			}
 
+		case "rs":
+			if len(opWithParams) != 2 {
+				return nil, errors.New("wrong number of arguments for rs operation")
+			}
+			rackSize, err = strconv.Atoi(opWithParams[1])
+			if err != nil {
+				return nil, err
+			}
+
+		case "ti":
+			if len(opWithParams) != 2 {
+				return nil, errors.New("wrong number of arguments for ti operation")
+			}
+			increment, err = parseMillis(opWithParams[1])
+			if err != nil {
+				return nil, err
+			}
+
+		case "tmr":
+			if len(opWithParams) != 2 {
+				return nil, errors.New("wrong number of arguments for tmr operation")
+			}
+			ts := strings.Split(opWithParams[1], "/")
+			if len(ts) != len(playerRacks) {
+				return nil, errors.New("timers and racks do not match")
+			}
+			timers = make([]time.Duration, len(ts))
+			for i, t := range ts {
+				timers[i], err = parseMillis(t)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+		case "to":
+			if len(opWithParams) != 2 {
+				return nil, errors.New("wrong number of arguments for to operation")
+			}
+			maxOvertime, err = parseMillis(opWithParams[1])
+			if err != nil {
+				return nil, err
+			}
+
 		case "var":
 			if len(opWithParams) != 2 {
 				return nil, errors.New("wrong number of arguments for var operation")
@@ -120,6 +185,33 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = rules.SetRackSize(rackSize); err != nil {
+		return nil, err
+	}
+	if err = rules.SetBingoBonus(bingoBonus); err != nil {
+		return nil, err
+	}
+	if exchangeLimit != 0 {
+		if err = rules.SetExchangeLimit(exchangeLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	if timers != nil {
+		// The position doesn't say how much time the players started
+		// with; use the most anyone has left.
+		initial := time.Millisecond
+		for _, t := range timers {
+			if t > initial {
+				initial = t
+			}
+		}
+		tc := game.NewTimeControl(initial, increment)
+		tc.MaxOvertime = maxOvertime
+		if err = rules.SetTimeControl(tc); err != nil {
+			return nil, err
+		}
+	}
 
 	// "Decompress" the gameboard letters.
 	fullRows := make([]string, len(rows))
@@ -144,6 +236,11 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 	}
 	g.SetMaxScorelessTurns(maxScorelessTurns)
 	g.SetScorelessTurns(nzero)
+	// The clock is left stopped; it only records the time each player
+	// has left.
+	for i, t := range timers {
+		g.Clock().SetRemaining(i, t)
+	}
 	g.History().StartingCgp = cgpstr
 	g.History().Uid = gid
 	g.History().IdAuth = "" //  maybe provide this later, id
@@ -152,6 +249,14 @@ func ParseCGP(cfg *config.Config, cgpstr string) (*game.Game, error) {
 	return g, nil
 }
 
+func parseMillis(s string) (time.Duration, error) {
+	ms, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
 func rowToLetters(row string) (string, error) {
 	// turn row into letters
 	var letters strings.Builder
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -53,20 +56,44 @@ func main() {
 	} else {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
-	idleConnsClosed := make(chan struct{})
-	sig := make(chan os.Signal, 1)
-	go func() {
-		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
-		<-sig
-		// We received an interrupt signal, shut down.
-		log.Info().Msg("got quit signal...")
-		close(idleConnsClosed)
-	}()
-
 	opts := &turnplayer.GameOptions{}
 	b := bot.NewBot(cfg, opts)
-	go bot.Main("bot.commands", b)
+	s, err := bot.NewService("bot.commands", b)
+	if err != nil {
+		log.Fatal().Err(err).Msg("bot-create-error")
+	}
+	err = s.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("nats-connect-error")
+	}
 
-	<-idleConnsClosed
+	var srv *http.Server
+	if cfg.BotHTTPAddr != "" {
+		srv = &http.Server{Addr: cfg.BotHTTPAddr, Handler: s}
+		go func() {
+			log.Info().Str("addr", cfg.BotHTTPAddr).Msg("bot-http-listening")
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal().Err(err).Msg("bot-http-listen-error")
+			}
+		}()
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+	// We received an interrupt signal, shut down.
+	log.Info().Msg("got quit signal...")
+	ctx, cancel := context.WithTimeout(context.Background(), GracefulShutdownTimeout)
+	defer cancel()
+	// Finish the moves the bot is already thinking about before going away.
+	// The health checks keep answering until then, with readyz failing.
+	if err := s.Shutdown(ctx); err != nil {
+		log.Err(err).Msg("bot-shutdown-error")
+	}
+	if srv != nil {
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Err(err).Msg("bot-http-shutdown-error")
+		}
+	}
 	log.Info().Msg("server gracefully shutting down")
 }
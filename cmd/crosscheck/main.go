@@ -0,0 +1,111 @@
+// The crosscheck command compares the moves an external engine finds with
+// the moves macondo's generator finds. It reads positions in CGP format
+// from standard input, one per line; blank lines and lines starting with #
+// are skipped. It takes the same flags as the bot, such as
+// -external-engine-url or -external-engine-cmd, and exits with status 1 if
+// the engine and macondo disagree anywhere.
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/domino14/macondo/cgp"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/external"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/movegen"
+)
+
+// allMoves is how many moves to ask the engine for, so that it returns all
+// of them.
+const allMoves = 1000000
+
+func main() {
+	ex, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.Load(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	cfg.AdjustRelativePaths(filepath.Dir(ex))
+	if cfg.Debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	}
+
+	eng, err := external.New(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer eng.Close()
+
+	positions, failed := 0, 0
+	scanner := bufio.NewScanner(os.Stdin)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		positions++
+		res, err := crossCheck(cfg, eng, line)
+		if err != nil {
+			fmt.Printf("line %d: %v\n", lineno, err)
+			failed++
+			continue
+		}
+		if !res.OK() {
+			fmt.Printf("line %d: %s\n", lineno, line)
+			res.Write(os.Stdout, "  ")
+			failed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fmt.Printf("%d positions, %d with differences\n", positions, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func crossCheck(cfg *config.Config, eng external.ExternalEngine, cgpstr string) (*external.CrossCheckResult, error) {
+	g, err := cgp.ParseCGP(cfg, cgpstr)
+	if err != nil {
+		return nil, err
+	}
+	ours, err := generate(cfg, g)
+	if err != nil {
+		return nil, err
+	}
+	resps, err := eng.AnalyzeRaw(context.Background(), g, allMoves)
+	if err != nil {
+		return nil, err
+	}
+	return external.CrossCheck(g, resps, ours, len(resps) < allMoves), nil
+}
+
+// generate returns every move macondo's generator finds for the player on
+// turn.
+func generate(cfg *config.Config, g *game.Game) ([]*move.Move, error) {
+	gd, err := kwg.Get(cfg, g.LexiconName())
+	if err != nil {
+		return nil, err
+	}
+	gen := movegen.NewGordonGenerator(gd, g.Board(), g.Bag().LetterDistribution())
+	gen.GenAll(g.RackFor(g.PlayerOnTurn()), g.Bag().TilesRemaining() >= g.ExchangeLimit())
+	return gen.Plays(), nil
+}
@@ -0,0 +1,41 @@
+// The engine command speaks the macondo engine protocol over standard input
+// and output, for use by graphical front-ends. Logs go to standard error.
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/engine"
+)
+
+func main() {
+	// Determine the directory of the executable. We will use this
+	// directory to find the data files if an absolute path is not
+	// provided for these!
+	ex, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	exPath := filepath.Dir(ex)
+
+	cfg := &config.Config{}
+	cfg.Load(os.Args[1:])
+	cfg.AdjustRelativePaths(exPath)
+
+	if cfg.Debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	}
+
+	e := engine.New(cfg, os.Stdout)
+	if err := e.Run(os.Stdin); err != nil {
+		log.Err(err).Msg("engine-exiting")
+		os.Exit(1)
+	}
+}
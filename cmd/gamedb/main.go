@@ -0,0 +1,171 @@
+// The gamedb command keeps a database of games in a directory. It imports
+// GCG files into it, lists the games that match a query, and sums them up
+// into stats.
+//
+// Usage:
+//
+//	gamedb [-db dir] import <file or directory>...
+//	gamedb [-db dir] find [query flags]
+//	gamedb [-db dir] stats [query flags]
+//
+// Data files are found through the same environment variables as the
+// shell's flags, such as DATA_PATH.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/gamedb"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	dbDir := flag.String("db", "gamedb", "directory of the database")
+	debug := flag.Bool("debug", false, "debug logging on")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: %s [flags] import <file or directory>...\n", os.Args[0])
+		fmt.Fprintf(out, "       %s [flags] find [query flags]\n", os.Args[0])
+		fmt.Fprintf(out, "       %s [flags] stats [query flags]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	}
+
+	db, err := gamedb.Open(*dbDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	args := flag.Args()[1:]
+	switch flag.Arg(0) {
+	case "import":
+		err = importGames(db, args)
+	case "find":
+		var q gamedb.Query
+		if q, err = parseQuery("find", args); err == nil {
+			writeGames(os.Stdout, db.Find(q))
+		}
+	case "stats":
+		var q gamedb.Query
+		if q, err = parseQuery("stats", args); err == nil {
+			writeStats(os.Stdout, db.Stats(q))
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func importGames(db *gamedb.DB, paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no files or directories to import")
+	}
+	ex, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cfg := &config.Config{}
+	if err := cfg.Load(nil); err != nil {
+		return err
+	}
+	cfg.AdjustRelativePaths(filepath.Dir(ex))
+
+	for _, path := range paths {
+		res, err := db.Import(cfg, path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v: %d games added, %d already in the database, %d failed, %d warnings\n",
+			path, res.Added, res.Duplicates, len(res.Failed), res.Warnings)
+		failed := make([]string, 0, len(res.Failed))
+		for f := range res.Failed {
+			failed = append(failed, f)
+		}
+		sort.Strings(failed)
+		for _, f := range failed {
+			fmt.Printf("  %v: %v\n", f, res.Failed[f])
+		}
+	}
+	fmt.Printf("%d games in the database\n", db.Len())
+	return nil
+}
+
+func parseQuery(name string, args []string) (gamedb.Query, error) {
+	var q gamedb.Query
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&q.Player, "player", "", "nickname or real name of a player")
+	fs.StringVar(&q.Lexicon, "lexicon", "", "lexicon, such as CSW21")
+	fs.StringVar(&q.Variant, "variant", "", "variant, such as classic or wordsmog")
+	from := fs.String("from", "", "earliest date played, as YYYY-MM-DD")
+	to := fs.String("to", "", "latest date played, as YYYY-MM-DD")
+	if err := fs.Parse(args); err != nil {
+		return q, err
+	}
+	var err error
+	if *from != "" {
+		if q.From, err = time.ParseInLocation(dateLayout, *from, time.Local); err != nil {
+			return q, err
+		}
+	}
+	if *to != "" {
+		if q.To, err = time.ParseInLocation(dateLayout, *to, time.Local); err != nil {
+			return q, err
+		}
+		// Take in the whole day.
+		q.To = q.To.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return q, nil
+}
+
+func writeGames(w io.Writer, entries []*gamedb.Entry) {
+	for _, e := range entries {
+		players := make([]string, len(e.Players))
+		for i, p := range e.Players {
+			players[i] = p.Nickname
+			if e.GameOver {
+				players[i] += fmt.Sprintf(" %d", e.FinalScores[i])
+			}
+		}
+		result := strings.Join(players, " - ")
+		if !e.GameOver {
+			result += " (unfinished)"
+		}
+		fmt.Fprintf(w, "%v %-8v %-8v %v  [%v]\n", e.Date.Format(dateLayout), e.Lexicon, e.Variant,
+			result, e.ID)
+	}
+	fmt.Fprintf(w, "%d games\n", len(entries))
+}
+
+func writeStats(w io.Writer, s *gamedb.Stats) {
+	fmt.Fprintf(w, "Games:                  %d\n", s.Games)
+	fmt.Fprintf(w, "Wins-losses-ties:       %d-%d-%d (%.1f%%)\n", s.Wins, s.Losses, s.Ties, 100*s.WinRate())
+	fmt.Fprintf(w, "Average score:          %.1f\n", s.AverageScore())
+	fmt.Fprintf(w, "Average opponent score: %.1f\n", s.AverageOpponentScore())
+	fmt.Fprintf(w, "Bingos per game:        %.2f\n", s.BingosPerGame())
+	fmt.Fprintf(w, "Win %% going first:      %.1f%% of %d\n", 100*s.FirstWinRate(), s.FirstSeats)
+	fmt.Fprintf(w, "Win %% not going first:  %.1f%% of %d\n", 100*s.SecondWinRate(), s.Seats-s.FirstSeats)
+}
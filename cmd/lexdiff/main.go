@@ -0,0 +1,157 @@
+// The lexdiff command compares two lexicons: it counts the words added and
+// removed for each word length, lists the short words that changed, since
+// they matter most to leave values, and says which leaves each lexicon
+// uses.
+//
+// Usage:
+//
+//	lexdiff [flags] <old> <new>
+//
+// Lexicons are KWG files, or the names of lexicons in the lexicon path,
+// such as NWL20 or CSW21. Data files are found through the same environment
+// variables as the shell's flags, such as DATA_PATH.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/equity"
+	"github.com/domino14/macondo/kwg"
+)
+
+func main() {
+	out := flag.String("o", "", "file to write every added and removed word to, as +WORD or -WORD")
+	short := flag.Int("short", 3, "list the added and removed words up to this length")
+	debug := flag.Bool("debug", false, "debug logging on")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <old> <new>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	}
+
+	ex, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.Load(nil); err != nil {
+		os.Exit(2)
+	}
+	cfg.AdjustRelativePaths(filepath.Dir(ex))
+
+	oldLex, err := load(cfg, flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	newLex, err := load(cfg, flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	d, err := kwg.Diff(oldLex, newLex)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	report(os.Stdout, cfg, oldLex.LexiconName(), newLex.LexiconName(), d, *short)
+
+	if *out != "" {
+		if err := writeChanges(*out, d); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// load loads a lexicon from a KWG file, or by name.
+func load(cfg *config.Config, lex string) (*kwg.KWG, error) {
+	if strings.HasSuffix(strings.ToLower(lex), ".kwg") {
+		return kwg.LoadKWG(cfg, lex)
+	}
+	return kwg.Get(cfg, lex)
+}
+
+func report(w io.Writer, cfg *config.Config, oldName, newName string, d *kwg.LexiconDiff, short int) {
+	fmt.Fprintf(w, "%s -> %s\n\n", oldName, newName)
+	fmt.Fprintf(w, "%6s %10s %10s %8s %8s\n", "Length", oldName, newName, "Added", "Removed")
+	var oldTotal, newTotal, added, removed int
+	for _, n := range d.Lengths() {
+		fmt.Fprintf(w, "%6d %10d %10d %8d %8d\n", n, d.OldCounts[n], d.NewCounts[n],
+			len(d.Added[n]), len(d.Removed[n]))
+		oldTotal += d.OldCounts[n]
+		newTotal += d.NewCounts[n]
+		added += len(d.Added[n])
+		removed += len(d.Removed[n])
+	}
+	fmt.Fprintf(w, "%6s %10d %10d %8d %8d\n", "Total", oldTotal, newTotal, added, removed)
+
+	shortChanged := false
+	for n := 1; n <= short; n++ {
+		if len(d.Added[n]) > 0 {
+			fmt.Fprintf(w, "\nAdded %d-letter words (%d):\n%s\n", n, len(d.Added[n]), strings.Join(d.Added[n], " "))
+			shortChanged = true
+		}
+		if len(d.Removed[n]) > 0 {
+			fmt.Fprintf(w, "\nRemoved %d-letter words (%d):\n%s\n", n, len(d.Removed[n]), strings.Join(d.Removed[n], " "))
+			shortChanged = true
+		}
+	}
+
+	oldLeaves := equity.LeavesDirectory(cfg.StrategyParamsPath, oldName)
+	newLeaves := equity.LeavesDirectory(cfg.StrategyParamsPath, newName)
+	fmt.Fprintf(w, "\nLeaves: %s uses %s, and %s uses %s.\n", oldName, leavesName(oldLeaves),
+		newName, leavesName(newLeaves))
+	if shortChanged && newLeaves != newName {
+		fmt.Fprintf(w, "Short words changed, and %s has no leaves of its own; "+
+			"its leave values may be off until leaves are made for it.\n", newName)
+	}
+}
+
+func leavesName(dir string) string {
+	if dir == "" {
+		return "no leaves"
+	}
+	return dir
+}
+
+// writeChanges writes every added and removed word to a file, one per
+// line, by length.
+func writeChanges(filename string, d *kwg.LexiconDiff) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, n := range d.Lengths() {
+		for _, word := range d.Added[n] {
+			fmt.Fprintln(w, "+"+word)
+		}
+		for _, word := range d.Removed[n] {
+			fmt.Fprintln(w, "-"+word)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
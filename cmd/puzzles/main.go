@@ -0,0 +1,335 @@
+// The puzzles command builds a puzzle pack offline. It finds puzzles in a
+// directory of GCG files, a single GCG file, or an autoplay log, and writes
+// one JSON object per line for each, with its position as a CGP string,
+// its answer and its tags.
+//
+// Usage:
+//
+//	puzzles [flags] <dir | game.gcg | autoplay.txt>
+//
+// Games are searched in parallel. The buckets of a PuzzleGenerationRequest,
+// given in its protobuf JSON form with -req, say which puzzles to keep, and
+// a bucket with a size stops taking puzzles once it has that many. The
+// command stops early once every bucket is full. A position is only
+// written once, however many games it turns up in.
+//
+// Data files are found through the same environment variables as the
+// shell's flags, such as DATA_PATH.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/domino14/macondo/automatic"
+	"github.com/domino14/macondo/cgp"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/gcgio"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/puzzles"
+	"github.com/domino14/macondo/tilemapping"
+	"github.com/domino14/macondo/zobrist"
+)
+
+// puzzle is a line of the output.
+type puzzle struct {
+	GameID string `json:"game_id"`
+	Turn   int32  `json:"turn"`
+	CGP    string `json:"cgp"`
+	Answer string `json:"answer"`
+	// Score is what the answer scores.
+	Score      int32    `json:"score"`
+	Tags       []string `json:"tags"`
+	Bucket     int32    `json:"bucket"`
+	Difficulty float64  `json:"difficulty"`
+	// Variation is the principal variation of endgame puzzles.
+	Variation []string           `json:"variation,omitempty"`
+	Sim       *pb.PuzzleSimStats `json:"sim,omitempty"`
+
+	hash uint64
+}
+
+func main() {
+	reqFile := flag.String("req", "", "JSON file with a PuzzleGenerationRequest. By default, every puzzle is kept")
+	out := flag.String("o", "", "file to write puzzles to, instead of standard output")
+	threads := flag.Int("threads", runtime.NumCPU(), "number of games to search at once")
+	eqLoss := flag.Int("eqloss", 1000, "skip the rest of a game once its players have lost this much equity in total")
+	lexicon := flag.String("lexicon", "", "lexicon to use for autoplay logs and GCGs that do not name one")
+	letterdist := flag.String("letterdist", "", "letter distribution of the games in an autoplay log")
+	boardLayout := flag.String("board", "", "board layout of the games in an autoplay log")
+	debug := flag.Bool("debug", false, "debug logging on")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <dir | game.gcg | autoplay.txt>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *threads < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	}
+
+	ex, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.Load(nil); err != nil {
+		os.Exit(2)
+	}
+	cfg.AdjustRelativePaths(filepath.Dir(ex))
+	if *lexicon != "" {
+		cfg.DefaultLexicon = *lexicon
+	}
+	if *letterdist != "" {
+		cfg.DefaultLetterDistribution = *letterdist
+	}
+
+	req := &pb.PuzzleGenerationRequest{Buckets: []*pb.PuzzleBucket{{}}}
+	if *reqFile != "" {
+		bts, err := os.ReadFile(*reqFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		req = &pb.PuzzleGenerationRequest{}
+		if err := protojson.Unmarshal(bts, req); err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", *reqFile, err)
+			os.Exit(1)
+		}
+	}
+	if err := puzzles.InitializePuzzleGenerationRequest(req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		w, err = os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer w.Close()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	histories := make(chan *pb.GameHistory)
+	found := make(chan *puzzle)
+	var readErr error
+	go func() {
+		defer close(histories)
+		readErr = readGames(ctx, cfg, flag.Arg(0), *boardLayout, histories)
+	}()
+
+	hasher := &positionHasher{byDim: map[int]*zobrist.Zobrist{}}
+	var wg sync.WaitGroup
+	for i := 0; i < *threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for history := range histories {
+				pzls, err := findPuzzles(cfg, *eqLoss, history, req, hasher)
+				if err != nil {
+					log.Warn().Err(err).Str("gid", history.Uid).Msg("puzzle-search-failed")
+					continue
+				}
+				for _, p := range pzls {
+					select {
+					case found <- p:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	seen := map[uint64]bool{}
+	counts := make([]int32, len(req.Buckets))
+	written := 0
+	for p := range found {
+		bucket := req.Buckets[p.Bucket]
+		if seen[p.hash] || (bucket.Size > 0 && counts[p.Bucket] >= bucket.Size) {
+			continue
+		}
+		seen[p.hash] = true
+		counts[p.Bucket]++
+		if err := enc.Encode(p); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		written++
+		if bucketsFull(req.Buckets, counts) {
+			cancel()
+			break
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// The games were all read unless every bucket filled up first.
+	if ctx.Err() == nil && readErr != nil {
+		fmt.Fprintln(os.Stderr, readErr)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d puzzles\n", written)
+}
+
+// bucketsFull returns whether every bucket has a size and is full.
+func bucketsFull(buckets []*pb.PuzzleBucket, counts []int32) bool {
+	for i, b := range buckets {
+		if b.Size <= 0 || counts[i] < b.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// readGames sends the games in path to histories until ctx is done. path
+// is a directory of GCG files, searched recursively, a GCG file, or an
+// autoplay log.
+func readGames(ctx context.Context, cfg *config.Config, path, boardLayout string,
+	histories chan<- *pb.GameHistory) error {
+
+	send := func(history *pb.GameHistory) error {
+		select {
+		case histories <- history:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() && !strings.EqualFold(filepath.Ext(path), ".gcg") {
+		return automatic.ForEachLogGame(cfg, path, cfg.DefaultLetterDistribution, cfg.DefaultLexicon,
+			boardLayout, send)
+	}
+	return filepath.WalkDir(path, func(fn string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(fn), ".gcg") {
+			return nil
+		}
+		history, err := gcgio.ParseGCG(cfg, fn)
+		if err != nil {
+			log.Warn().Err(err).Str("file", fn).Msg("skipping-gcg")
+			return nil
+		}
+		if history.Uid == "" {
+			history.Uid = strings.TrimSuffix(filepath.Base(fn), filepath.Ext(fn))
+		}
+		return send(history)
+	})
+}
+
+// findPuzzles finds the puzzles in a game.
+func findPuzzles(cfg *config.Config, eqLoss int, history *pb.GameHistory, req *pb.PuzzleGenerationRequest,
+	hasher *positionHasher) ([]*puzzle, error) {
+
+	if history.Lexicon == "" {
+		history.Lexicon = cfg.DefaultLexicon
+	}
+	boardLayout, ldName, variant := game.HistoryToVariant(history)
+	rules, err := game.NewBasicGameRules(cfg, history.Lexicon, boardLayout, ldName, game.CrossScoreAndSet, variant)
+	if err != nil {
+		return nil, err
+	}
+	g, err := game.NewFromHistory(history, rules, 0)
+	if err != nil {
+		return nil, err
+	}
+	resps, err := puzzles.CreatePuzzlesFromGame(cfg, eqLoss, g, req)
+	if err != nil {
+		return nil, err
+	}
+	pzls := make([]*puzzle, 0, len(resps))
+	for _, r := range resps {
+		if err := g.PlayToTurn(int(r.TurnNumber)); err != nil {
+			return nil, err
+		}
+		// Only an endgame's opponent rack is known from the position.
+		endgame := len(r.PrincipalVariation) > 0
+		p := &puzzle{
+			GameID:     r.GameId,
+			Turn:       r.TurnNumber,
+			CGP:        cgp.ToCGP(g, endgame),
+			Answer:     notation(r.Answer),
+			Score:      r.Answer.Score,
+			Bucket:     r.BucketIndex,
+			Difficulty: r.Difficulty,
+			Sim:        r.SimStats,
+			hash:       hasher.hash(g),
+		}
+		for _, t := range r.Tags {
+			p.Tags = append(p.Tags, t.String())
+		}
+		for _, evt := range r.PrincipalVariation {
+			p.Variation = append(p.Variation, notation(evt))
+		}
+		pzls = append(pzls, p)
+	}
+	return pzls, nil
+}
+
+// notation returns a move event as move.Move's Notation would.
+func notation(evt *pb.GameEvent) string {
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		return evt.Position + " " + evt.PlayedTiles
+	case pb.GameEvent_EXCHANGE:
+		return "-" + evt.Exchanged
+	}
+	return "-"
+}
+
+// positionHasher hashes the board and the rack on turn of positions, to
+// find the same puzzle in different games. It keeps a Zobrist table for
+// each board size; hashing is safe to do concurrently.
+type positionHasher struct {
+	sync.Mutex
+	byDim map[int]*zobrist.Zobrist
+}
+
+func (h *positionHasher) hash(g *game.Game) uint64 {
+	dim := g.Board().Dim()
+	h.Lock()
+	z, ok := h.byDim[dim]
+	if !ok {
+		z = &zobrist.Zobrist{}
+		z.Initialize(dim)
+		h.byDim[dim] = z
+	}
+	h.Unlock()
+	return z.Hash(g.Board().GetSquares(), g.RackFor(g.PlayerOnTurn()), tilemapping.NewRack(g.Alphabet()), false)
+}
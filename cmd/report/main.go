@@ -0,0 +1,156 @@
+// The report command reviews a game in a GCG file: it evaluates every turn
+// of one or both players, and writes a report with the board before each
+// turn, the move played against the best moves, what it lost, and a
+// scorecard for each player. Reports are Markdown or self-contained HTML.
+//
+// Usage:
+//
+//	report [flags] game.gcg
+//
+// Data files are found through the same environment variables as the
+// shell's flags, such as DATA_PATH.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/gcgio"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/report"
+)
+
+func main() {
+	users := flag.String("users", "", "comma-separated nicknames of the players to review. By default, both are reviewed")
+	format := flag.String("format", "", "html or markdown. By default, markdown unless -o names an .html file")
+	out := flag.String("o", "", "file to write the report to, instead of standard output")
+	lexicon := flag.String("lexicon", "", "lexicon to use if the GCG does not name one")
+	sim := flag.Bool("sim", false, "sim every turn, and solve endgames exactly")
+	endgame := flag.Bool("endgame", false, "solve endgames exactly, even without -sim")
+	plies := flag.Int("plies", 0, "plies to sim")
+	candidates := flag.Int("candidates", 0, "number of moves, by static equity, to sim each turn against")
+	iterations := flag.Int("iterations", 0, "iterations to sim each turn for")
+	phonyChance := flag.Float64("phony-challenge-chance", 0, "chance, from 0 to 1, that phonies get challenged. By default it depends on the challenge rule")
+	numMoves := flag.Int("moves", report.DefaultNumMoves, "number of the best moves to list for each turn")
+	debug := flag.Bool("debug", false, "debug logging on")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] game.gcg\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	}
+	if *format == "" {
+		*format = "markdown"
+		if ext := strings.ToLower(filepath.Ext(*out)); ext == ".html" || ext == ".htm" {
+			*format = "html"
+		}
+	}
+	if *format != "html" && *format != "markdown" {
+		fmt.Fprintf(os.Stderr, "unknown format %v\n", *format)
+		os.Exit(2)
+	}
+
+	ex, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.Load(nil); err != nil {
+		os.Exit(2)
+	}
+	cfg.AdjustRelativePaths(filepath.Dir(ex))
+	if *lexicon != "" {
+		cfg.DefaultLexicon = *lexicon
+	}
+
+	p, err := load(cfg, flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	opts := report.Options{
+		Sim:           *sim,
+		Endgame:       *endgame,
+		SimPlies:      *plies,
+		SimCandidates: *candidates,
+		SimIterations: *iterations,
+		NumMoves:      *numMoves,
+
+		PhonyChallengeChance: *phonyChance,
+	}
+	for _, u := range strings.Split(*users, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			opts.Users = append(opts.Users, u)
+		}
+	}
+	r, err := report.New(context.Background(), p, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "html" {
+		err = r.WriteHTML(w)
+	} else {
+		err = r.WriteMarkdown(w)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// load loads the game in a GCG file.
+func load(cfg *config.Config, filename string) (*bot.BotTurnPlayer, error) {
+	history, err := gcgio.ParseGCG(cfg, filename)
+	if err != nil {
+		return nil, err
+	}
+	if history.Lexicon == "" {
+		history.Lexicon = cfg.DefaultLexicon
+	}
+	boardLayout, ldName, variant := game.HistoryToVariant(history)
+	rules, err := game.NewBasicGameRules(cfg, history.Lexicon, boardLayout, ldName, game.CrossScoreAndSet, variant)
+	if err != nil {
+		return nil, err
+	}
+	g, err := game.NewFromHistory(history, rules, 0)
+	if err != nil {
+		return nil, err
+	}
+	p, err := bot.NewBotTurnPlayerFromGame(g, &bot.BotConfig{Config: *cfg}, pb.BotRequest_HASTY_BOT)
+	if err != nil {
+		return nil, err
+	}
+	p.SetBackupMode(game.InteractiveGameplayMode)
+	p.SetStateStackLength(1)
+	return p, nil
+}
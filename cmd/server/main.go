@@ -0,0 +1,71 @@
+// The server command serves the macondo analysis API over HTTP. See
+// docs/manual/server.md.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/server"
+)
+
+const (
+	GracefulShutdownTimeout = 20 * time.Second
+)
+
+func main() {
+	// Determine the directory of the executable. We will use this
+	// directory to find the data files if an absolute path is not
+	// provided for these!
+	ex, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	exPath := filepath.Dir(ex)
+
+	cfg := &config.Config{}
+	cfg.Load(os.Args[1:])
+	cfg.AdjustRelativePaths(exPath)
+
+	if cfg.Debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	}
+
+	s := server.New(cfg)
+	srv := &http.Server{Addr: cfg.ServerAddr, Handler: s}
+
+	idleConnsClosed := make(chan struct{})
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+		log.Info().Msg("got quit signal...")
+		// Cancel the jobs first, so that clients waiting on them get their
+		// answers and hang up.
+		s.Shutdown()
+		ctx, cancel := context.WithTimeout(context.Background(), GracefulShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Err(err).Msg("server-shutdown-error")
+		}
+		close(idleConnsClosed)
+	}()
+
+	log.Info().Str("addr", cfg.ServerAddr).Msg("server-listening")
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal().Err(err).Msg("server-listen-error")
+	}
+	<-idleConnsClosed
+}
@@ -18,9 +18,21 @@ type Config struct {
 	DefaultLexicon            string
 	DefaultLetterDistribution string
 	NatsURL                   string
+	BotHTTPAddr               string
 
 	WolgesAwsmURL string
 
+	ExternalEngineURL       string
+	ExternalEngineCmd       string
+	ExternalEngineLexica    string
+	ExternalEngineLanguages string
+	ExternalEngineLeaves    string
+	ExternalEngineRules     string
+
+	ServerAddr     string
+	ServerMaxJobs  int
+	ServerMaxQueue int
+
 	CPUProfile string
 	MemProfile string
 }
@@ -51,9 +63,19 @@ func (c *Config) Load(args []string) error {
 	fs.StringVar(&c.DefaultLetterDistribution, "default-letter-distribution", "English", "the default letter distribution to use. English, EnglishSuper, Spanish, Polish, etc.")
 	fs.StringVar(&c.DataPath, "data-path", "./data", "data path")
 	fs.StringVar(&c.NatsURL, "nats-url", "nats://127.0.0.1:4222", "The URL of the NATS server")
+	fs.StringVar(&c.BotHTTPAddr, "bot-http-addr", ":8089", "address for the bot to serve health checks and metrics on. Empty to turn them off")
 	fs.StringVar(&c.CPUProfile, "cpu-profile", "", "file to save cpu profile in")
 	fs.StringVar(&c.MemProfile, "mem-profile", "", "file to save mem profile in")
-	fs.StringVar(&c.WolgesAwsmURL, "wolges-awsm-url", "", "URL for the wolges-awsm server. Needed for WordSmog bot.")
+	fs.StringVar(&c.WolgesAwsmURL, "wolges-awsm-url", "", "URL for the wolges-awsm server. Deprecated: use external-engine-url")
+	fs.StringVar(&c.ExternalEngineURL, "external-engine-url", "", "URL of an external move generator served over HTTP. Needed for WordSmog bot")
+	fs.StringVar(&c.ExternalEngineCmd, "external-engine-cmd", "", "command that runs an external move generator over stdin and stdout, if there is no external-engine-url")
+	fs.StringVar(&c.ExternalEngineLexica, "external-engine-lexica", "", "comma-separated lexicon=name pairs renaming lexica for the external engine")
+	fs.StringVar(&c.ExternalEngineLanguages, "external-engine-languages", "rd=german,nsf=norwegian,fra=french,disc=catalan", "comma-separated prefix=language pairs giving the language of lexica that start with the prefix. The default language is english")
+	fs.StringVar(&c.ExternalEngineLeaves, "external-engine-leaves", "csw21=CSW21", "comma-separated lexicon=leaves pairs for lexica whose leaves are not named after their language")
+	fs.StringVar(&c.ExternalEngineRules, "external-engine-rules", "classic=CrosswordGame,wordsmog=WordSmog,classic_super=CrosswordGameSuper,wordsmog_super=WordSmogSuper", "comma-separated variant=rules pairs naming the rules of each variant for the external engine")
+	fs.StringVar(&c.ServerAddr, "server-addr", ":8088", "address for the analysis server to listen on")
+	fs.IntVar(&c.ServerMaxJobs, "server-max-jobs", 2, "maximum number of analysis jobs the server runs at once")
+	fs.IntVar(&c.ServerMaxQueue, "server-max-queue", 16, "maximum number of analysis jobs the server keeps waiting to run")
 	err := fs.Parse(args)
 	return err
 }
@@ -378,6 +378,9 @@ func (s *Solver) Solve(ctx context.Context, plies int) (float32, []*move.Move, e
 	if s.game.Bag().TilesRemaining() > 0 {
 		return 0, nil, errors.New("bag is not empty; cannot use endgame solver")
 	}
+	if s.game.NumPlayers() != 2 {
+		return 0, nil, game.ErrNotTwoPlayerGame
+	}
 	log.Debug().Int("plies", plies).
 		Bool("iterative-deepening", s.iterativeDeepeningOn).
 		Bool("complex-evaluation", s.complexEvaluation).
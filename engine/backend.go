@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// The search modes of the engine.
+const (
+	ModeAuto    = "auto"
+	ModeStatic  = "static"
+	ModeSim     = "sim"
+	ModeEndgame = "endgame"
+)
+
+// OptionType is the kind of value an engine option takes.
+type OptionType string
+
+const (
+	OptionSpin   OptionType = "spin"
+	OptionCombo  OptionType = "combo"
+	OptionString OptionType = "string"
+)
+
+// Option describes a setting that a front-end can change with setoption.
+type Option struct {
+	Name    string
+	Type    OptionType
+	Default string
+	// Min and Max only apply to spin options.
+	Min int
+	Max int
+	// Vars are the allowed values of a combo option.
+	Vars []string
+}
+
+// String returns the option line sent in reply to the engine command.
+func (o Option) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "option name %s type %s default %s", o.Name, o.Type, o.Default)
+	switch o.Type {
+	case OptionSpin:
+		fmt.Fprintf(&s, " min %d max %d", o.Min, o.Max)
+	case OptionCombo:
+		for _, v := range o.Vars {
+			fmt.Fprintf(&s, " var %s", v)
+		}
+	}
+	return s.String()
+}
+
+// Limits are the parameters of a go command. The zero value means the
+// backend decides for itself when to stop.
+type Limits struct {
+	MoveTime   time.Duration
+	Iterations int
+	Depth      int
+	Infinite   bool
+}
+
+// Info reports the progress of a search.
+type Info struct {
+	Mode string
+	Time time.Duration
+	// Iterations is the number of sim iterations so far.
+	Iterations int
+	// Depth is the number of plies an endgame was solved to.
+	Depth int
+	// Score is the number of points the best move scores.
+	Score int
+	// Spread is the final spread the endgame solver expects.
+	Spread float64
+	// WinPct is the win percentage of the best move, from 0 to 100.
+	WinPct float64
+	Equity float64
+	// PV is the best move, followed by the expected replies in an endgame.
+	PV []string
+}
+
+// String returns the info line sent to the front-end.
+func (i Info) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "info mode %s time %d", i.Mode, i.Time.Milliseconds())
+	switch i.Mode {
+	case ModeStatic:
+		fmt.Fprintf(&s, " score %d equity %.3f", i.Score, i.Equity)
+	case ModeSim:
+		fmt.Fprintf(&s, " iterations %d winpct %.2f equity %.3f", i.Iterations, i.WinPct, i.Equity)
+	case ModeEndgame:
+		fmt.Fprintf(&s, " depth %d spread %d", i.Depth, int(math.Round(i.Spread)))
+	}
+	if len(i.PV) > 0 {
+		fmt.Fprintf(&s, " pv %s", strings.Join(i.PV, " "))
+	}
+	return s.String()
+}
+
+// backend does the actual work behind the protocol.
+type backend interface {
+	// Options returns the options the backend understands, with their
+	// current values as defaults.
+	Options() []Option
+	SetOption(name, value string) error
+	SetPosition(cgp string) error
+	// Search looks for the best move in the current position until it is
+	// done, the limits are reached, or the context is canceled. It calls
+	// report with its progress, and returns the best move in protocol
+	// notation.
+	Search(ctx context.Context, limits Limits, report func(Info)) (string, error)
+}
@@ -0,0 +1,242 @@
+// Package engine implements a line-based protocol over standard input and
+// output, so that graphical front-ends can use macondo as an analysis
+// engine. See docs/manual/engine.md for the specification.
+package engine
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/config"
+)
+
+// EngineName is sent to the front-end in reply to the engine command.
+const EngineName = "macondo"
+
+var (
+	errSearching    = errors.New("a search is in progress; send stop first")
+	errPositionType = errors.New("only cgp positions are supported")
+)
+
+// Engine reads protocol commands and writes replies. At most one search
+// runs at a time, in the background, so that stop and isready can be
+// answered while searching.
+type Engine struct {
+	b backend
+
+	outMu sync.Mutex
+	out   io.Writer
+
+	mu sync.Mutex
+	// cancel is set while a search is running.
+	cancel context.CancelFunc
+	// done is closed once the last search has sent its best move.
+	done chan struct{}
+}
+
+// New creates an engine that writes its replies to out.
+func New(cfg *config.Config, out io.Writer) *Engine {
+	return newEngine(newMacondoBackend(cfg), out)
+}
+
+func newEngine(b backend, out io.Writer) *Engine {
+	return &Engine{b: b, out: out}
+}
+
+// Run handles commands read from in, one per line, until it reads quit or
+// reaches the end of the input. Any search still running is stopped
+// before returning.
+func (e *Engine) Run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if !e.Handle(scanner.Text()) {
+			return nil
+		}
+	}
+	e.stop()
+	return scanner.Err()
+}
+
+// Handle executes a single command. It returns false once the engine
+// should quit.
+func (e *Engine) Handle(line string) bool {
+	line = strings.TrimSpace(line)
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true
+	}
+	log.Debug().Str("line", line).Msg("engine-command")
+	var err error
+	switch fields[0] {
+	case "engine":
+		e.send("id name " + EngineName)
+		for _, o := range e.b.Options() {
+			e.send(o.String())
+		}
+		e.send("engineok")
+	case "isready":
+		e.send("readyok")
+	case "setoption":
+		err = e.setOption(fields[1:])
+	case "position":
+		err = e.position(strings.TrimSpace(strings.TrimPrefix(line, "position")))
+	case "go":
+		err = e.startSearch(fields[1:])
+	case "stop":
+		e.stop()
+	case "quit":
+		e.stop()
+		return false
+	default:
+		err = fmt.Errorf("unknown command %v", fields[0])
+	}
+	if err != nil {
+		e.send("error " + err.Error())
+	}
+	return true
+}
+
+// send writes a single line to the front-end.
+func (e *Engine) send(line string) {
+	e.outMu.Lock()
+	defer e.outMu.Unlock()
+	io.WriteString(e.out, line+"\n")
+}
+
+func (e *Engine) searching() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cancel != nil
+}
+
+func (e *Engine) setOption(args []string) error {
+	if e.searching() {
+		return errSearching
+	}
+	// setoption name <name> [value <value>]
+	if len(args) < 2 || args[0] != "name" {
+		return errors.New("usage: setoption name <name> [value <value>]")
+	}
+	name := []string{}
+	value := []string{}
+	inValue := false
+	for _, a := range args[1:] {
+		if a == "value" && !inValue {
+			inValue = true
+			continue
+		}
+		if inValue {
+			value = append(value, a)
+		} else {
+			name = append(name, a)
+		}
+	}
+	return e.b.SetOption(strings.Join(name, " "), strings.Join(value, " "))
+}
+
+func (e *Engine) position(args string) error {
+	if e.searching() {
+		return errSearching
+	}
+	if !strings.HasPrefix(args, "cgp ") {
+		return errPositionType
+	}
+	return e.b.SetPosition(strings.TrimSpace(strings.TrimPrefix(args, "cgp ")))
+}
+
+func parseLimits(args []string) (Limits, error) {
+	var limits Limits
+	for i := 0; i < len(args); i++ {
+		if args[i] == "infinite" {
+			limits.Infinite = true
+			continue
+		}
+		if i+1 == len(args) {
+			return limits, fmt.Errorf("go %v needs a value", args[i])
+		}
+		n, err := strconv.Atoi(args[i+1])
+		if err != nil || n <= 0 {
+			return limits, fmt.Errorf("go %v needs a positive number", args[i])
+		}
+		switch args[i] {
+		case "movetime":
+			limits.MoveTime = time.Duration(n) * time.Millisecond
+		case "iterations":
+			limits.Iterations = n
+		case "depth":
+			limits.Depth = n
+		default:
+			return limits, fmt.Errorf("unknown go parameter %v", args[i])
+		}
+		i++
+	}
+	return limits, nil
+}
+
+func (e *Engine) startSearch(args []string) error {
+	limits, err := parseLimits(args)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel != nil {
+		return errSearching
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if limits.MoveTime > 0 && !limits.Infinite {
+		ctx, cancel = context.WithTimeout(context.Background(), limits.MoveTime)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	done := make(chan struct{})
+	e.cancel = cancel
+	e.done = done
+
+	go func() {
+		defer close(done)
+		start := time.Now()
+		best, err := e.b.Search(ctx, limits, func(info Info) {
+			info.Time = time.Since(start)
+			e.send(info.String())
+		})
+		cancel()
+		if err != nil {
+			log.Err(err).Msg("engine-search-error")
+			e.send("error " + err.Error())
+			best = "none"
+		}
+		// The search is over by the time the front-end sees the best move,
+		// so that it can send new commands right away, and a new search
+		// cannot start before the best move is sent.
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.cancel = nil
+		e.send("bestmove " + best)
+	}()
+	return nil
+}
+
+// stop stops the running search, if any, and waits for its best move to
+// be sent.
+func (e *Engine) stop() {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+}
@@ -0,0 +1,337 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// syncBuffer collects the engine's output, which is written from more than
+// one goroutine.
+type syncBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.Lock()
+	defer b.Unlock()
+	s := strings.TrimSuffix(b.buf.String(), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func (b *syncBuffer) reset() {
+	b.Lock()
+	defer b.Unlock()
+	b.buf.Reset()
+}
+
+// waitFor waits until a line with the given prefix shows up in the output.
+func (b *syncBuffer) waitFor(t *testing.T, prefix string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, l := range b.lines() {
+			if strings.HasPrefix(l, prefix) {
+				return
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %q; output was %q", prefix, b.lines())
+}
+
+func countPrefix(lines []string, prefix string) int {
+	n := 0
+	for _, l := range lines {
+		if strings.HasPrefix(l, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// fakeBackend pretends to sim: it counts one iteration per millisecond
+// and reports every ten iterations.
+type fakeBackend struct {
+	cgp     string
+	options map[string]string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{options: map[string]string{}}
+}
+
+func (f *fakeBackend) Options() []Option {
+	return []Option{
+		{Name: "Threads", Type: OptionSpin, Default: "1", Min: 1, Max: 8},
+		{Name: "Mode", Type: OptionCombo, Default: ModeAuto, Vars: []string{ModeAuto, ModeSim}},
+	}
+}
+
+func (f *fakeBackend) SetOption(name, value string) error {
+	if name == "Bogus" {
+		return errUnknownValue
+	}
+	f.options[name] = value
+	return nil
+}
+
+func (f *fakeBackend) SetPosition(cgp string) error {
+	f.cgp = cgp
+	return nil
+}
+
+func (f *fakeBackend) Search(ctx context.Context, limits Limits, report func(Info)) (string, error) {
+	if f.cgp == "" {
+		return "", errNoPosition
+	}
+	tk := time.NewTicker(time.Millisecond)
+	defer tk.Stop()
+	iters := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return "8D STAIR", nil
+		case <-tk.C:
+			iters++
+			if iters%10 == 0 {
+				report(Info{Mode: ModeSim, Iterations: iters, WinPct: 55, PV: []string{"8D STAIR"}})
+			}
+			if limits.Iterations > 0 && iters >= limits.Iterations {
+				return "8D STAIR", nil
+			}
+		}
+	}
+}
+
+func newTestEngine() (*Engine, *fakeBackend, *syncBuffer) {
+	b := newFakeBackend()
+	out := &syncBuffer{}
+	return newEngine(b, out), b, out
+}
+
+const testCGP = "15/15/15/15/15/15/15/15/15/15/15/15/15/15/15 AEINRST/ 0/0 0 lex NWL20;"
+
+func TestHandshake(t *testing.T) {
+	is := is.New(t)
+	e, _, out := newTestEngine()
+	is.True(e.Handle("engine"))
+	is.Equal(out.lines(), []string{
+		"id name macondo",
+		"option name Threads type spin default 1 min 1 max 8",
+		"option name Mode type combo default auto var auto var sim",
+		"engineok",
+	})
+	out.reset()
+	is.True(e.Handle("isready"))
+	is.True(e.Handle(""))
+	is.Equal(out.lines(), []string{"readyok"})
+}
+
+func TestUnknownCommand(t *testing.T) {
+	is := is.New(t)
+	e, _, out := newTestEngine()
+	is.True(e.Handle("frobnicate now"))
+	is.Equal(out.lines(), []string{"error unknown command frobnicate"})
+}
+
+func TestSetOption(t *testing.T) {
+	is := is.New(t)
+	e, b, out := newTestEngine()
+	e.Handle("setoption name Threads value 4")
+	e.Handle("setoption name Leave File value my leaves.klv2")
+	is.Equal(b.options["Threads"], "4")
+	is.Equal(b.options["Leave File"], "my leaves.klv2")
+	is.Equal(len(out.lines()), 0)
+
+	e.Handle("setoption name Bogus value 1")
+	e.Handle("setoption Threads 4")
+	lines := out.lines()
+	is.Equal(len(lines), 2)
+	is.True(strings.HasPrefix(lines[0], "error "))
+	is.True(strings.HasPrefix(lines[1], "error usage"))
+}
+
+func TestPosition(t *testing.T) {
+	is := is.New(t)
+	e, b, out := newTestEngine()
+	e.Handle("position cgp " + testCGP)
+	is.Equal(b.cgp, testCGP)
+	is.Equal(len(out.lines()), 0)
+
+	e.Handle("position fen rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	is.Equal(out.lines(), []string{"error " + errPositionType.Error()})
+	is.Equal(b.cgp, testCGP)
+}
+
+func TestGoWithoutPosition(t *testing.T) {
+	is := is.New(t)
+	e, _, out := newTestEngine()
+	e.Handle("go movetime 10")
+	out.waitFor(t, "bestmove")
+	is.Equal(out.lines(), []string{"error " + errNoPosition.Error(), "bestmove none"})
+}
+
+func TestGoMoveTime(t *testing.T) {
+	is := is.New(t)
+	e, _, out := newTestEngine()
+	e.Handle("position cgp " + testCGP)
+	e.Handle("go movetime 100")
+	out.waitFor(t, "bestmove")
+	lines := out.lines()
+	is.True(countPrefix(lines, "info mode sim ") > 0)
+	is.Equal(countPrefix(lines, "bestmove"), 1)
+	is.Equal(lines[len(lines)-1], "bestmove 8D STAIR")
+	// Stopping after the search is done does nothing.
+	e.Handle("stop")
+	is.Equal(len(out.lines()), len(lines))
+}
+
+func TestGoIterations(t *testing.T) {
+	is := is.New(t)
+	e, _, out := newTestEngine()
+	e.Handle("position cgp " + testCGP)
+	e.Handle("go iterations 30")
+	out.waitFor(t, "bestmove")
+	lines := out.lines()
+	is.Equal(countPrefix(lines, "info"), 3)
+	is.True(strings.Contains(lines[2], " iterations 30 "))
+}
+
+func TestStopInfiniteSearch(t *testing.T) {
+	is := is.New(t)
+	e, b, out := newTestEngine()
+	e.Handle("position cgp " + testCGP)
+	e.Handle("go infinite")
+	out.waitFor(t, "info")
+
+	// The engine stays responsive while searching, but won't change the
+	// position or start another search.
+	e.Handle("isready")
+	out.waitFor(t, "readyok")
+	e.Handle("position cgp 15/15/15/15/15/15/15/15/15/15/15/15/15/15/15 A/ 0/0 0")
+	e.Handle("setoption name Threads value 2")
+	e.Handle("go")
+	is.Equal(countPrefix(out.lines(), "error "+errSearching.Error()), 3)
+	is.Equal(b.cgp, testCGP)
+	is.Equal(countPrefix(out.lines(), "bestmove"), 0)
+
+	// The best move is sent by the time stop returns.
+	e.Handle("stop")
+	lines := out.lines()
+	is.Equal(countPrefix(lines, "bestmove"), 1)
+	is.Equal(lines[len(lines)-1], "bestmove 8D STAIR")
+
+	// And a new search can start.
+	out.reset()
+	e.Handle("go iterations 10")
+	out.waitFor(t, "bestmove 8D STAIR")
+	is.Equal(countPrefix(out.lines(), "error"), 0)
+}
+
+func TestBadGoParameters(t *testing.T) {
+	is := is.New(t)
+	for _, cmd := range []string{"go movetime", "go movetime -5", "go depth x", "go nodes 100"} {
+		e, _, out := newTestEngine()
+		e.Handle("position cgp " + testCGP)
+		e.Handle(cmd)
+		lines := out.lines()
+		is.Equal(len(lines), 1)
+		is.True(strings.HasPrefix(lines[0], "error "))
+	}
+}
+
+func TestRun(t *testing.T) {
+	is := is.New(t)
+	e, _, out := newTestEngine()
+	in := strings.NewReader("engine\nposition cgp " + testCGP + "\ngo infinite\nquit\nisready\n")
+	is.NoErr(e.Run(in))
+	lines := out.lines()
+	is.Equal(lines[len(lines)-1], "bestmove 8D STAIR")
+	// Nothing is read after quit.
+	is.Equal(countPrefix(lines, "readyok"), 0)
+
+	// The end of the input stops the search too.
+	e, _, out = newTestEngine()
+	is.NoErr(e.Run(strings.NewReader("position cgp " + testCGP + "\ngo infinite\n")))
+	is.Equal(countPrefix(out.lines(), "bestmove"), 1)
+}
+
+func TestInfoString(t *testing.T) {
+	is := is.New(t)
+	is.Equal(Info{Mode: ModeStatic, Score: 72, Equity: 80.5, PV: []string{"8D STAIR"}}.String(),
+		"info mode static time 0 score 72 equity 80.500 pv 8D STAIR")
+	is.Equal(Info{Mode: ModeSim, Time: 1500 * time.Millisecond, Iterations: 2000,
+		WinPct: 61.234, Equity: 12.3456, PV: []string{"-QV"}}.String(),
+		"info mode sim time 1500 iterations 2000 winpct 61.23 equity 12.346 pv -QV")
+	is.Equal(Info{Mode: ModeEndgame, Time: time.Second, Depth: 4, Spread: -12.6,
+		PV: []string{"H12 ZA", "-", "1A QI.S"}}.String(),
+		"info mode endgame time 1000 depth 4 spread -13 pv H12 ZA - 1A QI.S")
+}
+
+func TestNotation(t *testing.T) {
+	is := is.New(t)
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	alph := ld.TileMapping()
+
+	play := move.NewScoringMoveSimple(20, "8D", "ST.IR", "AEN", alph)
+	is.Equal(notation(play), "8D ST.IR")
+	play = move.NewScoringMoveSimple(20, "H4", "STAIR", "AEN", alph)
+	is.Equal(notation(play), "H4 STAIR")
+
+	tiles, err := tilemapping.ToMachineWord("QV", alph)
+	is.NoErr(err)
+	leave, err := tilemapping.ToMachineWord("AEIRS", alph)
+	is.NoErr(err)
+	is.Equal(notation(move.NewExchangeMove(tiles, leave, alph)), "-QV")
+	is.Equal(notation(move.NewPassMove(leave, alph)), "-")
+}
+
+func TestMacondoOptions(t *testing.T) {
+	is := is.New(t)
+	b := newMacondoBackend(&DefaultConfig)
+	is.NoErr(b.SetOption("threads", "3"))
+	is.Equal(b.threads, 3)
+	is.NoErr(b.SetOption("Mode", ModeStatic))
+	is.Equal(b.mode, ModeStatic)
+	is.NoErr(b.SetOption("Lexicon", "CSW21"))
+	is.Equal(b.lexicon, "CSW21")
+	is.NoErr(b.SetOption("InfoInterval", "250"))
+	is.Equal(b.infoInterval, 250*time.Millisecond)
+
+	is.True(b.SetOption("Threads", "0") != nil)
+	is.True(b.SetOption("Threads", "many") != nil)
+	is.True(b.SetOption("Mode", "perfect") != nil)
+	is.True(b.SetOption("Hash", "64") != nil)
+
+	// The current values are reported as the defaults.
+	for _, o := range b.Options() {
+		if o.Name == "Threads" {
+			is.Equal(o.Default, "3")
+		}
+	}
+
+	_, err := b.Search(context.Background(), Limits{}, func(Info) {})
+	is.Equal(err, errNoPosition)
+}
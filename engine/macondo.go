@@ -0,0 +1,239 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/analysis"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/move"
+)
+
+var (
+	errNoPosition   = errors.New("no position; send a position command first")
+	errUnknownValue = errors.New("unknown option value")
+)
+
+// macondoBackend searches with macondo's own move generator, simmer and
+// endgame solver, through the same analyzer as the analysis server.
+type macondoBackend struct {
+	analyzer *analysis.Analyzer
+	cgp      string
+	game     *bot.BotTurnPlayer
+
+	lexicon       string
+	mode          string
+	threads       int
+	candidates    int
+	plies         int
+	stopCondition string
+	infoInterval  time.Duration
+}
+
+func newMacondoBackend(cfg *config.Config) *macondoBackend {
+	return &macondoBackend{
+		analyzer:      analysis.NewAnalyzer(cfg),
+		lexicon:       cfg.DefaultLexicon,
+		mode:          ModeAuto,
+		threads:       int(math.Max(1, float64(runtime.NumCPU()-1))),
+		candidates:    40,
+		plies:         2,
+		stopCondition: "99",
+		infoInterval:  time.Second,
+	}
+}
+
+func (b *macondoBackend) Options() []Option {
+	return []Option{
+		{Name: "Lexicon", Type: OptionString, Default: b.lexicon},
+		{Name: "Mode", Type: OptionCombo, Default: b.mode,
+			Vars: []string{ModeAuto, ModeStatic, ModeSim, ModeEndgame}},
+		{Name: "Threads", Type: OptionSpin, Default: strconv.Itoa(b.threads), Min: 1, Max: 256},
+		{Name: "Candidates", Type: OptionSpin, Default: strconv.Itoa(b.candidates), Min: 1, Max: 1000},
+		{Name: "Plies", Type: OptionSpin, Default: strconv.Itoa(b.plies), Min: 1, Max: 20},
+		{Name: "StopCondition", Type: OptionCombo, Default: b.stopCondition,
+			Vars: []string{"none", "95", "98", "99"}},
+		{Name: "InfoInterval", Type: OptionSpin,
+			Default: strconv.Itoa(int(b.infoInterval.Milliseconds())), Min: 100, Max: 60000},
+	}
+}
+
+func (b *macondoBackend) SetOption(name, value string) error {
+	var opt *Option
+	opts := b.Options()
+	for i := range opts {
+		if strings.EqualFold(opts[i].Name, name) {
+			opt = &opts[i]
+			break
+		}
+	}
+	if opt == nil {
+		return fmt.Errorf("unknown option %v", name)
+	}
+	var n int
+	switch opt.Type {
+	case OptionSpin:
+		var err error
+		n, err = strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if n < opt.Min || n > opt.Max {
+			return fmt.Errorf("%v must be between %d and %d", opt.Name, opt.Min, opt.Max)
+		}
+	case OptionCombo:
+		found := false
+		for _, v := range opt.Vars {
+			if v == value {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w %v for %v", errUnknownValue, value, opt.Name)
+		}
+	}
+
+	switch opt.Name {
+	case "Lexicon":
+		b.lexicon = value
+	case "Mode":
+		b.mode = value
+	case "Threads":
+		b.threads = n
+	case "Candidates":
+		b.candidates = n
+	case "Plies":
+		b.plies = n
+	case "StopCondition":
+		b.stopCondition = value
+	case "InfoInterval":
+		b.infoInterval = time.Duration(n) * time.Millisecond
+	}
+	return nil
+}
+
+func (b *macondoBackend) SetPosition(cgpstr string) error {
+	p, err := b.analyzer.Player(b.request(cgpstr))
+	if err != nil {
+		return err
+	}
+	b.cgp = cgpstr
+	b.game = p
+	return nil
+}
+
+// request returns an analysis request for a position with the current
+// options.
+func (b *macondoBackend) request(cgpstr string) *analysis.Request {
+	return &analysis.Request{
+		CGP:           cgpstr,
+		Lexicon:       b.lexicon,
+		NumPlays:      1,
+		Threads:       b.threads,
+		Plies:         b.plies,
+		Candidates:    b.candidates,
+		StopCondition: b.stopCondition,
+	}
+}
+
+func (b *macondoBackend) Search(ctx context.Context, limits Limits, report func(Info)) (string, error) {
+	if b.game == nil {
+		return "", errNoPosition
+	}
+	if !b.game.IsPlaying() {
+		return "", analysis.ErrGameOver
+	}
+	mode := b.mode
+	if mode == ModeAuto {
+		mode = ModeSim
+		if b.game.Bag().TilesRemaining() == 0 {
+			mode = ModeEndgame
+		}
+	}
+	switch mode {
+	case ModeStatic:
+		return b.staticSearch(report)
+	case ModeSim:
+		return b.simSearch(ctx, limits, report)
+	default:
+		return b.endgameSearch(ctx, limits, report)
+	}
+}
+
+func (b *macondoBackend) staticSearch(report func(Info)) (string, error) {
+	best := b.game.GenerateMoves(1)[0]
+	report(Info{
+		Mode:   ModeStatic,
+		Score:  best.Score(),
+		Equity: best.Equity(),
+		PV:     []string{notation(best)},
+	})
+	return notation(best), nil
+}
+
+func (b *macondoBackend) simSearch(ctx context.Context, limits Limits, report func(Info)) (string, error) {
+	req := b.request(b.cgp)
+	req.Iterations = limits.Iterations
+	// The stopping condition only applies when the front-end leaves it up
+	// to the engine when to stop.
+	if limits != (Limits{}) {
+		req.StopCondition = "none"
+	}
+	b.analyzer.SetProgressInterval(b.infoInterval)
+	res, err := b.analyzer.Sim(ctx, req, func(res *analysis.SimResult) {
+		report(simInfo(res))
+	})
+	if err != nil {
+		return "", err
+	}
+	info := simInfo(res)
+	report(info)
+	return info.PV[0], nil
+}
+
+func simInfo(res *analysis.SimResult) Info {
+	top := res.Plays[0]
+	return Info{
+		Mode:       ModeSim,
+		Iterations: res.Iterations,
+		WinPct:     top.WinPct,
+		Equity:     top.SimEquity,
+		PV:         []string{top.Notation},
+	}
+}
+
+func (b *macondoBackend) endgameSearch(ctx context.Context, limits Limits, report func(Info)) (string, error) {
+	req := b.request(b.cgp)
+	req.Depth = limits.Depth
+	res, err := b.analyzer.Endgame(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	pv := make([]string, len(res.PV))
+	for i, m := range res.PV {
+		pv[i] = m.Notation
+	}
+	report(Info{
+		Mode:   ModeEndgame,
+		Depth:  res.Depth,
+		Spread: float64(res.Spread),
+		PV:     pv,
+	})
+	return pv[0], nil
+}
+
+// notation returns a move as written in the protocol, which is the same
+// as in GCG files.
+func notation(m *move.Move) string {
+	if m.Action() == move.MoveTypeChallenge {
+		return "challenge"
+	}
+	return m.Notation()
+}
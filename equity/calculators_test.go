@@ -10,7 +10,9 @@ import (
 	"github.com/domino14/macondo/cross_set"
 	"github.com/domino14/macondo/equity"
 	"github.com/domino14/macondo/gaddag"
+	"github.com/domino14/macondo/game"
 	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/move"
 	"github.com/domino14/macondo/movegen"
 	"github.com/domino14/macondo/tilemapping"
 	"github.com/stretchr/testify/assert"
@@ -137,7 +139,7 @@ func TestPreendgameTiming(t *testing.T) {
 
 	els, err := equity.NewExhaustiveLeaveCalculator("NWL18", &DefaultConfig, "")
 	assert.Nil(t, err)
-	pac, err := equity.NewPreEndgameAdjustmentCalculator(&DefaultConfig, "NWL18", "quackle_preendgame.json")
+	pac, err := equity.NewPreEndgameAdjustmentCalculator(&DefaultConfig, "NWL18", "quackle_preendgame.json", game.RackTileLimit)
 	assert.Nil(t, err)
 	bag := tilemapping.NewBag(ld, alph)
 	bag.RemoveTiles(tilesInPlay.OnBoard)
@@ -162,6 +164,27 @@ func TestPreendgameTiming(t *testing.T) {
 	assert.Equal(t, plays[0].Equity(), float64(1780-3.5))
 }
 
+func TestPreendgameRackSize(t *testing.T) {
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	assert.Nil(t, err)
+	alph := ld.TileMapping()
+	bag := tilemapping.NewBag(ld, alph)
+	drawn := make([]tilemapping.MachineLetter, 95)
+	assert.Nil(t, bag.Draw(95, drawn))
+	m := move.NewScoringMoveSimple(80, "8D", "RETAINS", "", alph)
+
+	// With 5 tiles in the bag and 7 played, 5 tiles are unseen by the
+	// opponent with a rack of 7, and 6 with a rack of 8.
+	for _, tc := range []struct {
+		rackSize int
+		adjust   float64
+	}{{7, -3.5}, {8, -2}} {
+		pac, err := equity.NewPreEndgameAdjustmentCalculator(&DefaultConfig, "NWL18", "quackle_preendgame.json", tc.rackSize)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.adjust, pac.Equity(m, nil, bag, nil))
+	}
+}
+
 func TestOpeningPlayHeuristic(t *testing.T) {
 	gd, err := GaddagFromLexicon("NWL20")
 	assert.Nil(t, err)
@@ -173,7 +196,7 @@ func TestOpeningPlayHeuristic(t *testing.T) {
 	cross_set.GenAllCrossSets(bd, gd, ld)
 	generator.GenAll(tilemapping.RackFromString("AEFLR", alph), false)
 	els, err := equity.NewCombinedStaticCalculator(
-		"NWL20", &DefaultConfig, "", "")
+		"NWL20", &DefaultConfig, "", "", game.RackTileLimit)
 	assert.Nil(t, err)
 	plays := generator.Plays()
 	bag := tilemapping.NewBag(ld, alph)
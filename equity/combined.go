@@ -14,13 +14,16 @@ import (
 type CombinedStaticCalculator struct {
 	leaveValues                Leaves
 	preEndgameAdjustmentValues []float64
+	rackSize                   int
 }
 
+// NewCombinedStaticCalculator makes a calculator for games in the given
+// lexicon, with racks of rackSize tiles.
 func NewCombinedStaticCalculator(lexiconName string,
-	cfg *config.Config, leaveFilename, pegfile string) (
+	cfg *config.Config, leaveFilename, pegfile string, rackSize int) (
 	*CombinedStaticCalculator, error) {
 
-	calc := &CombinedStaticCalculator{}
+	calc := &CombinedStaticCalculator{rackSize: rackSize}
 	if leaveFilename == "" {
 		leaveFilename = LeavesFilename
 	}
@@ -64,10 +67,11 @@ func (csc CombinedStaticCalculator) Equity(play *move.Move, board *board.GameBoa
 
 	if bag.TilesRemaining() > 0 {
 		leaveAdjustment = csc.leaveValues.LeaveValue(leave)
-		bagPlusSeven := bag.TilesRemaining() - play.TilesPlayed() + 7
-		if bagPlusSeven < len(csc.preEndgameAdjustmentValues) {
-			preEndgameAdjustment := csc.preEndgameAdjustmentValues[bagPlusSeven]
-			// log.Debug().Float64("peg-adjust", preEndgameAdjustment).Int("bagPlusSeven", bagPlusSeven).Msg("equity calc")
+		// The tiles unseen by the opponent after this play.
+		bagPlusRack := bag.TilesRemaining() - play.TilesPlayed() + csc.rackSize
+		if bagPlusRack < len(csc.preEndgameAdjustmentValues) {
+			preEndgameAdjustment := csc.preEndgameAdjustmentValues[bagPlusRack]
+			// log.Debug().Float64("peg-adjust", preEndgameAdjustment).Int("bagPlusRack", bagPlusRack).Msg("equity calc")
 			otherAdjustments += preEndgameAdjustment
 		}
 	} else {
@@ -30,6 +30,18 @@ func stratFileForLexicon(strategyDir string, filename string, lexiconName string
 	return file, nil
 }
 
+// LeavesDirectory returns the directory in strategyDir whose leaves the
+// lexicon uses: its own if it has leaves, and otherwise the default
+// directory for its language, or "" if there is none.
+func LeavesDirectory(strategyDir, lexiconName string) string {
+	file, err := cache.Open(filepath.Join(strategyDir, lexiconName, LeavesFilename))
+	if err != nil {
+		return defaultForLexicon(lexiconName)
+	}
+	file.Close()
+	return lexiconName
+}
+
 func loadKLV(strategyPath, leavefile, lexiconName string) (*KLV, error) {
 	file, err := stratFileForLexicon(strategyPath, leavefile, lexiconName)
 	if err != nil {
@@ -14,9 +14,12 @@ import (
 // pre-endgames; this should only be used for simulation estimates!
 type PreEndgameAdjustmentCalculator struct {
 	preEndgameAdjustmentValues []float64
+	rackSize                   int
 }
 
-func NewPreEndgameAdjustmentCalculator(cfg *config.Config, lexiconName string, pegfile string) (*PreEndgameAdjustmentCalculator, error) {
+// NewPreEndgameAdjustmentCalculator makes a calculator for games in the
+// given lexicon, with racks of rackSize tiles.
+func NewPreEndgameAdjustmentCalculator(cfg *config.Config, lexiconName string, pegfile string, rackSize int) (*PreEndgameAdjustmentCalculator, error) {
 	if pegfile == "" {
 		pegfile = PEGAdjustmentFilename
 	}
@@ -25,7 +28,7 @@ func NewPreEndgameAdjustmentCalculator(cfg *config.Config, lexiconName string, p
 		log.Err(err).Msg("loading-peg-values")
 	}
 	var ok bool
-	calc := &PreEndgameAdjustmentCalculator{}
+	calc := &PreEndgameAdjustmentCalculator{rackSize: rackSize}
 	calc.preEndgameAdjustmentValues, ok = pegValues.([]float64)
 	if !ok {
 		log.Info().Msg("no peg values found, will use no pre-endgame strategy")
@@ -37,11 +40,12 @@ func NewPreEndgameAdjustmentCalculator(cfg *config.Config, lexiconName string, p
 func (pac PreEndgameAdjustmentCalculator) Equity(play *move.Move, board *board.GameBoard,
 	bag *tilemapping.Bag, oppRack *tilemapping.Rack) float64 {
 
-	bagPlusSeven := bag.TilesRemaining() - play.TilesPlayed() + 7
+	// The tiles unseen by the opponent after this play.
+	bagPlusRack := bag.TilesRemaining() - play.TilesPlayed() + pac.rackSize
 	var preEndgameAdjustment float64
-	if bagPlusSeven < len(pac.preEndgameAdjustmentValues) {
-		preEndgameAdjustment = pac.preEndgameAdjustmentValues[bagPlusSeven]
-		log.Debug().Float64("peg-adjust", preEndgameAdjustment).Int("bagPlusSeven", bagPlusSeven).Msg("equity calc")
+	if bagPlusRack < len(pac.preEndgameAdjustmentValues) {
+		preEndgameAdjustment = pac.preEndgameAdjustmentValues[bagPlusRack]
+		log.Debug().Float64("peg-adjust", preEndgameAdjustment).Int("bagPlusRack", bagPlusRack).Msg("equity calc")
 	}
 
 	return preEndgameAdjustment
@@ -0,0 +1,107 @@
+package external
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// ScoreMismatch is a move that an engine and macondo score differently.
+type ScoreMismatch struct {
+	Move    string
+	Engine  int
+	Macondo int
+}
+
+// CrossCheckResult lists the differences between the moves an external
+// engine found and the moves macondo's generator found in a position.
+type CrossCheckResult struct {
+	// Invalid are the moves the engine found that macondo could not make
+	// at all, with the reason why.
+	Invalid []string
+	// Missing are moves the engine found that macondo did not.
+	Missing []string
+	// Unreturned are moves macondo found that the engine did not. These
+	// are only looked for if the engine returned all of its moves.
+	Unreturned []string
+	// Scores are the moves both found but scored differently.
+	Scores []ScoreMismatch
+}
+
+// OK returns whether the engine and macondo agree.
+func (r *CrossCheckResult) OK() bool {
+	return len(r.Invalid) == 0 && len(r.Missing) == 0 && len(r.Unreturned) == 0 &&
+		len(r.Scores) == 0
+}
+
+// Write writes out the differences, one per line, each after the prefix.
+func (r *CrossCheckResult) Write(w io.Writer, prefix string) {
+	for _, m := range r.Invalid {
+		fmt.Fprintf(w, "%sthe engine found an invalid move: %s\n", prefix, m)
+	}
+	for _, m := range r.Missing {
+		fmt.Fprintf(w, "%sonly the engine found %s\n", prefix, m)
+	}
+	for _, m := range r.Unreturned {
+		fmt.Fprintf(w, "%sonly macondo found %s\n", prefix, m)
+	}
+	for _, s := range r.Scores {
+		fmt.Fprintf(w, "%s%s scores %d for the engine, %d for macondo\n",
+			prefix, s.Move, s.Engine, s.Macondo)
+	}
+}
+
+// CrossCheck compares the moves an engine found in g, as it returned them,
+// with the moves macondo's generator found in the same position. complete
+// says whether the engine returned all of its moves, rather than only the
+// best ones.
+func CrossCheck(g *game.Game, resps []AnalyzeResponse, ourMoves []*move.Move, complete bool) *CrossCheckResult {
+	res := &CrossCheckResult{}
+	ours := map[string]*move.Move{}
+	for _, m := range ourMoves {
+		ours[moveKey(m)] = m
+	}
+	seen := map[string]bool{}
+	for i := range resps {
+		m, err := resps[i].Move(g)
+		if err != nil {
+			res.Invalid = append(res.Invalid, fmt.Sprintf("%+v: %v", resps[i], err))
+			continue
+		}
+		k := moveKey(m)
+		seen[k] = true
+		our, ok := ours[k]
+		if !ok {
+			res.Missing = append(res.Missing, k)
+			continue
+		}
+		if resps[i].Score != our.Score() {
+			res.Scores = append(res.Scores, ScoreMismatch{Move: k, Engine: resps[i].Score, Macondo: our.Score()})
+		}
+	}
+	if complete {
+		for _, m := range ourMoves {
+			if k := moveKey(m); !seen[k] {
+				res.Unreturned = append(res.Unreturned, k)
+			}
+		}
+	}
+	sort.Strings(res.Missing)
+	sort.Strings(res.Unreturned)
+	return res
+}
+
+// moveKey identifies a move regardless of the order tiles were exchanged
+// in.
+func moveKey(m *move.Move) string {
+	if m.Action() != move.MoveTypeExchange {
+		return m.Notation()
+	}
+	tiles := append(tilemapping.MachineWord(nil), m.Tiles()...)
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i] < tiles[j] })
+	return "-" + tiles.UserVisible(m.Alphabet())
+}
@@ -0,0 +1,316 @@
+// Package external talks to move generators that run outside of macondo,
+// such as wolges (github.com/andy-k/wolges-awsm), either over HTTP or
+// through a subprocess.
+//
+// Both speak the same protocol. A request is a JSON AnalyzeRequest, and
+// the answer is a JSON array of AnalyzeResponse, best move first. Over
+// HTTP, requests are posted to <url>/analyze. A subprocess reads one
+// request per line on its stdin, and writes one answer per line on its
+// stdout.
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var ErrNoEngine = errors.New("no external engine is configured")
+
+// DefaultTimeout is how long to wait for an engine's answer when there is
+// no other time limit. An engine that doesn't answer at all would
+// otherwise hold up its callers for good.
+var DefaultTimeout = 5 * time.Second
+
+// ExternalEngine is a move generator that runs out of process.
+type ExternalEngine interface {
+	// Analyze returns up to count of the best moves for the player on turn,
+	// best first.
+	Analyze(ctx context.Context, g *game.Game, count int) ([]*move.Move, error)
+	// AnalyzeRaw is like Analyze, but returns the engine's answer as is.
+	AnalyzeRaw(ctx context.Context, g *game.Game, count int) ([]AnalyzeResponse, error)
+	// Close releases the engine and anything it started.
+	Close() error
+}
+
+// AnalyzeRequest is what an engine is asked to analyze. Tiles are numbered
+// as in macondo's letter distributions, except that a blank on the board
+// is the negative of the letter it stands for. 0 is an empty square on the
+// board, and a blank on the rack.
+type AnalyzeRequest struct {
+	Rack    []int   `json:"rack"`
+	Board   [][]int `json:"board"`
+	Lexicon string  `json:"lexicon"`
+	Leave   string  `json:"leave"`
+	Rules   string  `json:"rules"`
+	Count   int     `json:"count"`
+}
+
+// AnalyzeResponse is a move an engine came up with. Action is "play" or
+// "exchange"; an exchange with no tiles is a pass. A play is along the row
+// (or, if Down, the column) Lane, starting at Idx. Its Word has a 0 for
+// every tile played through.
+type AnalyzeResponse struct {
+	Equity float32 `json:"equity"`
+	Action string  `json:"action"`
+	Tiles  []int   `json:"tiles"`
+	Down   bool    `json:"down"`
+	Lane   int     `json:"lane"`
+	Idx    int     `json:"idx"`
+	Word   []int   `json:"word"`
+	Score  int     `json:"score"`
+}
+
+// Mapping translates the names macondo has for lexica, leaves and
+// variants into the names an engine knows them by.
+type Mapping struct {
+	// Lexica renames lexica. Lexica that aren't in it keep their names.
+	Lexica map[string]string
+	// Languages gives the language of the lexica that start with each
+	// prefix. The longest prefix wins, and the default is english.
+	Languages map[string]string
+	// Leaves names the leaves of lexica whose leaves are not named after
+	// their language.
+	Leaves map[string]string
+	// Rules names the rules of each variant. The rules of languages other
+	// than english get a /language suffix.
+	Rules map[string]string
+}
+
+// MappingFromConfig returns the mapping in the config's external-engine
+// flags.
+func MappingFromConfig(cfg *config.Config) (*Mapping, error) {
+	m := &Mapping{}
+	var err error
+	for _, f := range []struct {
+		dest *map[string]string
+		flag string
+		val  string
+	}{
+		{&m.Lexica, "external-engine-lexica", cfg.ExternalEngineLexica},
+		{&m.Languages, "external-engine-languages", cfg.ExternalEngineLanguages},
+		{&m.Leaves, "external-engine-leaves", cfg.ExternalEngineLeaves},
+		{&m.Rules, "external-engine-rules", cfg.ExternalEngineRules},
+	} {
+		*f.dest, err = ParsePairs(f.val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.flag, err)
+		}
+	}
+	return m, nil
+}
+
+// ParsePairs parses comma-separated key=value pairs. Keys are lowercased.
+func ParsePairs(s string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		pairs[strings.ToLower(k)] = v
+	}
+	return pairs, nil
+}
+
+func (m *Mapping) language(lexicon string) string {
+	lexicon = strings.ToLower(lexicon)
+	prefixes := make([]string, 0, len(m.Languages))
+	for p := range m.Languages {
+		prefixes = append(prefixes, p)
+	}
+	// Longest first.
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	for _, p := range prefixes {
+		if strings.HasPrefix(lexicon, p) {
+			return m.Languages[p]
+		}
+	}
+	return "english"
+}
+
+// names returns the lexicon, leaves and rules to send the engine for a
+// game with the given lexicon and variant. Word smog variants use lexica
+// with a .WordSmog suffix.
+func (m *Mapping) names(lexicon string, variant game.Variant) (string, string, string) {
+	lang := m.language(lexicon)
+	leave, ok := m.Leaves[strings.ToLower(lexicon)]
+	if !ok {
+		leave = lang
+	}
+	if variant == "" {
+		variant = game.VarClassic
+	}
+	rules := m.Rules[string(variant)]
+	if lang != "english" {
+		rules += "/" + lang
+	}
+	lex, ok := m.Lexica[strings.ToLower(lexicon)]
+	if !ok {
+		lex = lexicon
+	}
+	if variant == game.VarWordSmog || variant == game.VarWordSmogSuper {
+		lex += ".WordSmog"
+	}
+	return lex, leave, rules
+}
+
+// NewRequest returns the request for the best count moves of the player on
+// turn in g.
+func NewRequest(g *game.Game, m *Mapping, count int) *AnalyzeRequest {
+	req := &AnalyzeRequest{Count: count}
+	req.Lexicon, req.Leave, req.Rules = m.names(g.LexiconName(), g.Rules().Variant())
+
+	dim := g.Board().Dim()
+	req.Board = make([][]int, dim)
+	for i := 0; i < dim; i++ {
+		req.Board[i] = make([]int, dim)
+		for j := 0; j < dim; j++ {
+			letter := g.Board().GetLetter(i, j)
+			code := int(letter)
+			if letter.IsBlanked() {
+				code = -int(letter.Unblank())
+			}
+			req.Board[i][j] = code
+		}
+	}
+	req.Rack = []int{}
+	for _, t := range g.RackFor(g.PlayerOnTurn()).TilesOn() {
+		req.Rack = append(req.Rack, int(t))
+	}
+	return req
+}
+
+// Move turns the response into a move for the player on turn in g, scored
+// by macondo.
+func (r *AnalyzeResponse) Move(g *game.Game) (*move.Move, error) {
+	tm := g.Alphabet()
+	rack := g.RackFor(g.PlayerOnTurn())
+	var m *move.Move
+	switch r.Action {
+	case "exchange":
+		if len(r.Tiles) == 0 {
+			m = move.NewPassMove(rack.TilesOn(), tm)
+			break
+		}
+		tiles := make(tilemapping.MachineWord, len(r.Tiles))
+		for i, t := range r.Tiles {
+			tiles[i] = tilemapping.MachineLetter(t)
+		}
+		leave, err := tilemapping.Leave(rack.TilesOn(), tiles, true)
+		if err != nil {
+			return nil, err
+		}
+		m = move.NewExchangeMove(tiles, leave, tm)
+
+	case "play":
+		row, col := r.Lane, r.Idx
+		if r.Down {
+			row, col = r.Idx, r.Lane
+		}
+		var word strings.Builder
+		for _, t := range r.Word {
+			switch {
+			case t == 0:
+				word.WriteRune(tilemapping.ASCIIPlayedThrough)
+			case t < 0:
+				word.WriteString(tm.Letter(tilemapping.MachineLetter(-t).Blank()))
+			default:
+				word.WriteString(tm.Letter(tilemapping.MachineLetter(t)))
+			}
+		}
+		var err error
+		m, err = g.CreateAndScorePlacementMove(move.ToBoardGameCoords(row, col, r.Down),
+			word.String(), g.RackLettersFor(g.PlayerOnTurn()))
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown action %q", r.Action)
+	}
+	m.SetEquity(float64(r.Equity))
+	return m, nil
+}
+
+// transport sends a request to an engine and returns its raw answer.
+type transport interface {
+	roundTrip(ctx context.Context, req []byte) ([]byte, error)
+	Close() error
+}
+
+type engine struct {
+	transport
+	mapping *Mapping
+}
+
+// New returns the external engine the config asks for: one served at
+// external-engine-url (or the older wolges-awsm-url), or else one run by
+// external-engine-cmd.
+func New(cfg *config.Config) (ExternalEngine, error) {
+	m, err := MappingFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	url := cfg.ExternalEngineURL
+	if url == "" {
+		url = cfg.WolgesAwsmURL
+	}
+	switch {
+	case url != "":
+		return NewHTTPEngine(url, m), nil
+	case cfg.ExternalEngineCmd != "":
+		return NewProcessEngine(strings.Fields(cfg.ExternalEngineCmd), m)
+	}
+	return nil, ErrNoEngine
+}
+
+func (e *engine) AnalyzeRaw(ctx context.Context, g *game.Game, count int) ([]AnalyzeResponse, error) {
+	bts, err := json.Marshal(NewRequest(g, e.mapping, count))
+	if err != nil {
+		return nil, err
+	}
+	log.Debug().Str("payload", string(bts)).Msg("sending-to-external-engine")
+	raw, err := e.roundTrip(ctx, bts)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug().Str("body", string(raw)).Msg("raw-from-external-engine")
+	var resps []AnalyzeResponse
+	err = json.Unmarshal(raw, &resps)
+	if err != nil {
+		return nil, err
+	}
+	return resps, nil
+}
+
+func (e *engine) Analyze(ctx context.Context, g *game.Game, count int) ([]*move.Move, error) {
+	resps, err := e.AnalyzeRaw(ctx, g, count)
+	if err != nil {
+		return nil, err
+	}
+	moves := make([]*move.Move, 0, len(resps))
+	for i := range resps {
+		m, err := resps[i].Move(g)
+		if err != nil {
+			return nil, fmt.Errorf("move %d from external engine: %w", i, err)
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
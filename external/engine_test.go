@@ -0,0 +1,271 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// fakeEngineEnv makes the test binary run as a fake engine subprocess. Its
+// value is the fake's mode: "answer" or "hang".
+const fakeEngineEnv = "MACONDO_FAKE_ENGINE"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakeEngineEnv); mode != "" {
+		runFakeEngine(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeAnswer answers every request with the same few moves for the
+// position in testGame.
+func fakeAnswer(req *AnalyzeRequest) []AnalyzeResponse {
+	if len(req.Rack) == 0 {
+		return []AnalyzeResponse{}
+	}
+	resps := []AnalyzeResponse{
+		{Equity: 20.5, Action: "play", Down: true, Lane: 7, Idx: 5, Word: []int{20, 15, 0}, Score: 8},
+		{Equity: 3, Action: "exchange", Tiles: []int{7, 12}},
+		{Equity: -10, Action: "exchange"},
+	}
+	if req.Count < len(resps) {
+		resps = resps[:req.Count]
+	}
+	return resps
+}
+
+func runFakeEngine(mode string) {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		if mode == "hang" {
+			select {}
+		}
+		req := &AnalyzeRequest{}
+		if err := json.Unmarshal(scanner.Bytes(), req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		bts, _ := json.Marshal(fakeAnswer(req))
+		fmt.Printf("%s\n", bts)
+	}
+}
+
+// testGame returns a game where the first player played 8D STaIR, and the
+// second player has AEGLNOT.
+func testGame(is *is.I) *game.Game {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, game.VarClassic)
+	is.NoErr(err)
+	g, err := game.NewGame(rules, []*pb.PlayerInfo{
+		{Nickname: "p1", RealName: "Player 1"},
+		{Nickname: "p2", RealName: "Player 2"},
+	})
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	is.NoErr(g.SetRackFor(0, tilemapping.RackFromString("?EINRST", g.Alphabet())))
+	m, err := g.CreateAndScorePlacementMove("8D", "STaIR", "?EINRST")
+	is.NoErr(err)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.NoErr(g.SetRackFor(1, tilemapping.RackFromString("AEGLNOT", g.Alphabet())))
+	return g
+}
+
+func testMapping(is *is.I) *Mapping {
+	cfg := DefaultConfig
+	is.NoErr(cfg.Load([]string{}))
+	m, err := MappingFromConfig(&cfg)
+	is.NoErr(err)
+	return m
+}
+
+func TestNames(t *testing.T) {
+	is := is.New(t)
+	m := testMapping(is)
+	for _, tc := range []struct {
+		lexicon, lex, leave, rules string
+		variant                    game.Variant
+	}{
+		{"NWL20", "NWL20", "english", "CrosswordGame", ""},
+		{"CSW21", "CSW21", "CSW21", "CrosswordGame", game.VarClassic},
+		{"RD28", "RD28", "german", "CrosswordGame/german", game.VarClassic},
+		{"NSF22", "NSF22", "norwegian", "CrosswordGameSuper/norwegian", game.VarClassicSuper},
+		{"CSW21", "CSW21.WordSmog", "CSW21", "WordSmog", game.VarWordSmog},
+	} {
+		lex, leave, rules := m.names(tc.lexicon, tc.variant)
+		is.Equal(lex, tc.lex)
+		is.Equal(leave, tc.leave)
+		is.Equal(rules, tc.rules)
+	}
+
+	m.Lexica, _ = ParsePairs("NWL20=NWL2020")
+	m.Languages, _ = ParsePairs("d=dutch,disc=catalan")
+	lex, leave, rules := m.names("NWL20", game.VarClassic)
+	is.Equal([]string{lex, leave, rules}, []string{"NWL2020", "english", "CrosswordGame"})
+	// The longest prefix wins.
+	_, leave, _ = m.names("DISC2", game.VarClassic)
+	is.Equal(leave, "catalan")
+
+	_, err := ParsePairs("classic")
+	is.True(err != nil)
+}
+
+func TestNewRequest(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+	req := NewRequest(g, testMapping(is), 5)
+	is.Equal(req.Count, 5)
+	is.Equal(req.Rack, []int{1, 5, 7, 12, 14, 15, 20})
+	is.Equal(req.Board[7][2:9], []int{0, 19, 20, -1, 9, 18, 0})
+	is.Equal(req.Board[6][5], 0)
+}
+
+func TestResponseMoves(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+	moves := []*move.Move{}
+	for _, r := range fakeAnswer(&AnalyzeRequest{Rack: []int{1}, Count: 10}) {
+		m, err := r.Move(g)
+		is.NoErr(err)
+		moves = append(moves, m)
+	}
+	is.Equal(moves[0].Notation(), "H6 TO.")
+	is.Equal(moves[0].Score(), 3)
+	is.Equal(moves[0].Equity(), 20.5)
+	is.Equal(moves[0].LeaveString(), "AEGLN")
+	is.Equal(moves[1].Notation(), "-GL")
+	is.Equal(moves[1].LeaveString(), "AENOT")
+	is.Equal(moves[2].Action(), move.MoveTypePass)
+
+	_, err := (&AnalyzeResponse{Action: "exchange", Tiles: []int{26}}).Move(g)
+	is.True(err != nil)
+	_, err = (&AnalyzeResponse{Action: "resign"}).Move(g)
+	is.Equal(err.Error(), `unknown action "resign"`)
+}
+
+func TestHTTPEngine(t *testing.T) {
+	is := is.New(t)
+	var got *AnalyzeRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/analyze" {
+			http.NotFound(w, r)
+			return
+		}
+		got = &AnalyzeRequest{}
+		json.NewDecoder(r.Body).Decode(got)
+		json.NewEncoder(w).Encode(fakeAnswer(got))
+	}))
+	defer ts.Close()
+
+	g := testGame(is)
+	eng := NewHTTPEngine(ts.URL+"/", testMapping(is))
+	defer eng.Close()
+	moves, err := eng.Analyze(context.Background(), g, 2)
+	is.NoErr(err)
+	is.Equal(len(moves), 2)
+	is.Equal(moves[0].Notation(), "H6 TO.")
+	is.Equal(got.Count, 2)
+	is.Equal(got.Rules, "CrosswordGame")
+
+	eng = NewHTTPEngine(ts.URL+"/nothing", testMapping(is))
+	_, err = eng.Analyze(context.Background(), g, 2)
+	is.True(err != nil)
+}
+
+func TestProcessEngine(t *testing.T) {
+	is := is.New(t)
+	t.Setenv(fakeEngineEnv, "answer")
+	g := testGame(is)
+	eng, err := NewProcessEngine([]string{os.Args[0]}, testMapping(is))
+	is.NoErr(err)
+	defer eng.Close()
+
+	// The same process answers every request.
+	for i := 0; i < 2; i++ {
+		moves, err := eng.Analyze(context.Background(), g, 3)
+		is.NoErr(err)
+		is.Equal(len(moves), 3)
+		is.Equal(moves[1].Notation(), "-GL")
+	}
+	is.NoErr(eng.Close())
+	// and it is started again after it is closed.
+	resps, err := eng.AnalyzeRaw(context.Background(), g, 1)
+	is.NoErr(err)
+	is.Equal(len(resps), 1)
+}
+
+func TestProcessEngineTimeout(t *testing.T) {
+	is := is.New(t)
+	t.Setenv(fakeEngineEnv, "hang")
+	g := testGame(is)
+	eng, err := NewProcessEngine([]string{os.Args[0]}, testMapping(is))
+	is.NoErr(err)
+	defer eng.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = eng.Analyze(ctx, g, 3)
+	is.Equal(err, context.DeadlineExceeded)
+
+	// The engine that hung was killed, and a new one takes its place.
+	t.Setenv(fakeEngineEnv, "answer")
+	moves, err := eng.Analyze(context.Background(), g, 3)
+	is.NoErr(err)
+	is.Equal(len(moves), 3)
+}
+
+func TestCrossCheck(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+	rack := g.RackLettersFor(1)
+	ours := []*move.Move{}
+	for _, tiles := range []struct{ coords, word string }{{"H6", "TO."}, {"H4", "GLOT."}} {
+		m, err := g.CreateAndScorePlacementMove(tiles.coords, tiles.word, rack)
+		is.NoErr(err)
+		ours = append(ours, m)
+	}
+	exch, err := (&AnalyzeResponse{Action: "exchange", Tiles: []int{12, 7}}).Move(g)
+	is.NoErr(err)
+	ours = append(ours, exch)
+
+	resps := fakeAnswer(&AnalyzeRequest{Rack: []int{1}, Count: 2})
+	resps = append(resps,
+		// A play macondo missed.
+		AnalyzeResponse{Action: "play", Down: true, Lane: 7, Idx: 4, Word: []int{1, 20, 15, 0}, Score: 7},
+		// A play with a tile that isn't on the rack.
+		AnalyzeResponse{Action: "play", Down: true, Lane: 7, Idx: 6, Word: []int{26, 0}, Score: 11},
+	)
+	res := CrossCheck(g, resps, ours, false)
+	is.True(!res.OK())
+	is.Equal(res.Missing, []string{"H5 ATO."})
+	is.Equal(len(res.Invalid), 1)
+	// The fake scores TO. wrong, and exchanges are the same in any order.
+	is.Equal(res.Scores, []ScoreMismatch{{Move: "H6 TO.", Engine: 8, Macondo: 3}})
+	is.Equal(len(res.Unreturned), 0)
+
+	res = CrossCheck(g, resps, ours, true)
+	is.Equal(res.Unreturned, []string{"H4 GLOT."})
+
+	resps[0].Score = 3
+	res = CrossCheck(g, resps[:2], ours, false)
+	is.True(res.OK())
+}
@@ -0,0 +1,49 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type httpTransport struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPEngine returns an engine served over HTTP at url, such as
+// wolges-awsm.
+func NewHTTPEngine(url string, m *Mapping) ExternalEngine {
+	return &engine{
+		transport: &httpTransport{url: strings.TrimSuffix(url, "/"), client: http.DefaultClient},
+		mapping:   m,
+	}
+}
+
+func (t *httpTransport) roundTrip(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url+"/analyze", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("external engine replied %s: %s", resp.Status, bytes.TrimSpace(raw))
+	}
+	return raw, nil
+}
+
+func (t *httpTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxLineSize is the longest answer a subprocess may write.
+const maxLineSize = 64 * 1024 * 1024
+
+var errEngineExited = errors.New("external engine exited")
+
+// processTransport runs an engine as a subprocess. If the engine takes too
+// long to answer, it is killed, and started again for the next request.
+type processTransport struct {
+	sync.Mutex
+	args []string
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+}
+
+// NewProcessEngine starts an engine that runs the command in args, and
+// talks to it over its stdin and stdout. Its stderr goes to ours.
+func NewProcessEngine(args []string, m *Mapping) (ExternalEngine, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no command for the external engine")
+	}
+	t := &processTransport{args: args}
+	if err := t.start(); err != nil {
+		return nil, err
+	}
+	return &engine{transport: t, mapping: m}, nil
+}
+
+func (t *processTransport) start() error {
+	cmd := exec.Command(t.args[0], t.args[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	t.cmd = cmd
+	t.stdin = stdin
+	t.stdout = bufio.NewScanner(stdout)
+	t.stdout.Buffer(nil, maxLineSize)
+	log.Debug().Strs("args", t.args).Int("pid", cmd.Process.Pid).Msg("started-external-engine")
+	return nil
+}
+
+// stop kills the subprocess, if it is running, and waits for it to exit.
+func (t *processTransport) stop() error {
+	if t.cmd == nil {
+		return nil
+	}
+	t.stdin.Close()
+	err := t.cmd.Process.Kill()
+	t.cmd.Wait()
+	t.cmd = nil
+	if errors.Is(err, os.ErrProcessDone) {
+		return nil
+	}
+	return err
+}
+
+func (t *processTransport) roundTrip(ctx context.Context, req []byte) ([]byte, error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.cmd == nil {
+		if err := t.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	type answer struct {
+		line []byte
+		err  error
+	}
+	answers := make(chan answer, 1)
+	stdin, stdout := t.stdin, t.stdout
+	go func() {
+		_, err := stdin.Write(append(req, '\n'))
+		if err != nil {
+			answers <- answer{err: err}
+			return
+		}
+		if !stdout.Scan() {
+			err = stdout.Err()
+			if err == nil {
+				err = errEngineExited
+			}
+			answers <- answer{err: err}
+			return
+		}
+		// The scanner reuses its buffer.
+		answers <- answer{line: append([]byte(nil), stdout.Bytes()...)}
+	}()
+
+	select {
+	case a := <-answers:
+		if a.err != nil {
+			t.stop()
+		}
+		return a.line, a.err
+	case <-ctx.Done():
+		// The engine could still answer this request later, which would
+		// mix up the answers to the next ones.
+		log.Info().Msg("external-engine-timed-out")
+		t.stop()
+		return nil, ctx.Err()
+	}
+}
+
+func (t *processTransport) Close() error {
+	t.Lock()
+	defer t.Unlock()
+	return t.stop()
+}
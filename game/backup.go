@@ -161,6 +161,9 @@ func (g *Game) Copy() *Game {
 		playing:           g.playing,
 		scorelessTurns:    g.scorelessTurns,
 		maxScorelessTurns: g.maxScorelessTurns,
+		rackSize:          g.rackSize,
+		exchangeLimit:     g.exchangeLimit,
+		rules:             g.rules,
 		players:           copyPlayers(g.players),
 		// stackPtr only changes during a sim, etc. This Copy should
 		// only be called at the beginning of everything.
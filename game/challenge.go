@@ -38,6 +38,9 @@ func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
 	if len(g.lastWordsFormed) == 0 {
 		return false, errors.New("there are no words to challenge")
 	}
+	if g.clock != nil {
+		millis = int(g.clock.Remaining(g.onturn).Milliseconds())
+	}
 	// Note that the player on turn right now needs to be the player
 	// who is making the challenge.
 	illegalWords := validateWords(g.lexicon, g.lastWordsFormed, g.rules.Variant())
@@ -46,7 +49,8 @@ func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
 	lastEvent := g.history.Events[len(g.history.Events)-1]
 	cumeScoreBeforeChallenge := lastEvent.Cumulative
 
-	challengee := otherPlayer(g.onturn)
+	// The challengee is the player who made the last play.
+	challengee := g.prevPlayerIdx(g.onturn)
 
 	offBoardEvent := &pb.GameEvent{
 		PlayerIndex: lastEvent.PlayerIndex,
@@ -105,13 +109,14 @@ func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
 		// We must also set the last known rack of the challengee back to
 		// their rack before they played the phony.
 		g.history.LastKnownRacks[challengee] = lastEvent.Rack
-		// Explicitly set racks for both players. This prevents a bug where
+		// Explicitly set racks for all players. This prevents a bug where
 		// part of the game may have been loaded from a GameHistory (through the
 		// PlayGameToTurn flow) and the racks continually get reset.
-		g.SetRacksForBoth([]*tilemapping.Rack{
-			tilemapping.RackFromString(g.history.LastKnownRacks[0], g.alph),
-			tilemapping.RackFromString(g.history.LastKnownRacks[1], g.alph),
-		})
+		racks := make([]*tilemapping.Rack, len(g.players))
+		for pidx := range racks {
+			racks[pidx] = tilemapping.RackFromString(g.history.LastKnownRacks[pidx], g.alph)
+		}
+		g.SetRacksForBoth(racks)
 
 		// Note that if backup mode is InteractiveGameplayMode, which it should be,
 		// we do not back up the turn number. So restoring it doesn't change
@@ -184,7 +189,7 @@ func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
 			// do calculations with the player on turn being the player who
 			// didn't challenge, as this is a special event where the turn
 			// did not _actually_ change.
-			g.endOfGameCalcs(otherPlayer(g.onturn), true)
+			g.endOfGameCalcs(challengee, true)
 			g.AddFinalScoresToHistory()
 		}
 
@@ -0,0 +1,263 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+// DefaultOvertimePenalty is the number of points a player loses for every
+// minute (or part of a minute) they go over time.
+const DefaultOvertimePenalty = 10
+
+// TimeControl describes the clock settings for a timed game.
+type TimeControl struct {
+	// InitialTime is the time every player starts the game with.
+	InitialTime time.Duration
+	// Increment is added to a player's clock after every one of their turns.
+	Increment time.Duration
+	// MaxOvertime is how far over time a player can go before losing on
+	// time. Zero means there is no limit.
+	MaxOvertime time.Duration
+	// OvertimePenalty is the number of points lost per started minute
+	// of overtime.
+	OvertimePenalty int
+}
+
+// NewTimeControl returns a time control with the given initial time and
+// increment, the default overtime penalty, and no overtime limit.
+func NewTimeControl(initial, increment time.Duration) TimeControl {
+	return TimeControl{
+		InitialTime:     initial,
+		Increment:       increment,
+		OvertimePenalty: DefaultOvertimePenalty,
+	}
+}
+
+func (tc TimeControl) validate() error {
+	if tc.InitialTime <= 0 {
+		return errors.New("initial time must be positive")
+	}
+	if tc.Increment < 0 || tc.MaxOvertime < 0 || tc.OvertimePenalty < 0 {
+		return errors.New("increment, max overtime and overtime penalty cannot be negative")
+	}
+	return nil
+}
+
+// historyTimers returns the timers of a game history that starts with this
+// time control.
+func (tc TimeControl) historyTimers() *pb.GameTimers {
+	return &pb.GameTimers{
+		InitialMillis:     tc.InitialTime.Milliseconds(),
+		IncrementMillis:   tc.Increment.Milliseconds(),
+		MaxOvertimeMillis: tc.MaxOvertime.Milliseconds(),
+	}
+}
+
+// HistoryTimeControl returns the time control of a game history, or nil if
+// the game is untimed. If the history doesn't say how much time the
+// players started with, the most anyone has left is used instead.
+func HistoryTimeControl(h *pb.GameHistory) *TimeControl {
+	timers := h.GetTimers()
+	if timers == nil {
+		return nil
+	}
+	initial := time.Duration(timers.InitialMillis) * time.Millisecond
+	if initial <= 0 {
+		initial = time.Millisecond
+		for _, ms := range timers.MillisRemaining {
+			if t := time.Duration(ms) * time.Millisecond; t > initial {
+				initial = t
+			}
+		}
+	}
+	tc := NewTimeControl(initial, time.Duration(timers.IncrementMillis)*time.Millisecond)
+	tc.MaxOvertime = time.Duration(timers.MaxOvertimeMillis) * time.Millisecond
+	return &tc
+}
+
+// Clock keeps track of the time remaining for every player in a game.
+// At most one player's clock runs at any time. A player's remaining time
+// goes negative once they are in overtime.
+type Clock struct {
+	tc        TimeControl
+	remaining []time.Duration
+	running   int
+	started   time.Time
+	now       func() time.Time
+}
+
+// NewClock creates a stopped clock for the given number of players.
+func NewClock(tc TimeControl, nplayers int) *Clock {
+	c := &Clock{tc: tc, now: time.Now}
+	c.remaining = make([]time.Duration, nplayers)
+	c.Reset()
+	return c
+}
+
+// Reset stops the clock and gives every player the initial time again.
+func (c *Clock) Reset() {
+	for i := range c.remaining {
+		c.remaining[i] = c.tc.InitialTime
+	}
+	c.running = -1
+}
+
+// SetNowFunc replaces the function the clock uses to read the current
+// time. This is meant for tests.
+func (c *Clock) SetNowFunc(f func() time.Time) {
+	c.now = f
+}
+
+// TimeControl returns the settings of this clock.
+func (c *Clock) TimeControl() TimeControl {
+	return c.tc
+}
+
+// Start starts the given player's clock. If another player's clock was
+// running, it is stopped without charging it.
+func (c *Clock) Start(pidx int) {
+	c.running = pidx
+	c.started = c.now()
+}
+
+// Running returns the index of the player whose clock is running, or -1
+// if the clock is stopped.
+func (c *Clock) Running() int {
+	return c.running
+}
+
+// Stop charges the running player for the time since their clock was
+// started and stops the clock. It returns the running player's remaining
+// time. If the clock was already stopped, it returns 0.
+func (c *Clock) Stop() time.Duration {
+	if c.running < 0 {
+		return 0
+	}
+	pidx := c.running
+	c.remaining[pidx] -= c.now().Sub(c.started)
+	c.running = -1
+	return c.remaining[pidx]
+}
+
+// EndTurn stops the clock like Stop, and then adds the increment to the
+// player who just moved.
+func (c *Clock) EndTurn() time.Duration {
+	pidx := c.running
+	if pidx < 0 {
+		return 0
+	}
+	c.Stop()
+	c.remaining[pidx] += c.tc.Increment
+	return c.remaining[pidx]
+}
+
+// Remaining returns the time left for the given player, counting the time
+// used so far if their clock is running.
+func (c *Clock) Remaining(pidx int) time.Duration {
+	if pidx == c.running {
+		return c.remaining[pidx] - c.now().Sub(c.started)
+	}
+	return c.remaining[pidx]
+}
+
+// SetRemaining sets the time left for the given player.
+func (c *Clock) SetRemaining(pidx int, d time.Duration) {
+	c.remaining[pidx] = d
+	if pidx == c.running {
+		c.started = c.now()
+	}
+}
+
+// Overtime returns how far over time the given player is.
+func (c *Clock) Overtime(pidx int) time.Duration {
+	r := c.Remaining(pidx)
+	if r >= 0 {
+		return 0
+	}
+	return -r
+}
+
+// Penalty returns the number of points the given player loses for their
+// overtime. Every started minute counts as a full minute.
+func (c *Clock) Penalty(pidx int) int {
+	ot := c.Overtime(pidx)
+	if ot == 0 {
+		return 0
+	}
+	minutes := int((ot + time.Minute - 1) / time.Minute)
+	return minutes * c.tc.OvertimePenalty
+}
+
+// OutOfTime returns whether the given player has gone over the maximum
+// overtime allowed.
+func (c *Clock) OutOfTime(pidx int) bool {
+	return c.tc.MaxOvertime > 0 && c.Overtime(pidx) > c.tc.MaxOvertime
+}
+
+// Clock returns the game clock, or nil if the game is not timed.
+func (g *Game) Clock() *Clock {
+	return g.clock
+}
+
+// startClock starts the clock of the player on turn, if the game is timed
+// and still going.
+func (g *Game) startClock() {
+	if g.clock == nil || g.playing == pb.PlayState_GAME_OVER {
+		return
+	}
+	g.clock.Start(g.onturn)
+}
+
+// addTimePenalties takes the overtime penalty away from every player that
+// went over time. It is called once the game is over.
+func (g *Game) addTimePenalties() {
+	if g.clock == nil || g.timePenaltiesApplied {
+		return
+	}
+	g.clock.Stop()
+	g.timePenaltiesApplied = true
+	for pidx, p := range g.players {
+		penalty := g.clock.Penalty(pidx)
+		if penalty == 0 {
+			continue
+		}
+		p.points -= penalty
+		g.turnnum++
+		g.addEventToHistory(&pb.GameEvent{
+			PlayerIndex:     uint32(pidx),
+			Type:            pb.GameEvent_TIME_PENALTY,
+			Rack:            p.rackLetters(),
+			LostScore:       int32(penalty),
+			Cumulative:      int32(p.points),
+			MillisRemaining: int32(g.clock.Remaining(pidx).Milliseconds()),
+		})
+	}
+}
+
+// CheckTimeout ends the game if the player on turn has gone over the
+// maximum overtime. The player who ran out of time loses, no matter the
+// score. It returns whether the game ended.
+func (g *Game) CheckTimeout() bool {
+	if g.clock == nil || g.playing == pb.PlayState_GAME_OVER ||
+		!g.clock.OutOfTime(g.onturn) {
+		return false
+	}
+	loser := g.onturn
+	g.playing = pb.PlayState_GAME_OVER
+	g.history.PlayState = g.playing
+	g.history.EndReason = pb.GameEndReason_TIME
+	g.AddFinalScoresToHistory()
+	if int(g.history.Winner) == loser || g.history.Winner == -1 {
+		// Give the win to the best-scoring player who didn't time out.
+		winner := -1
+		for pidx := range g.players {
+			if pidx != loser && (winner == -1 || g.players[pidx].points > g.players[winner].points) {
+				winner = pidx
+			}
+		}
+		g.history.Winner = int32(winner)
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+)
+
+type fakeTime struct {
+	t time.Time
+}
+
+func (f *fakeTime) now() time.Time {
+	return f.t
+}
+
+func (f *fakeTime) advance(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+func newTimedGame(is *is.I, tc TimeControl, ft *fakeTime) *Game {
+	players := []*pb.PlayerInfo{
+		{Nickname: "JD", RealName: "Jesse"},
+		{Nickname: "cesar", RealName: "César"},
+	}
+	rules, err := NewBasicGameRules(
+		&DefaultConfig, "", board.CrosswordGameLayout, "english",
+		CrossScoreOnly, "")
+	is.NoErr(err)
+	is.NoErr(rules.SetTimeControl(tc))
+	g, err := NewGame(rules, players)
+	is.NoErr(err)
+	g.Clock().SetNowFunc(ft.now)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	return g
+}
+
+func TestClockIncrementAndPenalty(t *testing.T) {
+	is := is.New(t)
+	ft := &fakeTime{t: time.Unix(0, 0)}
+	c := NewClock(NewTimeControl(time.Minute, 5*time.Second), 2)
+	c.SetNowFunc(ft.now)
+	is.Equal(c.Running(), -1)
+
+	c.Start(0)
+	ft.advance(20 * time.Second)
+	is.Equal(c.Remaining(0), 40*time.Second)
+	is.Equal(c.EndTurn(), 45*time.Second)
+	is.Equal(c.Running(), -1)
+
+	// Stopping doesn't add the increment.
+	c.Start(1)
+	ft.advance(2*time.Minute + time.Second)
+	is.Equal(c.Stop(), -61*time.Second)
+	is.Equal(c.Overtime(1), 61*time.Second)
+	// Every started minute counts.
+	is.Equal(c.Penalty(1), 20)
+	is.Equal(c.Penalty(0), 0)
+	is.True(!c.OutOfTime(1))
+}
+
+func TestTimedGamePenalties(t *testing.T) {
+	is := is.New(t)
+	ft := &fakeTime{t: time.Unix(0, 0)}
+	g := newTimedGame(is, NewTimeControl(time.Minute, 0), ft)
+
+	// JD takes their time over every pass.
+	for i := 0; i < 6; i++ {
+		if g.PlayerOnTurn() == 0 {
+			ft.advance(40 * time.Second)
+		} else {
+			ft.advance(time.Second)
+		}
+		is.NoErr(g.PlayMove(move.NewPassMove(nil, g.Alphabet()), true, 0))
+	}
+	is.Equal(g.Playing(), pb.PlayState_GAME_OVER)
+	is.Equal(g.History().Events[0].MillisRemaining, int32(20000))
+	is.Equal(g.History().Events[1].MillisRemaining, int32(59000))
+
+	// 6 passes, 2 rack penalties and 1 time penalty.
+	evts := g.History().Events
+	is.Equal(len(evts), 9)
+	last := evts[len(evts)-1]
+	is.Equal(last.Type, pb.GameEvent_TIME_PENALTY)
+	is.Equal(last.PlayerIndex, uint32(0))
+	// 60 seconds over time is one minute.
+	is.Equal(last.LostScore, int32(10))
+	is.Equal(last.MillisRemaining, int32(-60000))
+	is.Equal(g.History().FinalScores[0], int32(g.PointsFor(0)))
+	is.Equal(g.Clock().Running(), -1)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	is := is.New(t)
+	ft := &fakeTime{t: time.Unix(0, 0)}
+	tc := NewTimeControl(time.Minute, 0)
+	tc.MaxOvertime = time.Minute
+	g := newTimedGame(is, tc, ft)
+
+	ft.advance(time.Second)
+	is.NoErr(g.PlayMove(move.NewPassMove(nil, g.Alphabet()), true, 0))
+	ft.advance(90 * time.Second)
+	is.True(!g.CheckTimeout())
+	ft.advance(31 * time.Second)
+	is.True(g.CheckTimeout())
+	is.Equal(g.Playing(), pb.PlayState_GAME_OVER)
+	// cesar loses on time, and 20 points for two started minutes over.
+	is.Equal(g.History().Winner, int32(0))
+	is.Equal(g.History().EndReason, pb.GameEndReason_TIME)
+	is.Equal(g.PointsFor(1), -20)
+}
+
+func TestHistoryTimeControl(t *testing.T) {
+	is := is.New(t)
+	ft := &fakeTime{t: time.Unix(0, 0)}
+	tc := NewTimeControl(25*time.Minute, 5*time.Second)
+	tc.MaxOvertime = time.Minute
+	g := newTimedGame(is, tc, ft)
+
+	// The history of a timed game has its time control, which can be
+	// read back.
+	h := g.History()
+	is.Equal(h.Timers.InitialMillis, int64(1500000))
+	is.Equal(h.Timers.IncrementMillis, int64(5000))
+	is.Equal(h.Timers.MaxOvertimeMillis, int64(60000))
+	is.Equal(*HistoryTimeControl(h), tc)
+
+	// Without the initial time, the most time left stands in for it.
+	h.Timers = &pb.GameTimers{IncrementMillis: 5000, MillisRemaining: []int64{62000, 1000}}
+	is.Equal(HistoryTimeControl(h).InitialTime, 62*time.Second)
+	is.Equal(HistoryTimeControl(&pb.GameHistory{}), (*TimeControl)(nil))
+}
@@ -57,22 +57,23 @@ func (g *Game) ToDisplayText() string {
 	vpadding := 1
 	bagColCount := 20
 
+	// Games with more than two players push everything below the player
+	// list down.
+	extra := g.NumPlayers() - 2
+
 	log.Debug().Int("onturn", g.onturn).Msg("todisplaytext")
-	for pi := 0; pi < 2; pi++ {
+	for pi := 0; pi < g.NumPlayers(); pi++ {
 		addText(bts, vpadding+pi, hpadding,
 			g.players[pi].stateString(g.playing == pb.PlayState_PLAYING && g.onturn == pi))
 	}
 
-	// Peek into the bag, and append the opponent's tiles:
-	inbag := g.bag.Peek()
-	opprack := g.players[otherPlayer(g.onturn)].rack.TilesOn()
-	bagAndUnseen := append(inbag, opprack...)
-	log.Debug().Str("inbag", tilemapping.MachineWord(inbag).UserVisible(g.alph)).Msg("")
-	log.Debug().Str("opprack", tilemapping.MachineWord(opprack).UserVisible(g.alph)).Msg("")
+	// The bag plus the opponents' tiles:
+	bagAndUnseen := g.UnseenTiles(g.onturn)
+	log.Debug().Str("unseen", tilemapping.MachineWord(bagAndUnseen).UserVisible(g.alph)).Msg("")
 
-	addText(bts, vpadding+3, hpadding, fmt.Sprintf("Bag + unseen: (%d)", len(bagAndUnseen)))
+	addText(bts, vpadding+3+extra, hpadding, fmt.Sprintf("Bag + unseen: (%d)", len(bagAndUnseen)))
 
-	vpadding = 6
+	vpadding = 6 + extra
 	sort.Slice(bagAndUnseen, func(i, j int) bool {
 		return bagAndUnseen[i] < bagAndUnseen[j]
 	})
@@ -97,9 +98,9 @@ func (g *Game) ToDisplayText() string {
 		addText(bts, p, hpadding, bagDisp[p-vpadding])
 	}
 
-	addText(bts, 12, hpadding, fmt.Sprintf("Turn %d:", g.turnnum))
+	addText(bts, 12+extra, hpadding, fmt.Sprintf("Turn %d:", g.turnnum))
 
-	vpadding = 13
+	vpadding = 13 + extra
 
 	for i, evt := range g.history.Events {
 		log.Debug().Msgf("Event %d: %v", i, evt)
@@ -110,7 +111,7 @@ func (g *Game) ToDisplayText() string {
 			summary(g.history.Players, g.history.Events[g.turnnum-1]))
 	}
 
-	vpadding = 17
+	vpadding = 17 + extra
 
 	if g.playing == pb.PlayState_GAME_OVER && g.turnnum == len(g.history.Events) {
 		addText(bts, vpadding, hpadding, "Game is over.")
@@ -118,7 +119,7 @@ func (g *Game) ToDisplayText() string {
 	if g.playing == pb.PlayState_WAITING_FOR_FINAL_PASS {
 		addText(bts, vpadding, hpadding, "Waiting for final pass/challenge...")
 	}
-	vpadding = 19
+	vpadding = 19 + extra
 	if g.turnnum-1 < len(g.history.Events) && g.turnnum-1 >= 0 &&
 		g.history.Events[g.turnnum-1].Note != "" {
 		// add it all the way at the bottom
@@ -5,6 +5,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/domino14/macondo/board"
@@ -24,13 +25,28 @@ const (
 
 	MacondoCreation = "Created with Macondo"
 
+	// ExchangeLimit and RackTileLimit are the defaults for a standard game.
+	// The actual values for a game come from its GameRules; see
+	// (*Game).ExchangeLimit and (*Game).RackSize.
 	ExchangeLimit = 7
 	RackTileLimit = 7
+	// MaxRackSize is the largest rack size GameRules will allow.
+	MaxRackSize = 15
 
-	DefaultMaxScorelessTurns  = 6
+	DefaultBingoBonus = board.DefaultBingoBonus
+
+	// DefaultMaxScorelessTurns is for a two-player game. Games with more
+	// players end after the same number of scoreless rounds; see NewGame.
+	DefaultMaxScorelessTurns = 6
+	// MaxPlayers is the largest number of players a game can have.
+	MaxPlayers                = 4
 	CurrentGameHistoryVersion = 2
 )
 
+// ErrNotTwoPlayerGame is returned by analysis tools (sims, endgames,
+// inference) that only understand two-player games.
+var ErrNotTwoPlayerGame = errors.New("this is only supported for two-player games")
+
 // Game is the actual internal game structure that controls the entire
 // business logic of the game; drawing, making moves, etc. The two
 // structures above are basically data entities.
@@ -51,6 +67,8 @@ type Game struct {
 
 	scorelessTurns    int
 	maxScorelessTurns int
+	rackSize          int
+	exchangeLimit     int
 	onturn            int
 	turnnum           int
 	players           playerStates
@@ -67,6 +85,9 @@ type Game struct {
 	stackPtr   int
 	// rules contains the original game rules passed in to create this game.
 	rules *GameRules
+	// clock is nil for untimed games.
+	clock                *Clock
+	timePenaltiesApplied bool
 }
 
 func (g *Game) Config() *config.Config {
@@ -89,6 +110,22 @@ func (g *Game) Rules() *GameRules {
 	return g.rules
 }
 
+// RackSize returns the number of tiles on a full rack in this game.
+func (g *Game) RackSize() int {
+	return g.rackSize
+}
+
+// ExchangeLimit returns the minimum number of tiles that must be in the
+// bag for an exchange to be allowed.
+func (g *Game) ExchangeLimit() int {
+	return g.exchangeLimit
+}
+
+// IsBingo returns true if the passed-in move plays an entire rack.
+func (g *Game) IsBingo(m *move.Move) bool {
+	return m.Action() == move.MoveTypePlay && m.TilesPlayed() == g.rackSize
+}
+
 func (g *Game) LastEvent() *pb.GameEvent {
 	last := len(g.history.Events) - 1
 	if last < 0 {
@@ -157,12 +194,22 @@ func NewGame(rules *GameRules, playerinfo []*pb.PlayerInfo) (*Game, error) {
 	game.lexicon = rules.Lexicon()
 	game.config = rules.Config()
 	game.rules = rules
-	game.maxScorelessTurns = DefaultMaxScorelessTurns
+	if len(playerinfo) < 2 || len(playerinfo) > MaxPlayers {
+		return nil, fmt.Errorf("a game must have between 2 and %d players", MaxPlayers)
+	}
+	// Six scoreless turns for two players is three scoreless rounds;
+	// keep the same number of rounds for bigger games.
+	game.maxScorelessTurns = DefaultMaxScorelessTurns * len(playerinfo) / 2
+	game.rackSize = rules.RackSize()
+	game.exchangeLimit = rules.ExchangeLimit()
+	if tc := rules.TimeControl(); tc != nil {
+		game.clock = NewClock(*tc, len(playerinfo))
+	}
 	game.bag = game.letterDistribution.MakeBag()
 	game.players = make([]*playerState, len(playerinfo))
 	ids := map[string]bool{}
 	for idx, p := range playerinfo {
-		game.players[idx] = newPlayerState(p.Nickname, p.UserId, p.RealName)
+		game.players[idx] = newPlayerState(p.Nickname, p.UserId, p.RealName, game.rackSize)
 		ids[p.Nickname] = true
 	}
 	if len(ids) < len(playerinfo) {
@@ -188,7 +235,7 @@ func NewFromHistory(history *pb.GameHistory, rules *GameRules, turnnum int) (*Ga
 		history.Description = MacondoCreation
 	}
 	if history.LastKnownRacks == nil {
-		history.LastKnownRacks = []string{"", ""}
+		history.LastKnownRacks = make([]string, len(history.Players))
 	}
 
 	// Initialize the bag and player rack structures to avoid panics.
@@ -214,6 +261,9 @@ func NewFromSnapshot(rules *GameRules, players []*pb.PlayerInfo, lastKnownRacks
 	}
 
 	game.history = newHistory(game.players)
+	if game.clock != nil {
+		game.history.Timers = game.clock.TimeControl().historyTimers()
+	}
 
 	game.bag = game.letterDistribution.MakeBag()
 	for i := 0; i < game.NumPlayers(); i++ {
@@ -230,18 +280,21 @@ func NewFromSnapshot(rules *GameRules, players []*pb.PlayerInfo, lastKnownRacks
 	if err != nil {
 		return nil, err
 	}
+	if len(lastKnownRacks) != len(players) {
+		return nil, errors.New("number of racks does not match number of players")
+	}
 	game.history.LastKnownRacks = lastKnownRacks
 	// Set racks and tiles
-	racks := []*tilemapping.Rack{
-		tilemapping.RackFromString(game.history.LastKnownRacks[0], game.Alphabet()),
-		tilemapping.RackFromString(game.history.LastKnownRacks[1], game.Alphabet()),
+	racks := make([]*tilemapping.Rack, len(lastKnownRacks))
+	for i, r := range lastKnownRacks {
+		racks[i] = tilemapping.RackFromString(r, game.Alphabet())
 	}
 	game.history.Lexicon = game.Lexicon().Name()
 	game.history.Variant = string(game.rules.Variant())
 	game.history.LetterDistribution = game.rules.LetterDistributionName()
 	game.history.BoardLayout = game.rules.BoardName()
 
-	// set racks for both players; this removes the relevant letters from the bag.
+	// set racks for all players; this removes the relevant letters from the bag.
 	err = game.SetRacksForBoth(racks)
 	if err != nil {
 		return nil, err
@@ -257,17 +310,26 @@ func NewFromSnapshot(rules *GameRules, players []*pb.PlayerInfo, lastKnownRacks
 	game.playing = pb.PlayState_PLAYING
 	game.history.PlayState = game.playing
 
-	if game.bag.TilesRemaining() == 0 && (game.RackFor(0).NumTiles() == 0 || game.RackFor(1).NumTiles() == 0) {
-		game.playing = pb.PlayState_GAME_OVER
-		game.history.PlayState = game.playing
-		log.Info().Msg("this game is already over")
+	if game.bag.TilesRemaining() == 0 {
+		for i := range game.players {
+			if game.RackFor(i).NumTiles() == 0 {
+				game.playing = pb.PlayState_GAME_OVER
+				game.history.PlayState = game.playing
+				log.Info().Msg("this game is already over")
+				break
+			}
+		}
 	}
 
 	return game, nil
 }
 
+// FlipPlayers changes who goes first. The first player moves to the end
+// of the turn order; with two players this just swaps them.
 func (g *Game) FlipPlayers() {
-	g.players[0], g.players[1] = g.players[1], g.players[0]
+	first := g.players[0]
+	copy(g.players, g.players[1:])
+	g.players[len(g.players)-1] = first
 }
 
 func (g *Game) RenamePlayer(idx int, playerinfo *pb.PlayerInfo) error {
@@ -294,19 +356,23 @@ func (g *Game) StartGame() {
 	g.Board().Clear()
 	g.bag = g.letterDistribution.MakeBag()
 	g.history = newHistory(g.players)
+	if g.clock != nil {
+		g.history.Timers = g.clock.TimeControl().historyTimers()
+	}
 	// Deal out tiles
 	for i := 0; i < g.NumPlayers(); i++ {
 
-		err := g.bag.Draw(7, g.players[i].placeholderRack)
+		err := g.bag.Draw(g.rackSize, g.players[i].placeholderRack)
 		if err != nil {
 			panic(err)
 		}
 		g.players[i].rack = tilemapping.NewRack(g.alph)
-		g.players[i].setRackTiles(g.players[i].placeholderRack[:7], g.alph)
+		g.players[i].setRackTiles(g.players[i].placeholderRack[:g.rackSize], g.alph)
 		g.players[i].resetScore()
 	}
-	g.history.LastKnownRacks = []string{
-		g.RackLettersFor(0), g.RackLettersFor(1),
+	g.history.LastKnownRacks = make([]string, g.NumPlayers())
+	for i := range g.players {
+		g.history.LastKnownRacks[i] = g.RackLettersFor(i)
 	}
 	g.history.Lexicon = g.Lexicon().Name()
 	g.history.Variant = string(g.rules.Variant())
@@ -318,6 +384,11 @@ func (g *Game) StartGame() {
 	g.scorelessTurns = 0
 	g.onturn = 0
 	g.lastWordsFormed = nil
+	if g.clock != nil {
+		g.clock.Reset()
+		g.timePenaltiesApplied = false
+		g.startClock()
+	}
 }
 
 func (g *Game) SetCrossSetGen(gen cross_set.Generator) {
@@ -338,9 +409,9 @@ func (g *Game) ValidateMove(m *move.Move) ([]tilemapping.MachineWord, error) {
 		if g.playing == pb.PlayState_WAITING_FOR_FINAL_PASS {
 			return nil, errors.New("you can only pass or challenge")
 		}
-		if g.bag.TilesRemaining() < ExchangeLimit {
+		if g.bag.TilesRemaining() < g.ExchangeLimit() {
 			return nil, fmt.Errorf("not allowed to exchange with fewer than %d tiles in the bag",
-				ExchangeLimit)
+				g.ExchangeLimit())
 		}
 		// Make sure we have the tiles we are trying to exchange.
 		for _, t := range m.Tiles() {
@@ -376,7 +447,7 @@ func (g *Game) ValidateMove(m *move.Move) ([]tilemapping.MachineWord, error) {
 }
 
 func (g *Game) validateTilePlayMove(m *move.Move) ([]tilemapping.MachineWord, error) {
-	if m.TilesPlayed() > RackTileLimit {
+	if m.TilesPlayed() > g.rackSize {
 		return nil, errors.New("your play contained too many tiles")
 	}
 	// Check that our move actually uses the tiles on our rack.
@@ -408,14 +479,39 @@ func (g *Game) validateTilePlayMove(m *move.Move) ([]tilemapping.MachineWord, er
 	return formedWords, nil
 }
 
+// endOfGameCalcs awards the player who went out the points on the other
+// racks. In a two-player game they get double the opponent's rack, as
+// is usual in tournament play. With more players, the player who went
+// out gets the sum of all other racks, and every other player loses the
+// value of their own rack.
 func (g *Game) endOfGameCalcs(onturn int, addToHistory bool) {
-	unplayedPts := g.calculateRackPts(otherPlayer(onturn)) * 2
+	if g.NumPlayers() == 2 {
+		unplayedPts := g.calculateRackPts(g.nextPlayerIdx(onturn)) * 2
 
+		g.players[onturn].points += unplayedPts
+		if addToHistory {
+			g.turnnum++ // since we're adding a new event.
+			g.addEventToHistory(g.endRackEvt(onturn, unplayedPts))
+		}
+		return
+	}
+	unplayedPts := 0
+	for pidx := g.nextPlayerIdx(onturn); pidx != onturn; pidx = g.nextPlayerIdx(pidx) {
+		unplayedPts += g.calculateRackPts(pidx)
+	}
 	g.players[onturn].points += unplayedPts
 	if addToHistory {
-		g.turnnum++ // since we're adding a new event.
+		g.turnnum++
 		g.addEventToHistory(g.endRackEvt(onturn, unplayedPts))
 	}
+	for pidx := g.nextPlayerIdx(onturn); pidx != onturn; pidx = g.nextPlayerIdx(pidx) {
+		pts := g.calculateRackPts(pidx)
+		g.players[pidx].points -= pts
+		if addToHistory {
+			g.turnnum++
+			g.addEventToHistory(g.endRackPenaltyEvt(pidx, pts))
+		}
+	}
 	// log.Debug().Int("onturn", onturn).Int("unplayedpts", unplayedPts).Interface("players", g.players).
 	// 	Msg("endOfGameCalcs")
 }
@@ -443,7 +539,8 @@ func convertToVisible(words []tilemapping.MachineWord,
 // by simulators as it implements a subset of possible moves, and by remote
 // gameplay engines as much as possible.
 // If the millis argument is passed in, it adds this value to the history
-// as the time remaining for the user (when they played the move).
+// as the time remaining for the user (when they played the move). Timed
+// games ignore it and use the game clock instead.
 func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 
 	// We need to handle challenges separately.
@@ -451,6 +548,12 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 		_, err := g.ChallengeEvent(0, 0)
 		return err
 	}
+	// A stopped clock (for example, in a position loaded for analysis)
+	// just records the time remaining and is left alone.
+	clockRunning := addToHistory && g.clock != nil && g.clock.Running() != -1
+	if clockRunning {
+		millis = int(g.clock.EndTurn().Milliseconds())
+	}
 
 	if g.backupMode != NoBackup {
 		g.backupState()
@@ -477,7 +580,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 		g.scorelessTurns = 0
 		g.players[g.onturn].points += score
 		g.players[g.onturn].turns += 1
-		if m.TilesPlayed() == RackTileLimit {
+		if m.TilesPlayed() == g.rackSize {
 			g.players[g.onturn].bingos++
 		}
 		drew := g.bag.DrawAtMost(m.TilesPlayed(), g.players[g.onturn].placeholderRack)
@@ -530,7 +633,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 			// Note that the player "on turn" changes here, as we created
 			// a fake virtual turn on the pass. We need to calculate
 			// the final score correctly.
-			g.endOfGameCalcs((g.onturn+1)%2, addToHistory)
+			g.endOfGameCalcs(g.prevPlayerIdx(g.onturn), addToHistory)
 			if addToHistory {
 				g.AddFinalScoresToHistory()
 			}
@@ -567,6 +670,9 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 	}
 
 	g.turnnum++
+	if clockRunning {
+		g.startClock()
+	}
 
 	// log.Debug().Interface("history", g.history).Int("onturn", g.onturn).Int("turnnum", g.turnnum).
 	// 	Msg("newhist")
@@ -574,16 +680,26 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 }
 
 // AddFinalScoresToHistory adds the final scores and winner to the history.
+// In a timed game, overtime penalties are applied first.
 func (g *Game) AddFinalScoresToHistory() {
+	g.addTimePenalties()
 	g.history.FinalScores = make([]int32, len(g.players))
 	for pidx, p := range g.players {
 		g.history.FinalScores[pidx] = int32(p.points)
 	}
-	if g.history.FinalScores[0] > g.history.FinalScores[1] {
-		g.history.Winner = 0
-	} else if g.history.FinalScores[0] < g.history.FinalScores[1] {
-		g.history.Winner = 1
-	} else {
+	g.history.Winner = 0
+	tied := false
+	for pidx := 1; pidx < len(g.history.FinalScores); pidx++ {
+		score := g.history.FinalScores[pidx]
+		best := g.history.FinalScores[g.history.Winner]
+		if score > best {
+			g.history.Winner = int32(pidx)
+			tied = false
+		} else if score == best {
+			tied = true
+		}
+	}
+	if tied {
 		g.history.Winner = -1
 	}
 	log.Debug().Interface("finalscores", g.history.FinalScores).Msg("added-final-scores")
@@ -597,22 +713,22 @@ func (g *Game) handleConsecutiveScorelessTurns(addToHistory bool) (bool, error)
 		if addToHistory {
 			g.history.PlayState = g.playing
 		}
-		pts := g.calculateRackPts(g.onturn)
-		g.players[g.onturn].points -= pts
-		if addToHistory {
-			penaltyEvt := g.endRackPenaltyEvt(pts)
-			g.turnnum++
+		// Every player loses the value of their own rack, starting with
+		// the player on turn. The last player penalized ends up on turn.
+		for i := 0; i < len(g.players); i++ {
+			if i > 0 {
+				g.onturn = g.nextPlayerIdx(g.onturn)
+			}
+			pts := g.calculateRackPts(g.onturn)
+			g.players[g.onturn].points -= pts
+			if addToHistory {
+				penaltyEvt := g.endRackPenaltyEvt(g.onturn, pts)
+				g.turnnum++
 
-			g.addEventToHistory(penaltyEvt)
+				g.addEventToHistory(penaltyEvt)
+			}
 		}
-		g.onturn = (g.onturn + 1) % len(g.players)
-		pts = g.calculateRackPts(g.onturn)
-		g.players[g.onturn].points -= pts
 		if addToHistory {
-			penaltyEvt := g.endRackPenaltyEvt(pts)
-			g.turnnum++
-
-			g.addEventToHistory(penaltyEvt)
 			g.AddFinalScoresToHistory()
 		}
 	}
@@ -697,8 +813,14 @@ func (g *Game) calculateRackPts(onturn int) int {
 	return rack.ScoreOn(g.bag.LetterDistribution())
 }
 
-func otherPlayer(idx int) int {
-	return (idx + 1) % 2
+// nextPlayerIdx returns the index of the player who plays after idx.
+func (g *Game) nextPlayerIdx(idx int) int {
+	return (idx + 1) % len(g.players)
+}
+
+// prevPlayerIdx returns the index of the player who played before idx.
+func (g *Game) prevPlayerIdx(idx int) int {
+	return (idx + len(g.players) - 1) % len(g.players)
 }
 
 func (g *Game) AddNote(note string) error {
@@ -747,35 +869,16 @@ func (g *Game) PlayToTurn(turnnum int) error {
 			return err
 		}
 		// g.onturn will get rewritten in the next iteration
-		g.onturn = (g.onturn + 1) % 2
+		g.onturn = g.nextPlayerIdx(g.onturn)
 		log.Trace().Int("turn", t).Msg("played turn")
 	}
 	g.SetBackupMode(oldbackupMode)
 
 	if t >= len(g.history.Events) {
-		if len(g.history.LastKnownRacks[0]) > 0 && len(g.history.LastKnownRacks[1]) > 0 {
-			g.SetRacksForBoth([]*tilemapping.Rack{
-				tilemapping.RackFromString(g.history.LastKnownRacks[0], g.alph),
-				tilemapping.RackFromString(g.history.LastKnownRacks[1], g.alph),
-			})
-		} else if len(g.history.LastKnownRacks[0]) > 0 {
-			// Rack1 but not rack2
-			err := g.SetRackFor(0, tilemapping.RackFromString(g.history.LastKnownRacks[0], g.alph))
-			if err != nil {
-				return err
-			}
-		} else if len(g.history.LastKnownRacks[1]) > 0 {
-			// Rack2 but not rack1
-			err := g.SetRackFor(1, tilemapping.RackFromString(g.history.LastKnownRacks[1], g.alph))
-			if err != nil {
-				return err
-			}
-		} else {
-			// They're both blank.
-			// We don't have a recorded rack, so set it to a random one.
-			g.SetRandomRack(g.onturn, nil)
+		err := g.setLastKnownRacks()
+		if err != nil {
+			return err
 		}
-
 		log.Debug().Str("r0", g.players[0].rackLetters()).Str("r1", g.players[1].rackLetters()).Msg("PlayToTurn-set-racks")
 
 	} else {
@@ -823,6 +926,46 @@ func (g *Game) PlayToTurn(turnnum int) error {
 	return nil
 }
 
+// setLastKnownRacks sets every player's rack to the last known rack from
+// the history. Players whose rack is not known get random racks.
+func (g *Game) setLastKnownRacks() error {
+	known := 0
+	for _, r := range g.history.LastKnownRacks {
+		if len(r) > 0 {
+			known++
+		}
+	}
+	if known == 0 {
+		// We don't have a recorded rack, so set it to a random one.
+		_, err := g.SetRandomRack(g.onturn, nil)
+		return err
+	}
+	if known == len(g.players) {
+		racks := make([]*tilemapping.Rack, len(g.players))
+		for pidx, r := range g.history.LastKnownRacks {
+			racks[pidx] = tilemapping.RackFromString(r, g.alph)
+		}
+		g.SetRacksForBoth(racks)
+		return nil
+	}
+	g.ThrowRacksIn()
+	for pidx, r := range g.history.LastKnownRacks {
+		if len(r) == 0 {
+			continue
+		}
+		err := g.SetRackForOnly(pidx, tilemapping.RackFromString(r, g.alph))
+		if err != nil {
+			return err
+		}
+	}
+	for pidx, r := range g.history.LastKnownRacks {
+		if len(r) == 0 {
+			g.SetRandomRack(pidx, nil)
+		}
+	}
+	return nil
+}
+
 // PlayLatestEvent "plays" the latest event on the board. This is used for
 // replaying a game from a GCG.
 func (g *Game) PlayLatestEvent() error {
@@ -864,7 +1007,7 @@ func (g *Game) playTurn(t int) error {
 		g.board.PlayMove(m, ld)
 		g.crossSetGen.UpdateForMove(g.board, m)
 		g.players[g.onturn].points += m.Score()
-		if m.TilesPlayed() == RackTileLimit {
+		if m.TilesPlayed() == g.rackSize {
 			g.players[g.onturn].bingos++
 		}
 		evt.WordsFormed = convertToVisible(g.lastWordsFormed, g.alph)
@@ -938,8 +1081,10 @@ func (g *Game) SetRackFor(playerIdx int, rack *tilemapping.Rack) error {
 
 	// success; set our rack
 	g.players[playerIdx].rack = rack
-	// And redraw a random rack for opponent.
-	g.SetRandomRack(otherPlayer(playerIdx), nil)
+	// And redraw random racks for opponents.
+	for pidx := g.nextPlayerIdx(playerIdx); pidx != playerIdx; pidx = g.nextPlayerIdx(pidx) {
+		g.SetRandomRack(pidx, nil)
+	}
 
 	return nil
 }
@@ -958,7 +1103,8 @@ func (g *Game) SetRackForOnly(playerIdx int, rack *tilemapping.Rack) error {
 	return nil
 }
 
-// SetRacksForBoth sets both racks at the same time.
+// SetRacksForBoth sets the racks for all players at the same time.
+// Despite the name, it works for any number of players.
 func (g *Game) SetRacksForBoth(racks []*tilemapping.Rack) error {
 	g.ThrowRacksIn()
 	for _, rack := range racks {
@@ -974,10 +1120,11 @@ func (g *Game) SetRacksForBoth(racks []*tilemapping.Rack) error {
 	return nil
 }
 
-// ThrowRacksIn throws both players' racks back in the bag.
+// ThrowRacksIn throws all players' racks back in the bag.
 func (g *Game) ThrowRacksIn() {
-	g.players[0].throwRackIn(g.bag)
-	g.players[1].throwRackIn(g.bag)
+	for _, p := range g.players {
+		p.throwRackIn(g.bag)
+	}
 }
 
 // SetRandomRack sets the player's  rack to a random rack drawn from the bag.
@@ -985,30 +1132,32 @@ func (g *Game) ThrowRacksIn() {
 // If a second argument (knownRack) is provided, the randomRack will contain
 // the known rack. Any extra drawn tiles are returned as well, in this case.
 func (g *Game) SetRandomRack(playerIdx int, knownRack []tilemapping.MachineLetter) ([]tilemapping.MachineLetter, error) {
-	n := g.RackFor(playerIdx).NoAllocTilesOn(g.players[1-playerIdx].placeholderRack)
+	// we use the next player's placeholder rack as scratch space.
+	scratch := g.players[g.nextPlayerIdx(playerIdx)].placeholderRack
+	n := g.RackFor(playerIdx).NoAllocTilesOn(scratch)
 	var extraDrawn []tilemapping.MachineLetter
 	if len(knownRack) == 0 {
 		// we're using the other player's rack as a placeholder. This is ugly.
-		ndrawn := g.bag.Redraw(g.players[1-playerIdx].placeholderRack[:n],
+		ndrawn := g.bag.Redraw(scratch[:n],
 			g.players[playerIdx].placeholderRack)
 		// note that ndrawn does not need to match n
 		g.players[playerIdx].setRackTiles(g.players[playerIdx].placeholderRack[:ndrawn], g.alph)
 	} else {
 		// we're using the other player's rack as a placeholder. This is ugly!
-		g.bag.PutBack(g.players[1-playerIdx].placeholderRack[:n])
+		g.bag.PutBack(scratch[:n])
 		err := g.bag.RemoveTiles(knownRack)
 		if err != nil {
 			// if there is an error we need to undo the PutBack!
-			g.bag.RemoveTiles(g.players[1-playerIdx].placeholderRack[:n])
+			g.bag.RemoveTiles(scratch[:n])
 			return nil, err
 		}
 		// In case we didn't have a full rack.
-		nTilesToDraw := lo.Max([]int{n, RackTileLimit}) - len(knownRack)
+		nTilesToDraw := lo.Max([]int{n, g.rackSize}) - len(knownRack)
 
-		copy(g.players[1-playerIdx].placeholderRack, knownRack)
-		ndrawn := g.bag.DrawAtMost(nTilesToDraw, g.players[1-playerIdx].placeholderRack[len(knownRack):])
-		g.players[playerIdx].setRackTiles(g.players[1-playerIdx].placeholderRack[:len(knownRack)+ndrawn], g.alph)
-		extraDrawn = g.players[1-playerIdx].placeholderRack[len(knownRack) : len(knownRack)+ndrawn]
+		copy(scratch, knownRack)
+		ndrawn := g.bag.DrawAtMost(nTilesToDraw, scratch[len(knownRack):])
+		g.players[playerIdx].setRackTiles(scratch[:len(knownRack)+ndrawn], g.alph)
+		extraDrawn = scratch[len(knownRack) : len(knownRack)+ndrawn]
 	}
 	// log.Debug().Int("player", playerIdx).Str("newrack", g.players[playerIdx].rackLetters).
 	// 	Msg("set random rack")
@@ -1025,6 +1174,16 @@ func (g *Game) RackLettersFor(playerIdx int) string {
 	return g.RackFor(playerIdx).String()
 }
 
+// UnseenTiles returns the tiles the given player cannot see: the tiles in
+// the bag and on every other player's rack.
+func (g *Game) UnseenTiles(playerIdx int) []tilemapping.MachineLetter {
+	unseen := g.bag.Peek()
+	for pi := g.nextPlayerIdx(playerIdx); pi != playerIdx; pi = g.nextPlayerIdx(pi) {
+		unseen = append(unseen, g.players[pi].rack.TilesOn()...)
+	}
+	return unseen
+}
+
 // PointsFor returns the number of points for the given player
 func (g *Game) PointsFor(playerIdx int) int {
 	return g.players[playerIdx].points
@@ -1057,13 +1216,21 @@ func (g *Game) TurnsForNick(nick string) int {
 	return 0
 }
 
+// SpreadFor returns the player's points minus the points of their best
+// opponent. In a two-player game this is the usual spread.
 func (g *Game) SpreadFor(playerIdx int) int {
-	return g.PointsFor(playerIdx) - g.PointsFor(otherPlayer(playerIdx))
+	best := math.MinInt
+	for pidx := range g.players {
+		if pidx != playerIdx && g.players[pidx].points > best {
+			best = g.players[pidx].points
+		}
+	}
+	return g.PointsFor(playerIdx) - best
 }
 
-// NumPlayers is always 2.
+// NumPlayers returns the number of players in this game.
 func (g *Game) NumPlayers() int {
-	return 2
+	return len(g.players)
 }
 
 // Bag returns the current bag
@@ -1114,6 +1281,9 @@ func (g *Game) PlayerIDOnTurn() string {
 
 func (g *Game) SetPlayerOnTurn(onTurn int) {
 	g.onturn = onTurn
+	if g.clock != nil && g.clock.Running() != -1 {
+		g.clock.Start(onTurn)
+	}
 }
 
 func (g *Game) SetPointsFor(player, pts int) {
@@ -1125,7 +1295,7 @@ func (g *Game) Alphabet() *tilemapping.TileMapping {
 }
 
 func (g *Game) CurrentSpread() int {
-	return g.PointsFor(g.onturn) - g.PointsFor((g.onturn+1)%2)
+	return g.SpreadFor(g.onturn)
 }
 
 func (g *Game) History() *pb.GameHistory {
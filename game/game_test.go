@@ -164,3 +164,150 @@ func TestPlayToTurnWithPhony(t *testing.T) {
 	is.Equal(g.RackFor(0).TilesOn().UserVisible(tilemapping.EnglishAlphabet()),
 		"EEHKNOQ")
 }
+
+func TestConfigurableRackSizeAndBingoBonus(t *testing.T) {
+	is := is.New(t)
+	players := []*pb.PlayerInfo{
+		{Nickname: "JD", RealName: "Jesse"},
+		{Nickname: "cesar", RealName: "César"},
+	}
+	rules, err := NewBasicGameRules(
+		&DefaultConfig, "", board.CrosswordGameLayout, "english",
+		CrossScoreOnly, "")
+	is.NoErr(err)
+	is.NoErr(rules.SetRackSize(8))
+	is.NoErr(rules.SetBingoBonus(40))
+	is.True(rules.SetRackSize(MaxRackSize+1) != nil)
+
+	g, err := NewGame(rules, players)
+	is.NoErr(err)
+	g.StartGame()
+	is.Equal(g.RackSize(), 8)
+	is.Equal(g.ExchangeLimit(), 8)
+	is.Equal(int(g.RackFor(0).NumTiles()), 8)
+	is.Equal(int(g.RackFor(1).NumTiles()), 8)
+	is.Equal(g.bag.TilesRemaining(), 84)
+
+	g.SetPlayerOnTurn(0)
+	err = g.SetRackFor(0, tilemapping.RackFromString("ABCDEFGH", g.Alphabet()))
+	is.NoErr(err)
+	is.Equal(int(g.RackFor(1).NumTiles()), 8)
+
+	m, err := g.CreateAndScorePlacementMove("8D", "ABCDEFGH", "ABCDEFGH")
+	is.NoErr(err)
+	// (2*1 + 3 + 3 + 2 + 1 + 4 + 2 + 4) * 2 + 40
+	is.Equal(m.Score(), 82)
+	is.True(g.IsBingo(m))
+
+	err = g.PlayMove(m, true, 0)
+	is.NoErr(err)
+	is.True(g.History().Events[0].IsBingo)
+	is.Equal(int(g.RackFor(0).NumTiles()), 8)
+
+	// A 7-tile play is no longer a bingo.
+	g.SetPlayerOnTurn(1)
+	err = g.SetRackFor(1, tilemapping.RackFromString("IJKLMNOP", g.Alphabet()))
+	is.NoErr(err)
+	m, err = g.CreateAndScorePlacementMove("K1", "IJKLMNO.", "IJKLMNOP")
+	is.NoErr(err)
+	is.True(!g.IsBingo(m))
+}
+
+func newThreePlayerGame(is *is.I) *Game {
+	players := []*pb.PlayerInfo{
+		{Nickname: "JD", RealName: "Jesse"},
+		{Nickname: "cesar", RealName: "César"},
+		{Nickname: "mina", RealName: "Mina"},
+	}
+	rules, err := NewBasicGameRules(
+		&DefaultConfig, "", board.CrosswordGameLayout, "english",
+		CrossScoreOnly, "")
+	is.NoErr(err)
+	g, err := NewGame(rules, players)
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	return g
+}
+
+func TestNewGamePlayerCount(t *testing.T) {
+	is := is.New(t)
+	rules, err := NewBasicGameRules(
+		&DefaultConfig, "", board.CrosswordGameLayout, "english",
+		CrossScoreOnly, "")
+	is.NoErr(err)
+	var players []*pb.PlayerInfo
+	for i := 0; i <= MaxPlayers+1; i++ {
+		_, err = NewGame(rules, players)
+		is.Equal(err == nil, i >= 2 && i <= MaxPlayers)
+		players = append(players, &pb.PlayerInfo{
+			Nickname: fmt.Sprintf("p%d", i), RealName: fmt.Sprintf("Player %d", i)})
+	}
+}
+
+func TestThreePlayerScorelessTurns(t *testing.T) {
+	is := is.New(t)
+	g := newThreePlayerGame(is)
+	is.Equal(g.NumPlayers(), 3)
+	for i := 0; i < 3; i++ {
+		is.Equal(int(g.RackFor(i).NumTiles()), 7)
+	}
+	is.Equal(g.bag.TilesRemaining(), 79)
+
+	// Six scoreless turns end a two-player game; with three players every
+	// player gets to pass three times.
+	for i := 0; i < 8; i++ {
+		is.Equal(g.PlayerOnTurn(), i%3)
+		is.NoErr(g.PlayMove(move.NewPassMove(nil, g.Alphabet()), true, 0))
+		is.Equal(g.Playing(), pb.PlayState_PLAYING)
+	}
+	racks := make([]int, 3)
+	for i := range racks {
+		racks[i] = g.RackFor(i).ScoreOn(g.Bag().LetterDistribution())
+	}
+	is.NoErr(g.PlayMove(move.NewPassMove(nil, g.Alphabet()), true, 0))
+	is.Equal(g.Playing(), pb.PlayState_GAME_OVER)
+	for i := range racks {
+		is.Equal(g.PointsFor(i), -racks[i])
+	}
+	// 9 passes and 3 rack penalties.
+	is.Equal(len(g.History().Events), 12)
+}
+
+func TestThreePlayerGoingOut(t *testing.T) {
+	is := is.New(t)
+	g := newThreePlayerGame(is)
+	alph := g.Alphabet()
+	err := g.SetRacksForBoth([]*tilemapping.Rack{
+		tilemapping.RackFromString("AB", alph),
+		tilemapping.RackFromString("QZ", alph),
+		tilemapping.RackFromString("XE", alph),
+	})
+	is.NoErr(err)
+	// Empty the bag.
+	drawn := make([]tilemapping.MachineLetter, g.bag.TilesRemaining())
+	is.NoErr(g.bag.Draw(len(drawn), drawn))
+
+	m, err := g.CreateAndScorePlacementMove("8G", "AB", "AB")
+	is.NoErr(err)
+	is.Equal(m.Score(), 8)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.Equal(g.Playing(), pb.PlayState_GAME_OVER)
+
+	// The player who went out gets the value of every other rack, and each
+	// other player loses the value of their own.
+	is.Equal(g.PointsFor(0), 8+20+9)
+	is.Equal(g.PointsFor(1), -20)
+	is.Equal(g.PointsFor(2), -9)
+	is.Equal(g.History().Winner, int32(0))
+
+	evts := g.History().Events
+	is.Equal(len(evts), 4)
+	is.Equal(evts[1].Type, pb.GameEvent_END_RACK_PTS)
+	is.Equal(evts[1].Rack, "QZEX")
+	is.Equal(evts[1].EndRackPoints, int32(29))
+	is.Equal(evts[2].Type, pb.GameEvent_END_RACK_PENALTY)
+	is.Equal(evts[2].PlayerIndex, uint32(1))
+	is.Equal(evts[3].Type, pb.GameEvent_END_RACK_PENALTY)
+	is.Equal(evts[3].PlayerIndex, uint32(2))
+}
@@ -20,14 +20,14 @@ type playerState struct {
 	placeholderRack []tilemapping.MachineLetter
 }
 
-func newPlayerState(nickname, userid, realname string) *playerState {
+func newPlayerState(nickname, userid, realname string, rackSize int) *playerState {
 	return &playerState{
 		PlayerInfo: pb.PlayerInfo{
 			Nickname: nickname,
 			UserId:   userid,
 			RealName: realname,
 		},
-		placeholderRack: make([]tilemapping.MachineLetter, RackTileLimit),
+		placeholderRack: make([]tilemapping.MachineLetter, rackSize),
 	}
 }
 
@@ -2,6 +2,7 @@ package game
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/domino14/macondo/board"
 	"github.com/domino14/macondo/config"
@@ -38,6 +39,13 @@ type GameRules struct {
 	variant     Variant
 	boardname   string
 	distname    string
+	rackSize    int
+	bingoBonus  int
+	// exchangeLimit is the minimum number of tiles that must be in the
+	// bag to allow an exchange. If it is 0, the rack size is used.
+	exchangeLimit int
+	// timeControl is nil for untimed games.
+	timeControl *TimeControl
 }
 
 func (g GameRules) Config() *config.Config {
@@ -76,6 +84,72 @@ func (g GameRules) Variant() Variant {
 	return g.variant
 }
 
+// RackSize is the number of tiles each player holds on their rack. It is
+// also the number of tiles that must be played for a bingo.
+func (g GameRules) RackSize() int {
+	return g.rackSize
+}
+
+// BingoBonus is the number of points awarded for playing all the tiles
+// on a full rack.
+func (g GameRules) BingoBonus() int {
+	return g.bingoBonus
+}
+
+// ExchangeLimit is the minimum number of tiles that must be in the bag
+// for an exchange to be allowed.
+func (g GameRules) ExchangeLimit() int {
+	if g.exchangeLimit == 0 {
+		return g.rackSize
+	}
+	return g.exchangeLimit
+}
+
+// SetExchangeLimit changes the minimum number of tiles that must be in the
+// bag to allow an exchange. By default this is the same as the rack size.
+func (g *GameRules) SetExchangeLimit(n int) error {
+	if n < 1 {
+		return errors.New("exchange limit must be at least 1")
+	}
+	g.exchangeLimit = n
+	return nil
+}
+
+// SetRackSize changes the rack size for games created with these rules.
+func (g *GameRules) SetRackSize(n int) error {
+	if n < 1 || n > MaxRackSize {
+		return fmt.Errorf("rack size must be between 1 and %d", MaxRackSize)
+	}
+	g.rackSize = n
+	g.board.SetBingoParams(g.rackSize, g.bingoBonus)
+	return nil
+}
+
+// SetBingoBonus changes the bingo bonus for games created with these rules.
+func (g *GameRules) SetBingoBonus(n int) error {
+	if n < 0 {
+		return errors.New("bingo bonus cannot be negative")
+	}
+	g.bingoBonus = n
+	g.board.SetBingoParams(g.rackSize, g.bingoBonus)
+	return nil
+}
+
+// TimeControl returns the clock settings for games created with these
+// rules, or nil if they are untimed.
+func (g GameRules) TimeControl() *TimeControl {
+	return g.timeControl
+}
+
+// SetTimeControl makes games created with these rules timed.
+func (g *GameRules) SetTimeControl(tc TimeControl) error {
+	if err := tc.validate(); err != nil {
+		return err
+	}
+	g.timeControl = &tc
+	return nil
+}
+
 func NewBasicGameRules(cfg *config.Config,
 	lexiconName, boardLayoutName, letterDistributionName, csetGenName string,
 	variant Variant) (*GameRules, error) {
@@ -131,6 +205,8 @@ func NewBasicGameRules(cfg *config.Config,
 		lexicon:     lex,
 		crossSetGen: csgen,
 		variant:     variant,
+		rackSize:    RackTileLimit,
+		bingoBonus:  DefaultBingoBonus,
 	}
 	return rules, nil
 }
@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
@@ -16,10 +17,6 @@ func (g *Game) curPlayer() *playerState {
 	return g.players[g.onturn]
 }
 
-func (g *Game) oppPlayer() *playerState {
-	return g.players[(g.onturn+1)%2]
-}
-
 func (g *Game) EventFromMove(m *move.Move) *pb.GameEvent {
 	curPlayer := g.curPlayer()
 
@@ -35,7 +32,7 @@ func (g *Game) EventFromMove(m *move.Move) *pb.GameEvent {
 		evt.PlayedTiles = m.Tiles().UserVisiblePlayedTiles(m.Alphabet())
 		evt.Score = int32(m.Score())
 		evt.Type = pb.GameEvent_TILE_PLACEMENT_MOVE
-		evt.IsBingo = m.TilesPlayed() == RackTileLimit
+		evt.IsBingo = m.TilesPlayed() == g.rackSize
 		evt.NumTilesFromRack = uint32(m.TilesPlayed())
 		CalculateCoordsFromStringPosition(evt)
 
@@ -65,23 +62,27 @@ func (g *Game) EventFromMove(m *move.Move) *pb.GameEvent {
 
 func (g *Game) endRackEvt(pidx int, bonusPts int) *pb.GameEvent {
 	curPlayer := g.players[pidx]
-	otherPlayer := g.players[otherPlayer(pidx)]
+	// The rack is that of every other player, in turn order.
+	var rack strings.Builder
+	for opp := g.nextPlayerIdx(pidx); opp != pidx; opp = g.nextPlayerIdx(opp) {
+		rack.WriteString(g.players[opp].rack.String())
+	}
 
 	evt := &pb.GameEvent{
 		PlayerIndex:   uint32(pidx),
 		Cumulative:    int32(curPlayer.points),
-		Rack:          otherPlayer.rack.String(),
+		Rack:          rack.String(),
 		EndRackPoints: int32(bonusPts),
 		Type:          pb.GameEvent_END_RACK_PTS,
 	}
 	return evt
 }
 
-func (g *Game) endRackPenaltyEvt(penalty int) *pb.GameEvent {
-	curPlayer := g.curPlayer()
+func (g *Game) endRackPenaltyEvt(pidx int, penalty int) *pb.GameEvent {
+	curPlayer := g.players[pidx]
 
 	evt := &pb.GameEvent{
-		PlayerIndex: uint32(g.onturn),
+		PlayerIndex: uint32(pidx),
 		Cumulative:  int32(curPlayer.points),
 		Rack:        curPlayer.rack.String(),
 		LostScore:   int32(penalty),
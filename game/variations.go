@@ -0,0 +1,320 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+// MainlineID is the ID of the mainline of a VariationTree.
+const MainlineID = 0
+
+var (
+	ErrVariationNotFound  = errors.New("variation not found")
+	ErrDeleteMainline     = errors.New("cannot delete the mainline")
+	ErrDeleteCurrentLine  = errors.New("cannot delete the current line or one of its ancestors; switch to another line first")
+	ErrBranchOutsideRange = errors.New("branch turn is outside the current line")
+)
+
+// A Variation is a single line of play in a VariationTree. Every variation
+// other than the mainline branches off its parent at BranchTurn: it shares
+// the parent's first BranchTurn events and has its own events after that.
+type Variation struct {
+	ID         int
+	ParentID   int
+	BranchTurn int
+
+	tree           *VariationTree
+	events         []*pb.GameEvent
+	lastKnownRacks []string
+	finalScores    []int32
+	winner         int32
+}
+
+// Events returns all the events of this line, including those it shares
+// with its parent.
+func (v *Variation) Events() []*pb.GameEvent {
+	v.tree.save()
+	return v.events
+}
+
+// Moves returns the events that belong to this line only, that is, the
+// ones after its branch point.
+func (v *Variation) Moves() []*pb.GameEvent {
+	v.tree.save()
+	if v.BranchTurn >= len(v.events) {
+		return nil
+	}
+	return v.events[v.BranchTurn:]
+}
+
+// VariationTree lets a game be explored along several lines of play. The
+// game always holds the events of the current line in its history; the
+// tree stores the events of every other line and swaps them in and out of
+// the game as the current line changes.
+type VariationTree struct {
+	game    *Game
+	lines   []*Variation
+	current *Variation
+	nextID  int
+}
+
+// NewVariationTree creates a tree whose mainline is the game's current
+// history.
+func NewVariationTree(g *Game) *VariationTree {
+	t := &VariationTree{game: g, nextID: MainlineID + 1}
+	mainline := &Variation{ID: MainlineID, ParentID: MainlineID, tree: t}
+	t.lines = []*Variation{mainline}
+	t.current = mainline
+	t.save()
+	return t
+}
+
+// save stores the game's history into the current line.
+func (t *VariationTree) save() {
+	h := t.game.History()
+	t.current.events = h.Events
+	t.current.lastKnownRacks = h.LastKnownRacks
+	t.current.finalScores = h.FinalScores
+	t.current.winner = h.Winner
+}
+
+// load puts the line's events into the game's history and replays the
+// game up to the given turn.
+func (t *VariationTree) load(v *Variation, turn int) error {
+	h := t.game.History()
+	h.Events = v.events
+	h.LastKnownRacks = v.lastKnownRacks
+	h.FinalScores = v.finalScores
+	h.Winner = v.winner
+	t.current = v
+	return t.game.PlayToTurn(turn)
+}
+
+// Current returns the line the game is currently following.
+func (t *VariationTree) Current() *Variation {
+	t.save()
+	return t.current
+}
+
+// Mainline returns the mainline of the tree.
+func (t *VariationTree) Mainline() *Variation {
+	t.save()
+	return t.lines[0]
+}
+
+// Variations returns all lines in the tree, in order of creation. The
+// mainline is always first.
+func (t *VariationTree) Variations() []*Variation {
+	t.save()
+	return t.lines
+}
+
+// Variation returns the line with the given ID.
+func (t *VariationTree) Variation(id int) (*Variation, error) {
+	t.save()
+	return t.find(id)
+}
+
+func (t *VariationTree) find(id int) (*Variation, error) {
+	for _, v := range t.lines {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, ErrVariationNotFound
+}
+
+// Branch creates a new variation off the current line at the game's
+// current turn and makes it the current line. The game stays at the same
+// position; any move played next is the first move of the new variation.
+func (t *VariationTree) Branch() (*Variation, error) {
+	t.save()
+	turn := t.game.Turn()
+	if turn > len(t.current.events) {
+		return nil, ErrBranchOutsideRange
+	}
+	// Clone the shared events, so that notes added in one line don't
+	// show up in the other.
+	events := make([]*pb.GameEvent, turn)
+	for i, evt := range t.current.events[:turn] {
+		events[i] = proto.Clone(evt).(*pb.GameEvent)
+	}
+	// Only the rack of the player on turn is known at the branch point.
+	racks := make([]string, t.game.NumPlayers())
+	racks[t.game.PlayerOnTurn()] = t.game.RackLettersFor(t.game.PlayerOnTurn())
+
+	v := &Variation{
+		ID:             t.nextID,
+		ParentID:       t.current.ID,
+		BranchTurn:     turn,
+		tree:           t,
+		events:         events,
+		lastKnownRacks: racks,
+	}
+	t.nextID++
+	t.lines = append(t.lines, v)
+	err := t.load(v, turn)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Switch makes the line with the given ID the current one, and sets the
+// game to the given turn of that line.
+func (t *VariationTree) Switch(id int, turn int) error {
+	v, err := t.Variation(id)
+	if err != nil {
+		return err
+	}
+	if turn < 0 || turn > len(v.events) {
+		return fmt.Errorf("variation %d has %d turns, you have chosen a turn outside the range",
+			id, len(v.events))
+	}
+	return t.load(v, turn)
+}
+
+// Divergence returns the turn at which the given line leaves the
+// mainline. This is the earliest branch turn of the line and all of its
+// ancestors.
+func (t *VariationTree) Divergence(v *Variation) int {
+	t.save()
+	turn := v.BranchTurn
+	for v.ID != MainlineID {
+		if v.BranchTurn < turn {
+			turn = v.BranchTurn
+		}
+		parent, err := t.find(v.ParentID)
+		if err != nil {
+			break
+		}
+		v = parent
+	}
+	return turn
+}
+
+// Delete removes the line with the given ID and every line that branches
+// off it. The mainline and the current line cannot be deleted.
+func (t *VariationTree) Delete(id int) error {
+	if id == MainlineID {
+		return ErrDeleteMainline
+	}
+	if _, err := t.find(id); err != nil {
+		return err
+	}
+	if t.isDescendant(t.current, id) {
+		return ErrDeleteCurrentLine
+	}
+	var kept []*Variation
+	for _, v := range t.lines {
+		if !t.isDescendant(v, id) {
+			kept = append(kept, v)
+		}
+	}
+	t.lines = kept
+	return nil
+}
+
+// isDescendant returns whether v is the line with the given ID or one of
+// its descendants.
+func (t *VariationTree) isDescendant(v *Variation, id int) bool {
+	for {
+		if v.ID == id {
+			return true
+		}
+		if v.ID == MainlineID {
+			return false
+		}
+		parent, err := t.find(v.ParentID)
+		if err != nil {
+			return false
+		}
+		v = parent
+	}
+}
+
+// Describe returns a one-line description of the line's moves, starting
+// at the given turn.
+func (t *VariationTree) Describe(v *Variation, from int) string {
+	players := t.game.History().Players
+	descs := []string{}
+	if from > len(v.events) {
+		return ""
+	}
+	for _, evt := range v.events[from:] {
+		descs = append(descs, eventNotation(players, evt))
+	}
+	return strings.Join(descs, "; ")
+}
+
+// MainlineHistory returns a copy of the game history that follows the
+// mainline. Every variation is described in the note of the mainline
+// event it is an alternative to.
+func (t *VariationTree) MainlineHistory() *pb.GameHistory {
+	t.save()
+	mainline := t.lines[0]
+	h := proto.Clone(t.game.History()).(*pb.GameHistory)
+	h.Events = make([]*pb.GameEvent, len(mainline.events))
+	for i, evt := range mainline.events {
+		h.Events[i] = proto.Clone(evt).(*pb.GameEvent)
+	}
+	h.LastKnownRacks = mainline.lastKnownRacks
+	h.FinalScores = mainline.finalScores
+	h.Winner = mainline.winner
+
+	for _, v := range t.lines[1:] {
+		if len(h.Events) == 0 {
+			break
+		}
+		turn := t.Divergence(v)
+		if turn >= len(v.events) {
+			// Nothing was played in this variation.
+			continue
+		}
+		// A variation off the end of the mainline is a continuation of
+		// its last event.
+		noteIdx := turn
+		if noteIdx >= len(h.Events) {
+			noteIdx = len(h.Events) - 1
+		}
+		evt := h.Events[noteIdx]
+		note := fmt.Sprintf("Variation %d: %s", v.ID, t.Describe(v, turn))
+		if evt.Note != "" {
+			evt.Note += "\n" + note
+		} else {
+			evt.Note = note
+		}
+	}
+	return h
+}
+
+// eventNotation returns a short notation for an event, similar to its
+// line in a GCG file.
+func eventNotation(players []*pb.PlayerInfo, evt *pb.GameEvent) string {
+	who := players[evt.PlayerIndex].Nickname
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		return fmt.Sprintf("%s: %s %s +%d", who, evt.Position, evt.PlayedTiles, evt.Score)
+	case pb.GameEvent_PASS, pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS:
+		return fmt.Sprintf("%s: - +0", who)
+	case pb.GameEvent_EXCHANGE:
+		return fmt.Sprintf("%s: -%s +0", who, evt.Exchanged)
+	case pb.GameEvent_PHONY_TILES_RETURNED:
+		return fmt.Sprintf("%s: -- -%d", who, evt.LostScore)
+	case pb.GameEvent_CHALLENGE_BONUS:
+		return fmt.Sprintf("%s: (challenge) +%d", who, evt.Bonus)
+	case pb.GameEvent_END_RACK_PTS:
+		return fmt.Sprintf("%s: (%s) +%d", who, evt.Rack, evt.EndRackPoints)
+	case pb.GameEvent_END_RACK_PENALTY:
+		return fmt.Sprintf("%s: (%s) -%d", who, evt.Rack, evt.LostScore)
+	case pb.GameEvent_TIME_PENALTY:
+		return fmt.Sprintf("%s: (time) -%d", who, evt.LostScore)
+	default:
+		return fmt.Sprintf("%s: %s", who, evt.Type)
+	}
+}
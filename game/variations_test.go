@@ -0,0 +1,107 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+func playScoringMove(is *is.I, g *Game, rack, coords, word string) {
+	err := g.SetRackFor(g.PlayerOnTurn(), tilemapping.RackFromString(rack, g.Alphabet()))
+	is.NoErr(err)
+	_, err = g.PlayScoringMove(coords, word, true)
+	is.NoErr(err)
+}
+
+func newVariationTestGame(is *is.I) *Game {
+	players := []*pb.PlayerInfo{
+		{Nickname: "JD", RealName: "Jesse"},
+		{Nickname: "cesar", RealName: "César"},
+	}
+	rules, err := NewBasicGameRules(
+		&DefaultConfig, "", board.CrosswordGameLayout, "english",
+		CrossScoreOnly, "")
+	is.NoErr(err)
+	g, err := NewGame(rules, players)
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	g.SetBackupMode(InteractiveGameplayMode)
+	g.SetStateStackLength(1)
+	playScoringMove(is, g, "AEINRST", "8D", "STAIR")
+	playScoringMove(is, g, "EHLOOTW", "E5", "WHO.E")
+	playScoringMove(is, g, "ADEGIMN", "G6", "MA.I")
+	return g
+}
+
+func TestVariationBranchAndSwitch(t *testing.T) {
+	is := is.New(t)
+	g := newVariationTestGame(is)
+	tree := NewVariationTree(g)
+	is.Equal(len(tree.Mainline().Events()), 3)
+
+	// Go back and try something else for cesar.
+	is.NoErr(g.PlayToTurn(1))
+	v, err := tree.Branch()
+	is.NoErr(err)
+	is.Equal(v.ID, 1)
+	is.Equal(v.ParentID, MainlineID)
+	is.Equal(v.BranchTurn, 1)
+	is.Equal(tree.Current(), v)
+	playScoringMove(is, g, "EHLOOTW", "9F", "WHO")
+	is.Equal(len(v.Moves()), 1)
+	is.Equal(g.PointsFor(1), 29)
+
+	// The mainline is untouched.
+	is.Equal(len(tree.Mainline().Events()), 3)
+	is.NoErr(tree.Switch(MainlineID, 3))
+	is.Equal(tree.Current().ID, MainlineID)
+	is.Equal(g.PointsFor(1), 22)
+	is.Equal(g.Board().GetLetter(8, 5), tilemapping.MachineLetter(0))
+
+	// And the variation can be replayed.
+	is.NoErr(tree.Switch(1, 2))
+	is.Equal(g.PointsFor(1), 29)
+	is.True(g.Board().GetLetter(8, 5) != 0)
+
+	// Branch off the variation.
+	v2, err := tree.Branch()
+	is.NoErr(err)
+	is.Equal(v2.ParentID, 1)
+	is.Equal(tree.Divergence(v2), 1)
+	playScoringMove(is, g, "ADEGIMN", "10F", "MAN")
+
+	is.Equal(tree.Delete(MainlineID), ErrDeleteMainline)
+	is.Equal(tree.Delete(1), ErrDeleteCurrentLine)
+	is.NoErr(tree.Switch(MainlineID, 1))
+	is.NoErr(tree.Delete(1))
+	is.Equal(len(tree.Variations()), 1)
+	_, err = tree.Variation(2)
+	is.Equal(err, ErrVariationNotFound)
+}
+
+func TestVariationMainlineHistory(t *testing.T) {
+	is := is.New(t)
+	g := newVariationTestGame(is)
+	tree := NewVariationTree(g)
+
+	is.NoErr(g.PlayToTurn(1))
+	_, err := tree.Branch()
+	is.NoErr(err)
+	playScoringMove(is, g, "EHLOOTW", "9F", "WHO")
+	// A note in the variation doesn't leak into the mainline.
+	is.NoErr(g.AddNote("better defensively"))
+
+	h := tree.MainlineHistory()
+	is.Equal(len(h.Events), 3)
+	is.Equal(h.Events[0].Note, "")
+	is.Equal(h.Events[1].PlayedTiles, "WHO.E")
+	is.Equal(h.Events[1].Note, "Variation 1: cesar: 9F WHO +29")
+	// The game itself still follows the variation.
+	is.Equal(len(g.History().Events), 2)
+	is.Equal(g.History().Events[1].PlayedTiles, "WHO")
+}
@@ -0,0 +1,358 @@
+// Package gamedb is a database of game histories, kept in a directory.
+// Each game is stored in its own file, and an index of every game, with its
+// players, lexicon, variant, date and results, is kept in memory and saved
+// as JSON next to them. Games can be imported from directories of GCG files,
+// found by player, lexicon, variant and date, and summed up into stats.
+package gamedb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/gcgio"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+const (
+	indexFile = "index.json"
+	gamesDir  = "games"
+)
+
+var errGameNotFound = errors.New("game not found")
+
+// A Player is a player of a game, as named in it.
+type Player struct {
+	Nickname string `json:"nickname"`
+	RealName string `json:"real_name,omitempty"`
+}
+
+// An Entry is the index entry of a game. Players and the per-player slices
+// are in the order the players went.
+type Entry struct {
+	ID      string    `json:"id"`
+	Players []Player  `json:"players"`
+	Lexicon string    `json:"lexicon"`
+	Variant string    `json:"variant"`
+	Date    time.Time `json:"date"`
+	// Source is the file the game was imported from, if any.
+	Source string `json:"source,omitempty"`
+	// FinalScores and Winner are only set for games that are over. Winner
+	// is -1 for a tie.
+	GameOver    bool    `json:"game_over"`
+	FinalScores []int32 `json:"final_scores,omitempty"`
+	Winner      int32   `json:"winner"`
+	// Bingos and Turns count the bingos that stayed on the board and the
+	// turns taken by each player.
+	Bingos []int `json:"bingos"`
+	Turns  []int `json:"turns"`
+}
+
+// PlayerIndex returns the index of the player in the game, matching their
+// nickname or real name without regard to case, or -1 if they did not
+// play in it.
+func (e *Entry) PlayerIndex(player string) int {
+	for i, p := range e.Players {
+		if strings.EqualFold(p.Nickname, player) || strings.EqualFold(p.RealName, player) {
+			return i
+		}
+	}
+	return -1
+}
+
+// A DB is a game database in a directory. It is not safe for concurrent
+// use.
+type DB struct {
+	dir     string
+	entries []*Entry
+	byID    map[string]*Entry
+	// The indexes are by lower-case name.
+	byPlayer  map[string][]*Entry
+	byLexicon map[string][]*Entry
+	byVariant map[string][]*Entry
+}
+
+// Open opens the database in a directory, creating it if it does not exist.
+func Open(dir string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Join(dir, gamesDir), 0755); err != nil {
+		return nil, err
+	}
+	db := &DB{
+		dir:       dir,
+		byID:      map[string]*Entry{},
+		byPlayer:  map[string][]*Entry{},
+		byLexicon: map[string][]*Entry{},
+		byVariant: map[string][]*Entry{},
+	}
+	bts, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return db, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	if err := json.Unmarshal(bts, &entries); err != nil {
+		return nil, fmt.Errorf("reading %v: %w", indexFile, err)
+	}
+	for _, e := range entries {
+		db.index(e)
+	}
+	return db, nil
+}
+
+func (db *DB) index(e *Entry) {
+	db.entries = append(db.entries, e)
+	db.byID[e.ID] = e
+	seen := map[string]bool{}
+	for _, p := range e.Players {
+		for _, name := range []string{p.Nickname, p.RealName} {
+			name = strings.ToLower(name)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				db.byPlayer[name] = append(db.byPlayer[name], e)
+			}
+		}
+	}
+	db.byLexicon[strings.ToLower(e.Lexicon)] = append(db.byLexicon[strings.ToLower(e.Lexicon)], e)
+	db.byVariant[strings.ToLower(e.Variant)] = append(db.byVariant[strings.ToLower(e.Variant)], e)
+}
+
+// Len returns the number of games in the database.
+func (db *DB) Len() int {
+	return len(db.entries)
+}
+
+// Save writes the index of the database. Games are written as they are
+// put, but the index must be saved for the database to find them when it
+// is next opened.
+func (db *DB) Save() error {
+	bts, err := json.MarshalIndent(db.entries, "", " ")
+	if err != nil {
+		return err
+	}
+	// Write the index to a temporary file first, so that a crash can't
+	// leave half of it behind.
+	tmp := filepath.Join(db.dir, indexFile+".tmp")
+	if err := os.WriteFile(tmp, bts, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(db.dir, indexFile))
+}
+
+// gameID returns the ID of a game: its GCG ID if it has one, or else a hash
+// of its original GCG or events, so the same game is not stored twice.
+func gameID(h *pb.GameHistory) (string, error) {
+	if h.Uid != "" {
+		id := h.Uid
+		if h.IdAuth != "" {
+			id = h.IdAuth + "-" + id
+		}
+		// IDs are file names, so keep them to safe characters.
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+				return r
+			}
+			return '_'
+		}, id), nil
+	}
+	bts := []byte(h.OriginalGcg)
+	if len(bts) == 0 {
+		var err error
+		bts, err = proto.MarshalOptions{Deterministic: true}.Marshal(&pb.GameHistory{
+			Events: h.Events, Players: h.Players, Lexicon: h.Lexicon})
+		if err != nil {
+			return "", err
+		}
+	}
+	sum := sha256.Sum256(bts)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func newEntry(id string, h *pb.GameHistory, date time.Time, source string) *Entry {
+	_, _, variant := game.HistoryToVariant(h)
+	if variant == "" {
+		variant = game.VarClassic
+	}
+	e := &Entry{
+		ID:      id,
+		Lexicon: h.Lexicon,
+		Variant: string(variant),
+		Date:    date,
+		Source:  source,
+		Bingos:  make([]int, len(h.Players)),
+		Turns:   make([]int, len(h.Players)),
+	}
+	for _, p := range h.Players {
+		e.Players = append(e.Players, Player{Nickname: p.Nickname, RealName: p.RealName})
+	}
+	if h.PlayState == pb.PlayState_GAME_OVER && len(h.FinalScores) == len(h.Players) {
+		e.GameOver = true
+		e.FinalScores = h.FinalScores
+		e.Winner = h.Winner
+	}
+	for i, evt := range h.Events {
+		if int(evt.PlayerIndex) >= len(h.Players) {
+			continue
+		}
+		switch evt.Type {
+		case pb.GameEvent_TILE_PLACEMENT_MOVE:
+			e.Turns[evt.PlayerIndex]++
+			challengedOff := i+1 < len(h.Events) && h.Events[i+1].Type == pb.GameEvent_PHONY_TILES_RETURNED
+			if evt.IsBingo && !challengedOff {
+				e.Bingos[evt.PlayerIndex]++
+			}
+		case pb.GameEvent_EXCHANGE, pb.GameEvent_PASS, pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS:
+			e.Turns[evt.PlayerIndex]++
+		}
+	}
+	return e
+}
+
+// Put adds a game to the database, if it is not already in it. It returns
+// the game's entry, and whether it was added. The date is when the game was
+// played, and the source is where it came from, if anywhere.
+func (db *DB) Put(h *pb.GameHistory, date time.Time, source string) (*Entry, bool, error) {
+	id, err := gameID(h)
+	if err != nil {
+		return nil, false, err
+	}
+	if e, ok := db.byID[id]; ok {
+		return e, false, nil
+	}
+	bts, err := proto.Marshal(h)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := os.WriteFile(db.gamePath(id), bts, 0644); err != nil {
+		return nil, false, err
+	}
+	e := newEntry(id, h, date, source)
+	db.index(e)
+	return e, true, nil
+}
+
+func (db *DB) gamePath(id string) string {
+	return filepath.Join(db.dir, gamesDir, id+".pb")
+}
+
+// Get returns the history of a game.
+func (db *DB) Get(id string) (*pb.GameHistory, error) {
+	if _, ok := db.byID[id]; !ok {
+		return nil, errGameNotFound
+	}
+	bts, err := os.ReadFile(db.gamePath(id))
+	if err != nil {
+		return nil, err
+	}
+	h := &pb.GameHistory{}
+	if err := proto.Unmarshal(bts, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// A Query selects games. Empty fields match every game; names match
+// without regard to case, and players by nickname or real name.
+type Query struct {
+	Player  string
+	Lexicon string
+	Variant string
+	// From and To are inclusive.
+	From time.Time
+	To   time.Time
+}
+
+// Find returns the entries of the games that match the query, from the
+// earliest played.
+func (db *DB) Find(q Query) []*Entry {
+	// Start from the smallest index that applies.
+	candidates := db.entries
+	for _, idx := range []struct {
+		m   map[string][]*Entry
+		key string
+	}{{db.byPlayer, q.Player}, {db.byLexicon, q.Lexicon}, {db.byVariant, q.Variant}} {
+		if idx.key == "" {
+			continue
+		}
+		if es := idx.m[strings.ToLower(idx.key)]; len(es) < len(candidates) {
+			candidates = es
+		}
+	}
+	found := []*Entry{}
+	for _, e := range candidates {
+		if q.Player != "" && e.PlayerIndex(q.Player) < 0 ||
+			q.Lexicon != "" && !strings.EqualFold(e.Lexicon, q.Lexicon) ||
+			q.Variant != "" && !strings.EqualFold(e.Variant, q.Variant) ||
+			!q.From.IsZero() && e.Date.Before(q.From) ||
+			!q.To.IsZero() && e.Date.After(q.To) {
+			continue
+		}
+		found = append(found, e)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Date.Before(found[j].Date) })
+	return found
+}
+
+// An ImportResult counts the GCG files of an import.
+type ImportResult struct {
+	Added      int
+	Duplicates int
+	// Warnings counts the problems that were worked around in the files
+	// that were imported; see gcgio.ParseGCGLenient.
+	Warnings int
+	// Failed has the files that could not be imported, and why.
+	Failed map[string]error
+}
+
+// Import imports a GCG file, or every GCG file under a directory, and saves
+// the index. GCG files are parsed leniently, and as they have no date, the
+// date of a game is the time its file was last modified.
+func (db *DB) Import(cfg *config.Config, path string) (*ImportResult, error) {
+	res := &ImportResult{Failed: map[string]error{}}
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".gcg") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		h, diags, err := gcgio.ParseGCGLenient(cfg, p)
+		if err != nil {
+			log.Debug().Err(err).Str("file", p).Msg("import-failed")
+			res.Failed[p] = err
+			return nil
+		}
+		_, added, err := db.Put(h, info.ModTime(), p)
+		if err != nil {
+			return err
+		}
+		if added {
+			res.Added++
+			res.Warnings += len(diags)
+		} else {
+			res.Duplicates++
+		}
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	return res, db.Save()
+}
@@ -0,0 +1,105 @@
+package gamedb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/config"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// importTestGames imports some GCGs, each played a day after the last.
+func importTestGames(is *is.I, dbDir string) (*DB, time.Time) {
+	gcgDir := filepath.Join(dbDir, "gcgs")
+	is.NoErr(os.MkdirAll(gcgDir, 0755))
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, f := range []string{"vs_andy.gcg", "vs_frentz.gcg", "noah_vs_peter.gcg", "josh2.gcg",
+		"guy_vs_bot_almost_complete.gcg"} {
+		bts, err := os.ReadFile(filepath.Join("..", "gcgio", "testdata", f))
+		is.NoErr(err)
+		path := filepath.Join(gcgDir, f)
+		is.NoErr(os.WriteFile(path, bts, 0644))
+		date := start.AddDate(0, 0, i)
+		is.NoErr(os.Chtimes(path, date, date))
+	}
+	db, err := Open(filepath.Join(dbDir, "db"))
+	is.NoErr(err)
+	res, err := db.Import(&DefaultConfig, gcgDir)
+	is.NoErr(err)
+	is.Equal(res.Added, 5)
+	is.Equal(len(res.Failed), 0)
+	return db, start
+}
+
+func TestImport(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	db, _ := importTestGames(is, dir)
+	is.Equal(db.Len(), 5)
+
+	// Importing the same games again adds nothing.
+	res, err := db.Import(&DefaultConfig, filepath.Join(dir, "gcgs"))
+	is.NoErr(err)
+	is.Equal(res.Added, 0)
+	is.Equal(res.Duplicates, 5)
+
+	// The games are there when the database is opened again.
+	db, err = Open(filepath.Join(dir, "db"))
+	is.NoErr(err)
+	is.Equal(db.Len(), 5)
+	found := db.Find(Query{Player: "Frentz"})
+	is.Equal(len(found), 1)
+	h, err := db.Get(found[0].ID)
+	is.NoErr(err)
+	is.Equal(h.Players[1].Nickname, "frentz")
+	is.Equal(len(h.Events) > 0, true)
+	_, err = db.Get("nope")
+	is.Equal(err, errGameNotFound)
+}
+
+func TestFind(t *testing.T) {
+	is := is.New(t)
+	db, start := importTestGames(is, t.TempDir())
+
+	found := db.Find(Query{Player: "cesar"})
+	is.Equal(len(found), 2)
+	is.Equal(found[0].Source, filepath.Join(filepath.Dir(found[0].Source), "vs_andy.gcg"))
+	is.True(found[0].Date.Before(found[1].Date))
+
+	is.Equal(len(db.Find(Query{Player: "cesar", Lexicon: DefaultConfig.DefaultLexicon})), 2)
+	is.Equal(len(db.Find(Query{Player: "cesar", Lexicon: "CSW21"})), 0)
+	is.Equal(len(db.Find(Query{Variant: "classic"})), 5)
+	// Real names are matched too.
+	is.Equal(len(db.Find(Query{Player: "peter armstrong"})), 1)
+	is.Equal(len(db.Find(Query{From: start.AddDate(0, 0, 1), To: start.AddDate(0, 0, 2)})), 2)
+}
+
+func TestStats(t *testing.T) {
+	is := is.New(t)
+	db, _ := importTestGames(is, t.TempDir())
+
+	s := db.Stats(Query{Player: "cesar"})
+	is.Equal(s.Games, 2)
+	is.Equal(s.Wins, 0)
+	is.Equal(s.Losses, 2)
+	is.Equal(s.AverageScore(), 401.0)
+	is.Equal(s.AverageOpponentScore(), 486.5)
+	is.Equal(s.BingosPerGame(), 1.5)
+	is.Equal(s.FirstSeats, 1)
+	is.Equal(s.FirstWinRate(), 0.0)
+
+	// The unfinished game does not count.
+	s = db.Stats(Query{})
+	is.Equal(s.Games, 4)
+	is.Equal(s.Seats, 8)
+	is.Equal(s.Wins, 4)
+	is.Equal(s.FirstSeats, 4)
+	is.Equal(s.FirstWins, 3)
+	is.Equal(s.FirstWinRate(), 0.75)
+	is.Equal(s.SecondWinRate(), 0.25)
+}
@@ -0,0 +1,116 @@
+package gamedb
+
+// Stats sum up a set of games, from the point of view of one player, or of
+// every player if no player is given. Only games that are over count.
+type Stats struct {
+	Games int
+	// Seats is the number of players counted: one per game for a single
+	// player, and every player of every game otherwise.
+	Seats              int
+	Wins, Losses, Ties int
+	// Points are the final scores of the players counted, and
+	// OpponentPoints the mean final score of their opponents.
+	Points         int
+	OpponentPoints float64
+	Bingos         int
+	Turns          int
+	// FirstSeats and FirstWins are the seats that went first, and their
+	// wins.
+	FirstSeats, FirstWins int
+}
+
+// AverageScore is the mean final score of the players counted.
+func (s *Stats) AverageScore() float64 {
+	return ratio(float64(s.Points), s.Seats)
+}
+
+// AverageOpponentScore is the mean final score of their opponents.
+func (s *Stats) AverageOpponentScore() float64 {
+	return ratio(s.OpponentPoints, s.Seats)
+}
+
+// BingosPerGame is the mean number of bingos each player counted made.
+func (s *Stats) BingosPerGame() float64 {
+	return ratio(float64(s.Bingos), s.Seats)
+}
+
+// WinRate is the fraction of games won, with ties as half a win.
+func (s *Stats) WinRate() float64 {
+	return ratio(float64(s.Wins)+float64(s.Ties)/2, s.Seats)
+}
+
+// FirstWinRate is the fraction of games won when going first.
+func (s *Stats) FirstWinRate() float64 {
+	return ratio(float64(s.FirstWins), s.FirstSeats)
+}
+
+// SecondWinRate is the fraction of games won when not going first.
+func (s *Stats) SecondWinRate() float64 {
+	return ratio(float64(s.Wins-s.FirstWins), s.Seats-s.FirstSeats)
+}
+
+func ratio(a float64, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / float64(b)
+}
+
+// Stats sums up the games that match the query. If the query has a player,
+// the stats are theirs.
+func (db *DB) Stats(q Query) *Stats {
+	s := &Stats{}
+	for _, e := range db.Find(q) {
+		if !e.GameOver {
+			continue
+		}
+		s.Games++
+		seats := []int{}
+		if q.Player != "" {
+			seats = append(seats, e.PlayerIndex(q.Player))
+		} else {
+			for i := range e.Players {
+				seats = append(seats, i)
+			}
+		}
+		total := 0
+		for _, score := range e.FinalScores {
+			total += int(score)
+		}
+		for _, i := range seats {
+			s.Seats++
+			s.Points += int(e.FinalScores[i])
+			if len(e.Players) > 1 {
+				s.OpponentPoints += float64(total-int(e.FinalScores[i])) / float64(len(e.Players)-1)
+			}
+			s.Bingos += e.Bingos[i]
+			s.Turns += e.Turns[i]
+			won := e.Winner == int32(i)
+			switch {
+			case won:
+				s.Wins++
+			case e.Winner == -1 && e.FinalScores[i] == maxScore(e.FinalScores):
+				s.Ties++
+			default:
+				s.Losses++
+			}
+			if i == 0 {
+				s.FirstSeats++
+				if won {
+					s.FirstWins++
+				}
+			}
+		}
+	}
+	return s
+}
+
+func maxScore(scores []int32) int32 {
+	best := scores[0]
+	for _, s := range scores[1:] {
+		if s > best {
+			best = s
+		}
+	}
+	return best
+}
@@ -0,0 +1,156 @@
+package gcgio
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/domino14/macondo/cache"
+	"github.com/domino14/macondo/config"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/lexicon"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// A Severity says how bad a problem in a GCG file is.
+type Severity int
+
+const (
+	// A Warning is a problem that was worked around, such as a line that
+	// was skipped or a score that was repaired.
+	Warning Severity = iota
+	// An Error is a play that is not legal, or that could not be played.
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// A Diagnostic is a problem found in a GCG file.
+type Diagnostic struct {
+	// Line is the line of the file, from 1.
+	Line     int
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d: %v: %v", d.Line, d.Severity, d.Message)
+}
+
+func (p *parser) warn(message string) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Line: p.line, Severity: Warning, Message: message})
+}
+
+func (p *parser) error(message string) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Line: p.line, Severity: Error, Message: message})
+}
+
+// repairScore scores a tile placement move on the board, and fixes the
+// recorded score if it is wrong. Moves that can't be scored are left alone,
+// as playing them will fail.
+func (p *parser) repairScore(evt *pb.GameEvent) {
+	m, err := p.game.CreateAndScorePlacementMove(evt.Position, evt.PlayedTiles, evt.Rack)
+	if err != nil {
+		return
+	}
+	if int32(m.Score()) != evt.Score {
+		p.warn(fmt.Sprintf("%v %v scores %d, not %d", evt.Position, evt.PlayedTiles, m.Score(), evt.Score))
+		evt.Score = int32(m.Score())
+	}
+}
+
+// repairCumulative fixes the cumulative score of an event that was just
+// played if it does not match the player's score.
+func (p *parser) repairCumulative(evt *pb.GameEvent) {
+	pts := int32(p.game.PointsFor(int(evt.PlayerIndex)))
+	if pts != evt.Cumulative {
+		p.warn(fmt.Sprintf("cumulative score for %v is %d, not %d",
+			p.history.Players[evt.PlayerIndex].Nickname, pts, evt.Cumulative))
+		evt.Cumulative = pts
+	}
+}
+
+// ParseGCGFromReaderLenient parses a GCG like ParseGCGFromReader, but keeps
+// going past problems, recording a diagnostic for each. Unknown lines and
+// misplaced pragmas are skipped, events that can't be played are dropped,
+// and wrong scores and cumulative scores are repaired. It only fails if the
+// file can't be read, or no game can be set up from it.
+func ParseGCGFromReaderLenient(cfg *config.Config, reader io.Reader) (*pb.GameHistory, []Diagnostic, error) {
+	p, err := parse(cfg, reader, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.history, p.diagnostics, nil
+}
+
+// ParseGCGLenient parses a GCG file leniently; see ParseGCGFromReaderLenient.
+func ParseGCGLenient(cfg *config.Config, filename string) (*pb.GameHistory, []Diagnostic, error) {
+	f, err := cache.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ParseGCGFromReaderLenient(cfg, f)
+}
+
+// ValidateGCGFromReader parses a GCG leniently and checks the words that
+// every tile placement move forms against the lexicon. Plays with invalid
+// words are errors unless they were challenged off, and plays that were
+// challenged off even though their words are valid are warnings. The
+// diagnostics of the parse are returned too, in order of line.
+func ValidateGCGFromReader(cfg *config.Config, reader io.Reader, lex lexicon.Lexicon) (*pb.GameHistory, []Diagnostic, error) {
+	p, err := parse(cfg, reader, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	alph := p.game.Alphabet()
+	events := p.history.Events
+	for i, evt := range events {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+			continue
+		}
+		var invalid []string
+		for _, w := range evt.WordsFormed {
+			mw, err := tilemapping.ToMachineWord(w, alph)
+			if err != nil {
+				return nil, nil, err
+			}
+			if p.game.ValidateWords(lex, []tilemapping.MachineWord{mw}) != nil {
+				invalid = append(invalid, w)
+			}
+		}
+		challengedOff := i+1 < len(events) && events[i+1].Type == pb.GameEvent_PHONY_TILES_RETURNED
+		d := Diagnostic{Line: p.eventLines[i]}
+		switch {
+		case len(invalid) > 0 && !challengedOff:
+			d.Severity = Error
+			d.Message = fmt.Sprintf("%v %v forms words not in %v: %v", evt.Position, evt.PlayedTiles,
+				lex.Name(), strings.Join(invalid, ", "))
+		case len(invalid) == 0 && challengedOff:
+			d.Severity = Warning
+			d.Message = fmt.Sprintf("%v %v was challenged off, but its words are in %v",
+				evt.Position, evt.PlayedTiles, lex.Name())
+		default:
+			continue
+		}
+		p.diagnostics = append(p.diagnostics, d)
+	}
+	sort.SliceStable(p.diagnostics, func(i, j int) bool {
+		return p.diagnostics[i].Line < p.diagnostics[j].Line
+	})
+	return p.history, p.diagnostics, nil
+}
+
+// ValidateGCG validates a GCG file; see ValidateGCGFromReader.
+func ValidateGCG(cfg *config.Config, filename string, lex lexicon.Lexicon) (*pb.GameHistory, []Diagnostic, error) {
+	f, err := cache.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ValidateGCGFromReader(cfg, f, lex)
+}
@@ -0,0 +1,102 @@
+package gcgio
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// testLexicon has just the given words.
+type testLexicon struct {
+	alph  *tilemapping.TileMapping
+	words map[string]bool
+}
+
+func (l testLexicon) Name() string                              { return "TEST" }
+func (l testLexicon) GetAlphabet() *tilemapping.TileMapping     { return l.alph }
+func (l testLexicon) HasWord(w tilemapping.MachineWord) bool    { return l.words[w.UserVisible(l.alph)] }
+func (l testLexicon) HasAnagram(w tilemapping.MachineWord) bool { return false }
+
+func TestParseLenient(t *testing.T) {
+	is := is.New(t)
+	gcg := `#character-encoding UTF-8
+#player1 alice Alice A
+#player2 bob Bob B
+#rating alice 1500
+>alice: AEINRST 8D STAIR +14 14
+this is not a gcg line
+>carol: EHLOOTW E5 WHO.E +22 22
+>bob: EHLOOTW E5 WHO.E +22 20
+#lexicon CSW21
+>alice: DEENOSU 8A ZZZ +30 42
+`
+	_, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	is.True(err != nil)
+
+	history, diags, err := ParseGCGFromReaderLenient(&DefaultConfig, strings.NewReader(gcg))
+	is.NoErr(err)
+	is.Equal(len(history.Events), 2)
+	is.Equal(history.Events[0].Score, int32(12))
+	is.Equal(history.Events[0].Cumulative, int32(12))
+	is.Equal(history.Events[1].Cumulative, int32(22))
+	// The misplaced lexicon pragma was skipped.
+	is.Equal(history.Lexicon, DefaultConfig.DefaultLexicon)
+
+	expected := []struct {
+		line     int
+		severity Severity
+	}{
+		{4, Warning}, {5, Warning}, {5, Warning}, {6, Warning},
+		{7, Error}, {8, Warning}, {9, Warning}, {10, Error},
+	}
+	is.Equal(len(diags), len(expected))
+	for i, e := range expected {
+		is.Equal(diags[i].Line, e.line)
+		is.Equal(diags[i].Severity, e.severity)
+	}
+	is.Equal(diags[1].Message, "8D STAIR scores 12, not 14")
+}
+
+func TestParseLenientCleanGCGs(t *testing.T) {
+	is := is.New(t)
+	for _, f := range []string{"vs_andy.gcg", "doug_v_emely.gcg", "josh2.gcg", "vs_frentz.gcg",
+		"three_players.gcg", "name_iso8859-1.gcg"} {
+
+		strict, err := ParseGCG(&DefaultConfig, filepath.Join("testdata", f))
+		is.NoErr(err)
+		lenient, diags, err := ParseGCGLenient(&DefaultConfig, filepath.Join("testdata", f))
+		is.NoErr(err)
+		is.Equal(len(diags), 0)
+		is.Equal(len(lenient.Events), len(strict.Events))
+	}
+}
+
+func TestValidateGCG(t *testing.T) {
+	is := is.New(t)
+	gcg := `#player1 alice Alice A
+#player2 bob Bob B
+>alice: AEINRST 8D STAIR +12 12
+>bob: EHLOOTW E5 WHO.E +22 22
+>bob: EHLOOTW -- -22 0
+>alice: ADEGIMN G6 MA.I +8 20
+>bob: EHLOOSW 8D .....S +6 6
+>bob: EHLOOSW -- -6 0
+`
+	dist, err := tilemapping.GetDistribution(&DefaultConfig, "english")
+	is.NoErr(err)
+	lex := testLexicon{
+		alph:  dist.TileMapping(),
+		words: map[string]bool{"STAIR": true, "STAIRS": true},
+	}
+	_, diags, err := ValidateGCGFromReader(&DefaultConfig, strings.NewReader(gcg), lex)
+	is.NoErr(err)
+	is.Equal(len(diags), 2)
+	// WHOTE was challenged off, so it is fine.
+	is.Equal(diags[0], Diagnostic{Line: 6, Severity: Error, Message: "G6 MA.I forms words not in TEST: MAII"})
+	is.Equal(diags[1], Diagnostic{Line: 7, Severity: Warning,
+		Message: "8D .....S was challenged off, but its words are in TEST"})
+}
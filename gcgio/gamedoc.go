@@ -0,0 +1,537 @@
+package gcgio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/macondo/cache"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// Game documents are the JSON form of the GameDocument messages that online
+// play sites such as Woogles keep their games in. Unlike a GameHistory,
+// they store tiles as machine letters, encoded in base64, and they have the
+// racks and timers of a game in progress.
+
+var (
+	errBadDocumentTile = errors.New("game document has a tile that is not in the letter distribution")
+	errNoPlayers       = errors.New("game document has no players")
+)
+
+// Event types that game documents have, and game histories don't, by name
+// and by number.
+const (
+	docEventTimedOut       = "TIMED_OUT"
+	docEventResigned       = "RESIGNED"
+	docEventTimedOutNumber = "10"
+	docEventResignedNumber = "11"
+)
+
+type gameDocument struct {
+	Players            []docPlayer `json:"players"`
+	Events             []docEvent  `json:"events"`
+	Version            uint32      `json:"version,omitempty"`
+	Lexicon            string      `json:"lexicon,omitempty"`
+	Uid                string      `json:"uid,omitempty"`
+	Description        string      `json:"description,omitempty"`
+	Racks              [][]byte    `json:"racks,omitempty"`
+	ChallengeRule      docEnum     `json:"challengeRule,omitempty"`
+	PlayState          docEnum     `json:"playState,omitempty"`
+	CurrentScores      []int32     `json:"currentScores,omitempty"`
+	Variant            string      `json:"variant,omitempty"`
+	Winner             int32       `json:"winner,omitempty"`
+	BoardLayout        string      `json:"boardLayout,omitempty"`
+	LetterDistribution string      `json:"letterDistribution,omitempty"`
+	EndReason          docEnum     `json:"endReason,omitempty"`
+	ScorelessTurns     uint32      `json:"scorelessTurns,omitempty"`
+	PlayerOnTurn       uint32      `json:"playerOnTurn,omitempty"`
+	Timers             *docTimers  `json:"timers,omitempty"`
+	Title              string      `json:"title,omitempty"`
+}
+
+type docPlayer struct {
+	Nickname string `json:"nickname"`
+	RealName string `json:"realName,omitempty"`
+	UserId   string `json:"userId,omitempty"`
+	Quit     bool   `json:"quit,omitempty"`
+}
+
+type docEvent struct {
+	Note                string   `json:"note,omitempty"`
+	Rack                []byte   `json:"rack,omitempty"`
+	Type                docEnum  `json:"type,omitempty"`
+	Cumulative          int32    `json:"cumulative,omitempty"`
+	Row                 int32    `json:"row,omitempty"`
+	Column              int32    `json:"column,omitempty"`
+	Direction           docEnum  `json:"direction,omitempty"`
+	Position            string   `json:"position,omitempty"`
+	PlayedTiles         []byte   `json:"playedTiles,omitempty"`
+	Exchanged           []byte   `json:"exchanged,omitempty"`
+	Score               int32    `json:"score,omitempty"`
+	Bonus               int32    `json:"bonus,omitempty"`
+	EndRackPoints       int32    `json:"endRackPoints,omitempty"`
+	LostScore           int32    `json:"lostScore,omitempty"`
+	IsBingo             bool     `json:"isBingo,omitempty"`
+	WordsFormed         [][]byte `json:"wordsFormed,omitempty"`
+	MillisRemaining     int32    `json:"millisRemaining,omitempty"`
+	PlayerIndex         uint32   `json:"playerIndex,omitempty"`
+	WordsFormedFriendly []string `json:"wordsFormedFriendly,omitempty"`
+}
+
+type docTimers struct {
+	TimeOfLastUpdate docInt64   `json:"timeOfLastUpdate,omitempty"`
+	TimeStarted      docInt64   `json:"timeStarted,omitempty"`
+	TimeRemaining    []docInt64 `json:"timeRemaining,omitempty"`
+	MaxOvertime      int32      `json:"maxOvertime,omitempty"`
+	IncrementSeconds int32      `json:"incrementSeconds,omitempty"`
+	Untimed          bool       `json:"untimed,omitempty"`
+}
+
+// A docEnum is an enum, which is written as its name, but may be read as
+// its name or its number.
+type docEnum string
+
+func (e *docEnum) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*e = docEnum(s)
+		return nil
+	}
+	var n int32
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("bad enum %s", b)
+	}
+	*e = docEnum(strconv.Itoa(int(n)))
+	return nil
+}
+
+// value returns the number of the enum, looking its name up without the
+// prefix in values.
+func (e docEnum) value(prefix string, values map[string]int32) (int32, error) {
+	if e == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(string(e)); err == nil {
+		return int32(n), nil
+	}
+	v, ok := values[strings.TrimPrefix(string(e), prefix)]
+	if !ok {
+		return 0, fmt.Errorf("unknown value %v", e)
+	}
+	return v, nil
+}
+
+// A docInt64 is a 64-bit integer, which is written as a string, as
+// JavaScript can't hold all of them as numbers, but may be read as either.
+type docInt64 int64
+
+func (i docInt64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+func (i *docInt64) UnmarshalJSON(b []byte) error {
+	s := string(bytes.Trim(b, `"`))
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = docInt64(n)
+	return nil
+}
+
+// camelCaseKeys renames the snake_case keys of decoded JSON objects to
+// lowerCamelCase, as documents can be written with either.
+func camelCaseKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			parts := strings.Split(k, "_")
+			for i := 1; i < len(parts); i++ {
+				if parts[i] != "" {
+					r := []rune(parts[i])
+					r[0] = unicode.ToUpper(r[0])
+					parts[i] = string(r)
+				}
+			}
+			m[strings.Join(parts, "")] = camelCaseKeys(val)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = camelCaseKeys(v[i])
+		}
+	}
+	return v
+}
+
+func docLetters(bts []byte, alph *tilemapping.TileMapping) (tilemapping.MachineWord, error) {
+	mw := make(tilemapping.MachineWord, len(bts))
+	for i, b := range bts {
+		ml := tilemapping.MachineLetter(b)
+		if ml.Unblank() >= tilemapping.MachineLetter(alph.NumLetters()) {
+			return nil, errBadDocumentTile
+		}
+		mw[i] = ml
+	}
+	return mw, nil
+}
+
+func docEventToGameEvent(de *docEvent, alph *tilemapping.TileMapping) (*pb.GameEvent, error) {
+	t, err := de.Type.value("", pb.GameEvent_Type_value)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := de.Direction.value("", pb.GameEvent_Direction_value)
+	if err != nil {
+		return nil, err
+	}
+	evt := &pb.GameEvent{
+		Note:            de.Note,
+		Type:            pb.GameEvent_Type(t),
+		Cumulative:      de.Cumulative,
+		Row:             de.Row,
+		Column:          de.Column,
+		Direction:       pb.GameEvent_Direction(dir),
+		Position:        de.Position,
+		Score:           de.Score,
+		Bonus:           de.Bonus,
+		EndRackPoints:   de.EndRackPoints,
+		LostScore:       de.LostScore,
+		IsBingo:         de.IsBingo,
+		MillisRemaining: de.MillisRemaining,
+		PlayerIndex:     de.PlayerIndex,
+	}
+	rack, err := docLetters(de.Rack, alph)
+	if err != nil {
+		return nil, err
+	}
+	evt.Rack = rack.UserVisible(alph)
+	played, err := docLetters(de.PlayedTiles, alph)
+	if err != nil {
+		return nil, err
+	}
+	evt.PlayedTiles = played.UserVisiblePlayedTiles(alph)
+	exchanged, err := docLetters(de.Exchanged, alph)
+	if err != nil {
+		return nil, err
+	}
+	evt.Exchanged = exchanged.UserVisible(alph)
+	evt.WordsFormed = de.WordsFormedFriendly
+	if len(evt.WordsFormed) == 0 {
+		for _, bts := range de.WordsFormed {
+			w, err := docLetters(bts, alph)
+			if err != nil {
+				return nil, err
+			}
+			evt.WordsFormed = append(evt.WordsFormed, w.UserVisible(alph))
+		}
+	}
+	if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE && evt.Position == "" {
+		evt.Position = move.ToBoardGameCoords(int(evt.Row), int(evt.Column),
+			evt.Direction == pb.GameEvent_VERTICAL)
+	}
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		evt.NumTilesFromRack = uint32(len(played) - strings.Count(evt.PlayedTiles, "."))
+	case pb.GameEvent_EXCHANGE:
+		evt.NumTilesFromRack = uint32(len(exchanged))
+	}
+	return evt, nil
+}
+
+// ParseGameDocumentFromReader reads a game document, and checks it by
+// replaying its events.
+func ParseGameDocumentFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory, error) {
+	dec := json.NewDecoder(reader)
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	bts, err := json.Marshal(camelCaseKeys(raw))
+	if err != nil {
+		return nil, err
+	}
+	doc := &gameDocument{}
+	if err := json.Unmarshal(bts, doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Players) == 0 {
+		return nil, errNoPlayers
+	}
+
+	h := &pb.GameHistory{
+		Version:            game.CurrentGameHistoryVersion,
+		Lexicon:            doc.Lexicon,
+		Uid:                doc.Uid,
+		Title:              doc.Title,
+		Description:        doc.Description,
+		Variant:            doc.Variant,
+		BoardLayout:        doc.BoardLayout,
+		LetterDistribution: doc.LetterDistribution,
+	}
+	for _, p := range doc.Players {
+		h.Players = append(h.Players, &pb.PlayerInfo{Nickname: p.Nickname, RealName: p.RealName, UserId: p.UserId})
+	}
+	rule, err := doc.ChallengeRule.value("ChallengeRule_", pb.ChallengeRule_value)
+	if err != nil {
+		return nil, err
+	}
+	h.ChallengeRule = pb.ChallengeRule(rule)
+	state, err := doc.PlayState.value("", pb.PlayState_value)
+	if err != nil && doc.PlayState != "UNSTARTED" {
+		return nil, err
+	}
+	h.PlayState = pb.PlayState(state)
+	reason, err := doc.EndReason.value("", pb.GameEndReason_value)
+	if err != nil {
+		return nil, err
+	}
+	h.EndReason = pb.GameEndReason(reason)
+	if doc.Timers != nil && !doc.Timers.Untimed {
+		h.Timers = &pb.GameTimers{
+			IncrementMillis:   int64(doc.Timers.IncrementSeconds) * 1000,
+			MaxOvertimeMillis: int64(doc.Timers.MaxOvertime) * time.Minute.Milliseconds(),
+		}
+		for _, t := range doc.Timers.TimeRemaining {
+			h.Timers.MillisRemaining = append(h.Timers.MillisRemaining, int64(t))
+		}
+	}
+
+	_, ldName, _ := game.HistoryToVariant(h)
+	dist, err := tilemapping.GetDistribution(cfg, ldName)
+	if err != nil {
+		return nil, err
+	}
+	alph := dist.TileMapping()
+	for i := range doc.Events {
+		// Timeouts and resignations end the game, but are not turns.
+		switch doc.Events[i].Type {
+		case docEventTimedOut, docEventTimedOutNumber:
+			h.PlayState = pb.PlayState_GAME_OVER
+			if h.EndReason == pb.GameEndReason_NONE {
+				h.EndReason = pb.GameEndReason_TIME
+			}
+			continue
+		case docEventResigned, docEventResignedNumber:
+			h.PlayState = pb.PlayState_GAME_OVER
+			if h.EndReason == pb.GameEndReason_NONE {
+				h.EndReason = pb.GameEndReason_RESIGNED
+			}
+			continue
+		}
+		evt, err := docEventToGameEvent(&doc.Events[i], alph)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		if int(evt.PlayerIndex) >= len(h.Players) {
+			return nil, fmt.Errorf("event %d: %w", i, errPlayerDoesNotExist)
+		}
+		h.Events = append(h.Events, evt)
+	}
+	if h.PlayState == pb.PlayState_GAME_OVER {
+		h.FinalScores = doc.CurrentScores
+		h.Winner = doc.Winner
+	} else {
+		for _, bts := range doc.Racks {
+			rack, err := docLetters(bts, alph)
+			if err != nil {
+				return nil, err
+			}
+			h.LastKnownRacks = append(h.LastKnownRacks, rack.UserVisible(alph))
+		}
+	}
+	if err := checkReplay(cfg, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// checkReplay replays a copy of a history, and checks that every player's
+// score after the last event matches its cumulative score.
+func checkReplay(cfg *config.Config, h *pb.GameHistory) error {
+	boardLayout, ldName, variant := game.HistoryToVariant(h)
+	// As for GCGs, words aren't checked, so no lexicon needs to be loaded.
+	rules, err := game.NewBasicGameRules(cfg, "", boardLayout, ldName, game.CrossScoreOnly, variant)
+	if err != nil {
+		return err
+	}
+	replay := proto.Clone(h).(*pb.GameHistory)
+	g, err := game.NewFromHistory(replay, rules, len(replay.Events))
+	if err != nil {
+		return fmt.Errorf("replaying game: %w", err)
+	}
+	last := make([]*pb.GameEvent, len(h.Players))
+	for _, evt := range h.Events {
+		last[evt.PlayerIndex] = evt
+	}
+	for i, evt := range last {
+		if evt != nil && int(evt.Cumulative) != g.PointsFor(i) {
+			return fmt.Errorf("%v has %d points after replaying the game, not %d",
+				h.Players[i].Nickname, g.PointsFor(i), evt.Cumulative)
+		}
+	}
+	return nil
+}
+
+// ParseGameDocument reads a game document file into a GameHistory.
+func ParseGameDocument(cfg *config.Config, filename string) (*pb.GameHistory, error) {
+	f, err := cache.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGameDocumentFromReader(cfg, f)
+}
+
+func docTiles(s string, alph *tilemapping.TileMapping) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	mls, err := tilemapping.ToMachineLetters(s, alph)
+	if err != nil {
+		return nil, err
+	}
+	bts := make([]byte, len(mls))
+	for i, ml := range mls {
+		bts[i] = byte(ml)
+	}
+	return bts, nil
+}
+
+// GameHistoryToGameDocument returns the JSON game document of a
+// GameHistory.
+func GameHistoryToGameDocument(cfg *config.Config, h *pb.GameHistory) ([]byte, error) {
+	if h.StartingCgp != "" {
+		return nil, errors.New("cannot turn a game history with a starting CGP into a game document")
+	}
+	_, ldName, _ := game.HistoryToVariant(h)
+	dist, err := tilemapping.GetDistribution(cfg, ldName)
+	if err != nil {
+		return nil, err
+	}
+	alph := dist.TileMapping()
+	doc := &gameDocument{
+		Version:            uint32(h.Version),
+		Lexicon:            h.Lexicon,
+		Uid:                h.Uid,
+		Title:              h.Title,
+		Description:        h.Description,
+		ChallengeRule:      docEnum("ChallengeRule_" + h.ChallengeRule.String()),
+		PlayState:          docEnum(h.PlayState.String()),
+		Variant:            h.Variant,
+		Winner:             h.Winner,
+		BoardLayout:        h.BoardLayout,
+		LetterDistribution: h.LetterDistribution,
+		CurrentScores:      make([]int32, len(h.Players)),
+		Timers:             &docTimers{Untimed: true},
+	}
+	timeRemaining := make([]docInt64, len(h.Players))
+	for _, p := range h.Players {
+		doc.Players = append(doc.Players, docPlayer{Nickname: p.Nickname, RealName: p.RealName, UserId: p.UserId})
+	}
+	for _, evt := range h.Events {
+		de := docEvent{
+			Note:                evt.Note,
+			Type:                docEnum(evt.Type.String()),
+			Cumulative:          evt.Cumulative,
+			Row:                 evt.Row,
+			Column:              evt.Column,
+			Direction:           docEnum(evt.Direction.String()),
+			Position:            evt.Position,
+			Score:               evt.Score,
+			Bonus:               evt.Bonus,
+			EndRackPoints:       evt.EndRackPoints,
+			LostScore:           evt.LostScore,
+			IsBingo:             evt.IsBingo,
+			MillisRemaining:     evt.MillisRemaining,
+			PlayerIndex:         evt.PlayerIndex,
+			WordsFormedFriendly: evt.WordsFormed,
+		}
+		if de.Rack, err = docTiles(evt.Rack, alph); err != nil {
+			return nil, err
+		}
+		if de.PlayedTiles, err = docTiles(evt.PlayedTiles, alph); err != nil {
+			return nil, err
+		}
+		if de.Exchanged, err = docTiles(evt.Exchanged, alph); err != nil {
+			return nil, err
+		}
+		for _, w := range evt.WordsFormed {
+			bts, err := docTiles(w, alph)
+			if err != nil {
+				return nil, err
+			}
+			de.WordsFormed = append(de.WordsFormed, bts)
+		}
+		if evt.MillisRemaining != 0 {
+			doc.Timers.Untimed = false
+			timeRemaining[evt.PlayerIndex] = docInt64(evt.MillisRemaining)
+		}
+		doc.CurrentScores[evt.PlayerIndex] = evt.Cumulative
+		doc.Events = append(doc.Events, de)
+	}
+	if t := h.Timers; t != nil {
+		doc.Timers.Untimed = false
+		doc.Timers.IncrementSeconds = int32(t.IncrementMillis / 1000)
+		// Documents only have whole minutes of overtime; round up, so
+		// that no one loses on time any sooner.
+		minute := time.Minute.Milliseconds()
+		doc.Timers.MaxOvertime = int32((t.MaxOvertimeMillis + minute - 1) / minute)
+		if len(t.MillisRemaining) == len(h.Players) {
+			for i, ms := range t.MillisRemaining {
+				timeRemaining[i] = docInt64(ms)
+			}
+		}
+	}
+	if !doc.Timers.Untimed {
+		doc.Timers.TimeRemaining = timeRemaining
+	}
+	if h.PlayState == pb.PlayState_GAME_OVER {
+		doc.EndReason = docEnum(endReason(h).String())
+		if len(h.FinalScores) == len(h.Players) {
+			doc.CurrentScores = h.FinalScores
+		}
+	} else {
+		for _, r := range h.LastKnownRacks {
+			bts, err := docTiles(r, alph)
+			if err != nil {
+				return nil, err
+			}
+			doc.Racks = append(doc.Racks, bts)
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// endReason returns why the game of a history ended. Histories that don't
+// say, such as those of GCGs, ended on scoreless turns if the players lost
+// the tiles left on their racks, and with a player going out otherwise.
+func endReason(h *pb.GameHistory) pb.GameEndReason {
+	if h.EndReason != pb.GameEndReason_NONE {
+		return h.EndReason
+	}
+	reason := pb.GameEndReason_STANDARD
+	for _, evt := range h.Events {
+		switch evt.Type {
+		case pb.GameEvent_END_RACK_PTS:
+			return pb.GameEndReason_STANDARD
+		case pb.GameEvent_END_RACK_PENALTY:
+			reason = pb.GameEndReason_CONSECUTIVE_ZEROES
+		}
+	}
+	return reason
+}
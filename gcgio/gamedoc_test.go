@@ -0,0 +1,179 @@
+package gcgio
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+func TestGameHistoryToGameDocument(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCG(&DefaultConfig, "testdata/vs_frentz.gcg")
+	is.NoErr(err)
+	bts, err := GameHistoryToGameDocument(&DefaultConfig, h)
+	is.NoErr(err)
+	compareGoldenJSON(t, "testdata/vs_frentz_doc.json", bts)
+}
+
+func TestParseGameDocument(t *testing.T) {
+	is := is.New(t)
+	gcg, err := ParseGCG(&DefaultConfig, "testdata/vs_frentz.gcg")
+	is.NoErr(err)
+	h, err := ParseGameDocument(&DefaultConfig, "testdata/vs_frentz_doc.json")
+	is.NoErr(err)
+
+	is.Equal(len(h.Players), 2)
+	is.Equal(h.Players[1].Nickname, "frentz")
+	is.Equal(h.PlayState, pb.PlayState_GAME_OVER)
+	is.Equal(h.FinalScores, gcg.FinalScores)
+	is.Equal(h.Winner, gcg.Winner)
+	is.Equal(len(h.Events), len(gcg.Events))
+	for i := range h.Events {
+		// GCGs don't say how many tiles came from the rack, as documents do.
+		gcg.Events[i].NumTilesFromRack = h.Events[i].NumTilesFromRack
+		is.True(proto.Equal(h.Events[i], gcg.Events[i]))
+	}
+	is.Equal(h.Events[0].NumTilesFromRack, uint32(7))
+}
+
+func TestParseGameDocumentSnakeCase(t *testing.T) {
+	is := is.New(t)
+	// This document is of a game in progress, with snake_case keys, enums
+	// as numbers, and no positions or friendly words.
+	gcg, err := ParseGCG(&DefaultConfig, "testdata/incomplete.gcg")
+	is.NoErr(err)
+	h, err := ParseGameDocument(&DefaultConfig, "testdata/incomplete_doc.json")
+	is.NoErr(err)
+
+	is.Equal(h.Uid, "kd8Qm2xHcTZbPq6gGhBvN4")
+	is.Equal(h.Lexicon, "NWL20")
+	is.Equal(h.Players[0].RealName, "Player 1")
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_DOUBLE)
+	is.Equal(h.PlayState, pb.PlayState_PLAYING)
+	is.Equal(h.LastKnownRacks, []string{"AAACEEE", ""})
+	is.Equal(len(h.Events), len(gcg.Events))
+	for i, evt := range h.Events {
+		is.Equal(evt.Type, gcg.Events[i].Type)
+		is.Equal(evt.Position, gcg.Events[i].Position)
+		is.Equal(evt.PlayedTiles, gcg.Events[i].PlayedTiles)
+		is.Equal(evt.Cumulative, gcg.Events[i].Cumulative)
+		is.True(evt.MillisRemaining > 0)
+	}
+	is.Equal(h.Events[1].WordsFormed, []string{"PEW", "UP", "ME"})
+
+	// And it is written back with its racks and timers.
+	bts, err := GameHistoryToGameDocument(&DefaultConfig, h)
+	is.NoErr(err)
+	again, err := ParseGameDocumentFromReader(&DefaultConfig, strings.NewReader(string(bts)))
+	is.NoErr(err)
+	is.True(proto.Equal(again, h))
+	is.True(strings.Contains(string(bts), `"timeRemaining": [
+      "1040000",
+      "1030000"
+    ]`))
+}
+
+func TestParseGameDocumentTimedOut(t *testing.T) {
+	is := is.New(t)
+	// The game in incomplete_doc.json, which Player_1 then lost on time.
+	h, err := ParseGameDocument(&DefaultConfig, "testdata/timed_out_doc.json")
+	is.NoErr(err)
+
+	is.Equal(h.PlayState, pb.PlayState_GAME_OVER)
+	is.Equal(h.EndReason, pb.GameEndReason_TIME)
+	is.Equal(h.Winner, int32(1))
+	is.Equal(h.FinalScores, []int32{336, 298})
+	is.True(proto.Equal(h.Timers, &pb.GameTimers{
+		IncrementMillis:   5000,
+		MaxOvertimeMillis: 60000,
+		MillisRemaining:   []int64{-60500, 1030000},
+	}))
+	tc := game.HistoryTimeControl(h)
+	is.Equal(tc.Increment, 5*time.Second)
+	is.Equal(tc.MaxOvertime, time.Minute)
+
+	// The time control and the end reason are written back.
+	bts, err := GameHistoryToGameDocument(&DefaultConfig, h)
+	is.NoErr(err)
+	again, err := ParseGameDocumentFromReader(&DefaultConfig, strings.NewReader(string(bts)))
+	is.NoErr(err)
+	is.True(proto.Equal(again, h))
+	is.True(strings.Contains(string(bts), `"endReason": "TIME"`))
+	is.True(strings.Contains(string(bts), `"incrementSeconds": 5`))
+	is.True(strings.Contains(string(bts), `"maxOvertime": 1`))
+}
+
+func TestEndReason(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCG(&DefaultConfig, "testdata/vs_frentz.gcg")
+	is.NoErr(err)
+	is.Equal(endReason(h), pb.GameEndReason_STANDARD)
+
+	// Without an END_RACK_PTS event, the players passed the game away.
+	evts := h.Events
+	h.Events = append(evts[:len(evts)-1:len(evts)-1], &pb.GameEvent{Type: pb.GameEvent_END_RACK_PENALTY})
+	is.Equal(endReason(h), pb.GameEndReason_CONSECUTIVE_ZEROES)
+
+	h.EndReason = pb.GameEndReason_RESIGNED
+	is.Equal(endReason(h), pb.GameEndReason_RESIGNED)
+}
+
+func TestParseGameDocumentErrors(t *testing.T) {
+	testcases := []struct {
+		name   string
+		change func(doc *gameDocument)
+		err    error
+		errMsg string
+	}{
+		{
+			name:   "no players",
+			change: func(doc *gameDocument) { doc.Players = nil },
+			err:    errNoPlayers,
+		},
+		{
+			name:   "bad tile",
+			change: func(doc *gameDocument) { doc.Events[3].Rack[0] = 40 },
+			err:    errBadDocumentTile,
+		},
+		{
+			name: "wrong score",
+			change: func(doc *gameDocument) {
+				doc.Events[len(doc.Events)-1].Cumulative += 10
+			},
+			errMsg: "after replaying the game",
+		},
+		{
+			name: "play off the board",
+			change: func(doc *gameDocument) {
+				doc.Events[2].Row = 14
+				doc.Events[2].Position = ""
+			},
+			errMsg: "replaying game",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			doc := &gameDocument{}
+			is.NoErr(json.Unmarshal([]byte(slurp("testdata/vs_frentz_doc.json")), doc))
+			tc.change(doc)
+			bts, err := json.Marshal(doc)
+			is.NoErr(err)
+			_, err = ParseGameDocumentFromReader(&DefaultConfig, strings.NewReader(string(bts)))
+			is.True(err != nil)
+			if tc.err != nil {
+				is.True(errors.Is(err, tc.err))
+			} else {
+				is.True(strings.Contains(err.Error(), tc.errMsg))
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ var (
 	errEncodingWrongPlace = errors.New("encoding line must be first line in file if present")
 	errPlayerNotSupported = errors.New("player number not supported")
 	errPlayerDoesNotExist = errors.New("player does not exist")
+	errNoEvents           = errors.New("gcg has no events")
 )
 
 // A Token is an event in a GCG file.
@@ -63,6 +64,8 @@ const (
 	ContinuationToken
 	IncompleteToken
 	TileDeclarationToken
+	Rack3Token
+	Rack4Token
 )
 
 type gcgdatum struct {
@@ -73,12 +76,14 @@ type gcgdatum struct {
 var GCGRegexes []gcgdatum
 
 const (
-	PlayerRegex               = `#player(?P<p_number>[1-2])\s+(?P<nick>\S+)\s+(?P<real_name>.+)`
+	PlayerRegex               = `#player(?P<p_number>[1-4])\s+(?P<nick>\S+)\s+(?P<real_name>.+)`
 	TitleRegex                = `#title\s*(?P<title>.*)`
 	DescriptionRegex          = `#description\s*(?P<description>.*)`
 	IDRegex                   = `#id\s*(?P<id_authority>\S+)\s+(?P<id>\S+)`
 	Rack1Regex                = `#rack1 (?P<rack>\S+)`
 	Rack2Regex                = `#rack2 (?P<rack>\S+)`
+	Rack3Regex                = `#rack3 (?P<rack>\S+)`
+	Rack4Regex                = `#rack4 (?P<rack>\S+)`
 	MoveRegex                 = `>(?P<nick>\S+):\s+(?P<rack>\S+)\s+(?P<pos>\w+)\s+(?P<play>\S+)\s+\+(?P<score>\d+)\s+(?P<cumul>\d+)`
 	NoteRegex                 = `#note (?P<note>.+)`
 	LexiconRegex              = `#lexicon (?P<lexicon>.+)`
@@ -107,6 +112,16 @@ type parser struct {
 
 	history *pb.GameHistory
 	game    *game.Game
+
+	// lenient parsers skip the lines they can't use and repair scores,
+	// recording a diagnostic for each, instead of stopping at the first
+	// error.
+	lenient     bool
+	diagnostics []Diagnostic
+	// line is the number of the line being parsed, from 1, and
+	// eventLines is the line of each event in the history.
+	line       int
+	eventLines []int
 }
 
 // init initializes the regexp list.
@@ -125,6 +140,8 @@ func init() {
 		{IDToken, regexp.MustCompile(IDRegex)},
 		{Rack1Token, regexp.MustCompile(Rack1Regex)},
 		{Rack2Token, regexp.MustCompile(Rack2Regex)},
+		{Rack3Token, regexp.MustCompile(Rack3Regex)},
+		{Rack4Token, regexp.MustCompile(Rack4Regex)},
 		{EncodingToken, compiledEncodingRegexp},
 		{MoveToken, regexp.MustCompile(MoveRegex)},
 		{NoteToken, regexp.MustCompile(NoteRegex)},
@@ -164,12 +181,21 @@ func nickToPIndex(nick string, players []*pb.PlayerInfo) (uint32, error) {
 	return 0, errPlayerDoesNotExist
 }
 
+func isEventToken(token Token) bool {
+	switch token {
+	case MoveToken, PhonyTilesReturnedToken, PassToken, ChallengeBonusToken,
+		ExchangeToken, EndRackPointsToken, TimePenaltyToken, LastRackPenaltyToken:
+		return true
+	}
+	return false
+}
+
 func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []string) error {
 	var err error
 
-	if token == MoveToken || token == PassToken || token == ExchangeToken {
+	if isEventToken(token) {
 		// Start the game if we haven't already.
-		if len(p.history.Players) != 2 {
+		if len(p.history.Players) < 2 || len(p.history.Players) > game.MaxPlayers {
 			return errors.New("wrong number of players defined")
 		}
 		if p.game == nil {
@@ -183,7 +209,7 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 				Str("lexicon", p.history.Lexicon).
 				Str("variant", string(variant)).Msg("creating game")
 
-			// We have both players. Initialize a new game.
+			// We have all players. Initialize a new game.
 			// Don't pass in lexicon to new basic game rules. We don't want GCG
 			// parsing to have to load in an actual lexicon to verify any plays.
 			rules, err := game.NewBasicGameRules(
@@ -214,11 +240,11 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 		if err != nil {
 			return err
 		}
-		if pn != 1 && pn != 2 {
+		if pn < 1 || pn > game.MaxPlayers {
 			return errPlayerNotSupported
 		}
-		if pn == 2 {
-			if match[2] == p.history.Players[0].Nickname {
+		for _, player := range p.history.Players {
+			if match[2] == player.Nickname {
 				return errDuplicateNames
 			}
 		}
@@ -246,18 +272,19 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 		}
 		p.history.IdAuth = match[1]
 		p.history.Uid = match[2]
-	case Rack1Token:
-		// assume if there is a rack2 token, that rack1 will come before it.
-		if p.history.LastKnownRacks == nil {
-			p.history.LastKnownRacks = []string{match[1], ""}
-		}
-	case Rack2Token:
-		if p.history.LastKnownRacks == nil {
-			p.history.LastKnownRacks = []string{"", match[1]}
-		} else {
-			// There is already a rack1 at the [0] position.
-			p.history.LastKnownRacks[1] = match[1]
+	case Rack1Token, Rack2Token, Rack3Token, Rack4Token:
+		pidx := int(token - Rack1Token)
+		if token >= Rack3Token {
+			pidx = int(token-Rack3Token) + 2
+		}
+		nracks := len(p.history.Players)
+		if pidx >= nracks {
+			nracks = pidx + 1
 		}
+		for len(p.history.LastKnownRacks) < nracks {
+			p.history.LastKnownRacks = append(p.history.LastKnownRacks, "")
+		}
+		p.history.LastKnownRacks[pidx] = match[1]
 	case EncodingToken:
 		return errEncodingWrongPlace
 	case MoveToken:
@@ -293,7 +320,10 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 			}
 		}
 
-		evt.IsBingo = tp == game.RackTileLimit
+		evt.IsBingo = tp == p.game.RackSize()
+		if p.lenient {
+			p.repairScore(evt)
+		}
 		p.history.Events = append(p.history.Events, evt)
 		// Try playing the move
 		log.Debug().Msg("PLAYING LATEST EVENT for MoveToken")
@@ -304,6 +334,7 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 		lastEvtIdx := len(p.history.Events) - 1
 		if lastEvtIdx < 0 {
 			log.Warn().Msg("note pragma may not precede events")
+			p.warn("skipped a note before the first event")
 		} else {
 			p.history.Events[lastEvtIdx].Note += match[1]
 		}
@@ -496,9 +527,27 @@ func (p *parser) parseLine(cfg *config.Config, line string) error {
 		match := datum.regex.FindStringSubmatch(line)
 		if match != nil {
 			foundMatch = true
+			nevts := len(p.history.Events)
 			err := p.addEventOrPragma(cfg, datum.token, match)
 			if err != nil {
-				return err
+				// Without a game, there is nothing to play the events on.
+				if !p.lenient || (isEventToken(datum.token) && p.game == nil) {
+					return err
+				}
+				p.history.Events = p.history.Events[:nevts]
+				if isEventToken(datum.token) {
+					p.error(fmt.Sprintf("skipped an event that could not be played: %v", err))
+				} else {
+					p.warn(fmt.Sprintf("skipped a pragma: %v", err))
+				}
+				p.lastToken = UndefinedToken
+				return nil
+			}
+			for len(p.eventLines) < len(p.history.Events) {
+				p.eventLines = append(p.eventLines, p.line)
+			}
+			if p.lenient && len(p.history.Events) > nevts {
+				p.repairCumulative(p.history.Events[len(p.history.Events)-1])
 			}
 			p.lastToken = datum.token
 			break
@@ -506,7 +555,7 @@ func (p *parser) parseLine(cfg *config.Config, line string) error {
 	}
 	if !foundMatch {
 		// maybe it's a multi-line note.
-		if p.lastToken == NoteToken {
+		if p.lastToken == NoteToken && len(p.history.Events) > 0 {
 			lastEventIdx := len(p.history.Events) - 1
 			p.history.Events[lastEventIdx].Note += ("\n" + line)
 			return nil
@@ -515,6 +564,15 @@ func (p *parser) parseLine(cfg *config.Config, line string) error {
 		if strings.TrimSpace(line) == "" {
 			return nil
 		}
+		if p.lenient {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				p.warn("skipped an unknown pragma")
+			} else {
+				p.warn("skipped a line that is not a pragma or an event")
+			}
+			p.lastToken = UndefinedToken
+			return nil
+		}
 		return fmt.Errorf("no match found for line '%v'", line)
 	}
 	return nil
@@ -560,6 +618,14 @@ func encodingOrFirstLine(reader io.Reader) (string, string, error) {
 }
 
 func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory, error) {
+	p, err := parse(cfg, reader, false)
+	if err != nil {
+		return nil, err
+	}
+	return p.history, nil
+}
+
+func parse(cfg *config.Config, reader io.Reader, lenient bool) (*parser, error) {
 	var err error
 	parser := &parser{
 		history: &pb.GameHistory{
@@ -569,6 +635,7 @@ func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory,
 			// check the validity of every play.
 			ChallengeRule: pb.ChallengeRule_SINGLE,
 			Version:       game.CurrentGameHistoryVersion},
+		lenient: lenient,
 	}
 	originalGCG := ""
 
@@ -587,6 +654,7 @@ func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory,
 	} else {
 		scanner = bufio.NewScanner(reader)
 	}
+	parser.line = 1
 	if firstLine != "" {
 		err = parser.parseLine(cfg, firstLine)
 		if err != nil {
@@ -597,12 +665,16 @@ func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory,
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		parser.line++
 		err = parser.parseLine(cfg, line)
 		if err != nil {
 			return nil, err
 		}
 		originalGCG += line + "\n"
 	}
+	if parser.game == nil {
+		return nil, errNoEvents
+	}
 	parser.history.OriginalGcg = strings.TrimSpace(originalGCG)
 
 	// Determine if the game ended.
@@ -612,7 +684,7 @@ func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory,
 	}
 	// Set challenge rule back to void since we don't know or care what it is.
 	parser.history.ChallengeRule = pb.ChallengeRule_VOID
-	return parser.history, nil
+	return parser, nil
 }
 
 // ParseGCG parses a GCG file into a GameHistory.
@@ -735,8 +807,9 @@ func writePlayer(s *strings.Builder, pn int, p *pb.PlayerInfo) {
 }
 
 func writePlayers(s *strings.Builder, players []*pb.PlayerInfo) {
-	writePlayer(s, 1, players[0])
-	writePlayer(s, 2, players[1])
+	for i, p := range players {
+		writePlayer(s, i+1, p)
+	}
 }
 
 func isPassBeforeEndRackPoints(h *pb.GameHistory, i int) bool {
@@ -273,3 +273,35 @@ func TestIsBingo(t *testing.T) {
 	assert.True(t, history.Events[0].IsBingo)
 	assert.False(t, history.Events[1].IsBingo)
 }
+
+func TestThreePlayerGame(t *testing.T) {
+	is := is.New(t)
+	history, err := ParseGCG(&DefaultConfig, "./testdata/three_players.gcg")
+	is.NoErr(err)
+	is.Equal(len(history.Players), 3)
+	is.Equal(len(history.Events), 15)
+	is.Equal(history.Events[2].PlayerIndex, uint32(2))
+	is.Equal(history.Events[3].PlayerIndex, uint32(0))
+	is.Equal(history.FinalScores, []int32{4, 10, -1})
+	is.Equal(history.Winner, int32(1))
+
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, "")
+	is.NoErr(err)
+	g, err := game.NewFromHistory(history, rules, 0)
+	is.NoErr(err)
+	is.NoErr(g.PlayToTurn(len(history.Events)))
+	is.Equal(g.PointsFor(0), 4)
+	is.Equal(g.PointsFor(1), 10)
+	is.Equal(g.PointsFor(2), -1)
+
+	gcgstr, err := GameHistoryToGCG(history, false)
+	is.NoErr(err)
+	// ignore encoding line:
+	linesNew := strings.Split(gcgstr, "\n")[1:]
+	linesOld := strings.Split(slurp("./testdata/three_players.gcg"), "\n")
+	is.Equal(len(linesNew), len(linesOld))
+	for idx, ln := range linesNew {
+		is.Equal(strings.Fields(ln), strings.Fields(linesOld[idx]))
+	}
+}
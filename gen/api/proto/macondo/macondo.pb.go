@@ -20,6 +20,77 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// The values match the ones in game documents.
+type GameEndReason int32
+
+const (
+	// The game isn't over, or it isn't known why it ended.
+	GameEndReason_NONE GameEndReason = 0
+	// A player went too far over time.
+	GameEndReason_TIME GameEndReason = 1
+	// A player went out.
+	GameEndReason_STANDARD           GameEndReason = 2
+	GameEndReason_CONSECUTIVE_ZEROES GameEndReason = 3
+	GameEndReason_RESIGNED           GameEndReason = 4
+	GameEndReason_ABORTED            GameEndReason = 5
+	GameEndReason_TRIPLE_CHALLENGE   GameEndReason = 6
+	GameEndReason_CANCELLED          GameEndReason = 7
+	GameEndReason_FORCE_FORFEIT      GameEndReason = 8
+)
+
+// Enum value maps for GameEndReason.
+var (
+	GameEndReason_name = map[int32]string{
+		0: "NONE",
+		1: "TIME",
+		2: "STANDARD",
+		3: "CONSECUTIVE_ZEROES",
+		4: "RESIGNED",
+		5: "ABORTED",
+		6: "TRIPLE_CHALLENGE",
+		7: "CANCELLED",
+		8: "FORCE_FORFEIT",
+	}
+	GameEndReason_value = map[string]int32{
+		"NONE":               0,
+		"TIME":               1,
+		"STANDARD":           2,
+		"CONSECUTIVE_ZEROES": 3,
+		"RESIGNED":           4,
+		"ABORTED":            5,
+		"TRIPLE_CHALLENGE":   6,
+		"CANCELLED":          7,
+		"FORCE_FORFEIT":      8,
+	}
+)
+
+func (x GameEndReason) Enum() *GameEndReason {
+	p := new(GameEndReason)
+	*p = x
+	return p
+}
+
+func (x GameEndReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GameEndReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_macondo_macondo_proto_enumTypes[0].Descriptor()
+}
+
+func (GameEndReason) Type() protoreflect.EnumType {
+	return &file_api_proto_macondo_macondo_proto_enumTypes[0]
+}
+
+func (x GameEndReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GameEndReason.Descriptor instead.
+func (GameEndReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{0}
+}
+
 type PlayState int32
 
 const (
@@ -53,11 +124,11 @@ func (x PlayState) String() string {
 }
 
 func (PlayState) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_macondo_macondo_proto_enumTypes[0].Descriptor()
+	return file_api_proto_macondo_macondo_proto_enumTypes[1].Descriptor()
 }
 
 func (PlayState) Type() protoreflect.EnumType {
-	return &file_api_proto_macondo_macondo_proto_enumTypes[0]
+	return &file_api_proto_macondo_macondo_proto_enumTypes[1]
 }
 
 func (x PlayState) Number() protoreflect.EnumNumber {
@@ -66,7 +137,7 @@ func (x PlayState) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PlayState.Descriptor instead.
 func (PlayState) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{1}
 }
 
 type ChallengeRule int32
@@ -111,11 +182,11 @@ func (x ChallengeRule) String() string {
 }
 
 func (ChallengeRule) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_macondo_macondo_proto_enumTypes[1].Descriptor()
+	return file_api_proto_macondo_macondo_proto_enumTypes[2].Descriptor()
 }
 
 func (ChallengeRule) Type() protoreflect.EnumType {
-	return &file_api_proto_macondo_macondo_proto_enumTypes[1]
+	return &file_api_proto_macondo_macondo_proto_enumTypes[2]
 }
 
 func (x ChallengeRule) Number() protoreflect.EnumNumber {
@@ -124,7 +195,7 @@ func (x ChallengeRule) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ChallengeRule.Descriptor instead.
 func (ChallengeRule) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{2}
 }
 
 type PuzzleTag int32
@@ -138,29 +209,54 @@ const (
 	PuzzleTag_POWER_TILE          PuzzleTag = 5
 	PuzzleTag_BINGO_NINE_OR_ABOVE PuzzleTag = 6
 	PuzzleTag_CEL_ONLY            PuzzleTag = 7
+	// Endgame puzzles, from positions with an empty bag. Every endgame puzzle
+	// is an ENDGAME_WIN: its answer wins the game, and no other move wins by
+	// nearly as much.
+	PuzzleTag_ENDGAME_WIN PuzzleTag = 8
+	// No other move wins at all.
+	PuzzleTag_ENDGAME_ONLY_WINNING_MOVE PuzzleTag = 9
+	// The answer doesn't go out, but the player goes out with their next
+	// move.
+	PuzzleTag_OUT_IN_TWO PuzzleTag = 10
+	// The answer scores less than another move, to set up a bigger play for
+	// the player's next move.
+	PuzzleTag_SETUP PuzzleTag = 11
+	// A puzzle from a position with tiles in the bag, but fewer than a full
+	// rack. Its answer won a sim, as the draws can't be solved for.
+	PuzzleTag_PRE_ENDGAME PuzzleTag = 12
 )
 
 // Enum value maps for PuzzleTag.
 var (
 	PuzzleTag_name = map[int32]string{
-		0: "EQUITY",
-		1: "BINGO",
-		2: "ONLY_BINGO",
-		3: "BLANK_BINGO",
-		4: "NON_BINGO",
-		5: "POWER_TILE",
-		6: "BINGO_NINE_OR_ABOVE",
-		7: "CEL_ONLY",
+		0:  "EQUITY",
+		1:  "BINGO",
+		2:  "ONLY_BINGO",
+		3:  "BLANK_BINGO",
+		4:  "NON_BINGO",
+		5:  "POWER_TILE",
+		6:  "BINGO_NINE_OR_ABOVE",
+		7:  "CEL_ONLY",
+		8:  "ENDGAME_WIN",
+		9:  "ENDGAME_ONLY_WINNING_MOVE",
+		10: "OUT_IN_TWO",
+		11: "SETUP",
+		12: "PRE_ENDGAME",
 	}
 	PuzzleTag_value = map[string]int32{
-		"EQUITY":              0,
-		"BINGO":               1,
-		"ONLY_BINGO":          2,
-		"BLANK_BINGO":         3,
-		"NON_BINGO":           4,
-		"POWER_TILE":          5,
-		"BINGO_NINE_OR_ABOVE": 6,
-		"CEL_ONLY":            7,
+		"EQUITY":                    0,
+		"BINGO":                     1,
+		"ONLY_BINGO":                2,
+		"BLANK_BINGO":               3,
+		"NON_BINGO":                 4,
+		"POWER_TILE":                5,
+		"BINGO_NINE_OR_ABOVE":       6,
+		"CEL_ONLY":                  7,
+		"ENDGAME_WIN":               8,
+		"ENDGAME_ONLY_WINNING_MOVE": 9,
+		"OUT_IN_TWO":                10,
+		"SETUP":                     11,
+		"PRE_ENDGAME":               12,
 	}
 )
 
@@ -175,11 +271,11 @@ func (x PuzzleTag) String() string {
 }
 
 func (PuzzleTag) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_macondo_macondo_proto_enumTypes[2].Descriptor()
+	return file_api_proto_macondo_macondo_proto_enumTypes[3].Descriptor()
 }
 
 func (PuzzleTag) Type() protoreflect.EnumType {
-	return &file_api_proto_macondo_macondo_proto_enumTypes[2]
+	return &file_api_proto_macondo_macondo_proto_enumTypes[3]
 }
 
 func (x PuzzleTag) Number() protoreflect.EnumNumber {
@@ -188,7 +284,7 @@ func (x PuzzleTag) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PuzzleTag.Descriptor instead.
 func (PuzzleTag) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{2}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{3}
 }
 
 type GameEvent_Type int32
@@ -249,11 +345,11 @@ func (x GameEvent_Type) String() string {
 }
 
 func (GameEvent_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_macondo_macondo_proto_enumTypes[3].Descriptor()
+	return file_api_proto_macondo_macondo_proto_enumTypes[4].Descriptor()
 }
 
 func (GameEvent_Type) Type() protoreflect.EnumType {
-	return &file_api_proto_macondo_macondo_proto_enumTypes[3]
+	return &file_api_proto_macondo_macondo_proto_enumTypes[4]
 }
 
 func (x GameEvent_Type) Number() protoreflect.EnumNumber {
@@ -262,7 +358,7 @@ func (x GameEvent_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use GameEvent_Type.Descriptor instead.
 func (GameEvent_Type) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{1, 0}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{2, 0}
 }
 
 type GameEvent_Direction int32
@@ -295,11 +391,11 @@ func (x GameEvent_Direction) String() string {
 }
 
 func (GameEvent_Direction) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_macondo_macondo_proto_enumTypes[4].Descriptor()
+	return file_api_proto_macondo_macondo_proto_enumTypes[5].Descriptor()
 }
 
 func (GameEvent_Direction) Type() protoreflect.EnumType {
-	return &file_api_proto_macondo_macondo_proto_enumTypes[4]
+	return &file_api_proto_macondo_macondo_proto_enumTypes[5]
 }
 
 func (x GameEvent_Direction) Number() protoreflect.EnumNumber {
@@ -308,7 +404,7 @@ func (x GameEvent_Direction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use GameEvent_Direction.Descriptor instead.
 func (GameEvent_Direction) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{1, 1}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{2, 1}
 }
 
 type BotRequest_BotCode int32
@@ -381,11 +477,11 @@ func (x BotRequest_BotCode) String() string {
 }
 
 func (BotRequest_BotCode) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_macondo_macondo_proto_enumTypes[5].Descriptor()
+	return file_api_proto_macondo_macondo_proto_enumTypes[6].Descriptor()
 }
 
 func (BotRequest_BotCode) Type() protoreflect.EnumType {
-	return &file_api_proto_macondo_macondo_proto_enumTypes[5]
+	return &file_api_proto_macondo_macondo_proto_enumTypes[6]
 }
 
 func (x BotRequest_BotCode) Number() protoreflect.EnumNumber {
@@ -394,7 +490,56 @@ func (x BotRequest_BotCode) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use BotRequest_BotCode.Descriptor instead.
 func (BotRequest_BotCode) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{3, 0}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type EvaluationRequest_EvaluationType int32
+
+const (
+	// Compare each move to the other moves by static equity.
+	EvaluationRequest_STATIC EvaluationRequest_EvaluationType = 0
+	// Sim each move against the best moves by static equity, or solve the
+	// endgame exactly once the bag is empty.
+	EvaluationRequest_SIM EvaluationRequest_EvaluationType = 1
+)
+
+// Enum value maps for EvaluationRequest_EvaluationType.
+var (
+	EvaluationRequest_EvaluationType_name = map[int32]string{
+		0: "STATIC",
+		1: "SIM",
+	}
+	EvaluationRequest_EvaluationType_value = map[string]int32{
+		"STATIC": 0,
+		"SIM":    1,
+	}
+)
+
+func (x EvaluationRequest_EvaluationType) Enum() *EvaluationRequest_EvaluationType {
+	p := new(EvaluationRequest_EvaluationType)
+	*p = x
+	return p
+}
+
+func (x EvaluationRequest_EvaluationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EvaluationRequest_EvaluationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_macondo_macondo_proto_enumTypes[7].Descriptor()
+}
+
+func (EvaluationRequest_EvaluationType) Type() protoreflect.EnumType {
+	return &file_api_proto_macondo_macondo_proto_enumTypes[7]
+}
+
+func (x EvaluationRequest_EvaluationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EvaluationRequest_EvaluationType.Descriptor instead.
+func (EvaluationRequest_EvaluationType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{5, 0}
 }
 
 // GameHistory encodes a whole history of a game, and it should also encode
@@ -442,6 +587,10 @@ type GameHistory struct {
 	LetterDistribution string `protobuf:"bytes,18,opt,name=letter_distribution,json=letterDistribution,proto3" json:"letter_distribution,omitempty"`
 	// If provided, the starting CGP is a crossword-game position string.
 	StartingCgp string `protobuf:"bytes,19,opt,name=starting_cgp,json=startingCgp,proto3" json:"starting_cgp,omitempty"`
+	// The clock of a timed game. It is not set for untimed games.
+	Timers *GameTimers `protobuf:"bytes,20,opt,name=timers,proto3" json:"timers,omitempty"`
+	// Why the game ended, if it is over.
+	EndReason GameEndReason `protobuf:"varint,21,opt,name=end_reason,json=endReason,proto3,enum=macondo.GameEndReason" json:"end_reason,omitempty"`
 }
 
 func (x *GameHistory) Reset() {
@@ -610,6 +759,98 @@ func (x *GameHistory) GetStartingCgp() string {
 	return ""
 }
 
+func (x *GameHistory) GetTimers() *GameTimers {
+	if x != nil {
+		return x.Timers
+	}
+	return nil
+}
+
+func (x *GameHistory) GetEndReason() GameEndReason {
+	if x != nil {
+		return x.EndReason
+	}
+	return GameEndReason_NONE
+}
+
+type GameTimers struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// How much time every player starts with. It is 0 if it isn't known, as
+	// in games imported from game documents.
+	InitialMillis int64 `protobuf:"varint,1,opt,name=initial_millis,json=initialMillis,proto3" json:"initial_millis,omitempty"`
+	// How much time is added to a player's clock after every turn.
+	IncrementMillis int64 `protobuf:"varint,2,opt,name=increment_millis,json=incrementMillis,proto3" json:"increment_millis,omitempty"`
+	// How far over time a player can go before losing on time. 0 means
+	// there is no limit.
+	MaxOvertimeMillis int64 `protobuf:"varint,3,opt,name=max_overtime_millis,json=maxOvertimeMillis,proto3" json:"max_overtime_millis,omitempty"`
+	// The time left on every player's clock, in the order of the players,
+	// when the game was last saved.
+	MillisRemaining []int64 `protobuf:"varint,4,rep,packed,name=millis_remaining,json=millisRemaining,proto3" json:"millis_remaining,omitempty"`
+}
+
+func (x *GameTimers) Reset() {
+	*x = GameTimers{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameTimers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameTimers) ProtoMessage() {}
+
+func (x *GameTimers) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameTimers.ProtoReflect.Descriptor instead.
+func (*GameTimers) Descriptor() ([]byte, []int) {
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GameTimers) GetInitialMillis() int64 {
+	if x != nil {
+		return x.InitialMillis
+	}
+	return 0
+}
+
+func (x *GameTimers) GetIncrementMillis() int64 {
+	if x != nil {
+		return x.IncrementMillis
+	}
+	return 0
+}
+
+func (x *GameTimers) GetMaxOvertimeMillis() int64 {
+	if x != nil {
+		return x.MaxOvertimeMillis
+	}
+	return 0
+}
+
+func (x *GameTimers) GetMillisRemaining() []int64 {
+	if x != nil {
+		return x.MillisRemaining
+	}
+	return nil
+}
+
 // This should be merged into Move.
 type GameEvent struct {
 	state         protoimpl.MessageState
@@ -650,7 +891,7 @@ type GameEvent struct {
 func (x *GameEvent) Reset() {
 	*x = GameEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[1]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -663,7 +904,7 @@ func (x *GameEvent) String() string {
 func (*GameEvent) ProtoMessage() {}
 
 func (x *GameEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[1]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -676,7 +917,7 @@ func (x *GameEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameEvent.ProtoReflect.Descriptor instead.
 func (*GameEvent) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{2}
 }
 
 // Deprecated: Marked as deprecated in api/proto/macondo/macondo.proto.
@@ -835,7 +1076,7 @@ type PlayerInfo struct {
 func (x *PlayerInfo) Reset() {
 	*x = PlayerInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[2]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -848,7 +1089,7 @@ func (x *PlayerInfo) String() string {
 func (*PlayerInfo) ProtoMessage() {}
 
 func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[2]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -861,7 +1102,7 @@ func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PlayerInfo.ProtoReflect.Descriptor instead.
 func (*PlayerInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{2}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PlayerInfo) GetNickname() string {
@@ -893,12 +1134,22 @@ type BotRequest struct {
 	GameHistory       *GameHistory       `protobuf:"bytes,1,opt,name=game_history,json=gameHistory,proto3" json:"game_history,omitempty"`
 	EvaluationRequest *EvaluationRequest `protobuf:"bytes,2,opt,name=evaluation_request,json=evaluationRequest,proto3" json:"evaluation_request,omitempty"`
 	BotType           BotRequest_BotCode `protobuf:"varint,3,opt,name=bot_type,json=botType,proto3,enum=macondo.BotRequest_BotCode" json:"bot_type,omitempty"`
+	// How long the bot may take to answer, in milliseconds. When it runs out
+	// the bot answers with the best move it found so far. Zero means no limit
+	// other than the one that comes from the clock.
+	MillisBudget int32 `protobuf:"varint,4,opt,name=millis_budget,json=millisBudget,proto3" json:"millis_budget,omitempty"`
+	// The time left on the bot's clock and the increment it gets after every
+	// turn, in milliseconds. The bot spreads the time left over the rest of
+	// the game, and never goes over millis_budget. If millis_remaining is
+	// zero, the bot's clock is taken from the game history instead.
+	MillisRemaining int32 `protobuf:"varint,5,opt,name=millis_remaining,json=millisRemaining,proto3" json:"millis_remaining,omitempty"`
+	IncrementMillis int32 `protobuf:"varint,6,opt,name=increment_millis,json=incrementMillis,proto3" json:"increment_millis,omitempty"`
 }
 
 func (x *BotRequest) Reset() {
 	*x = BotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[3]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -911,7 +1162,7 @@ func (x *BotRequest) String() string {
 func (*BotRequest) ProtoMessage() {}
 
 func (x *BotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[3]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -924,7 +1175,7 @@ func (x *BotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BotRequest.ProtoReflect.Descriptor instead.
 func (*BotRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{3}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *BotRequest) GetGameHistory() *GameHistory {
@@ -948,19 +1199,49 @@ func (x *BotRequest) GetBotType() BotRequest_BotCode {
 	return BotRequest_HASTY_BOT
 }
 
+func (x *BotRequest) GetMillisBudget() int32 {
+	if x != nil {
+		return x.MillisBudget
+	}
+	return 0
+}
+
+func (x *BotRequest) GetMillisRemaining() int32 {
+	if x != nil {
+		return x.MillisRemaining
+	}
+	return 0
+}
+
+func (x *BotRequest) GetIncrementMillis() int32 {
+	if x != nil {
+		return x.IncrementMillis
+	}
+	return 0
+}
+
 type EvaluationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// Evaluate for this user
-	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"` // Later: evaluation type (static/sim/etc)
+	User string                           `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Type EvaluationRequest_EvaluationType `protobuf:"varint,2,opt,name=type,proto3,enum=macondo.EvaluationRequest_EvaluationType" json:"type,omitempty"`
+	// Options for sim evaluations. Zero values mean defaults.
+	SimPlies int32 `protobuf:"varint,3,opt,name=sim_plies,json=simPlies,proto3" json:"sim_plies,omitempty"`
+	// The number of moves, by static equity, to sim the played move against.
+	SimCandidates int32 `protobuf:"varint,4,opt,name=sim_candidates,json=simCandidates,proto3" json:"sim_candidates,omitempty"`
+	SimIterations int32 `protobuf:"varint,5,opt,name=sim_iterations,json=simIterations,proto3" json:"sim_iterations,omitempty"`
+	// The chance, from 0 to 1, that the opponent challenges a phony. Zero
+	// means a default that depends on the challenge rule.
+	PhonyChallengeChance float64 `protobuf:"fixed64,6,opt,name=phony_challenge_chance,json=phonyChallengeChance,proto3" json:"phony_challenge_chance,omitempty"`
 }
 
 func (x *EvaluationRequest) Reset() {
 	*x = EvaluationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[4]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -973,7 +1254,7 @@ func (x *EvaluationRequest) String() string {
 func (*EvaluationRequest) ProtoMessage() {}
 
 func (x *EvaluationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[4]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -986,7 +1267,7 @@ func (x *EvaluationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EvaluationRequest.ProtoReflect.Descriptor instead.
 func (*EvaluationRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{4}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *EvaluationRequest) GetUser() string {
@@ -996,18 +1277,56 @@ func (x *EvaluationRequest) GetUser() string {
 	return ""
 }
 
+func (x *EvaluationRequest) GetType() EvaluationRequest_EvaluationType {
+	if x != nil {
+		return x.Type
+	}
+	return EvaluationRequest_STATIC
+}
+
+func (x *EvaluationRequest) GetSimPlies() int32 {
+	if x != nil {
+		return x.SimPlies
+	}
+	return 0
+}
+
+func (x *EvaluationRequest) GetSimCandidates() int32 {
+	if x != nil {
+		return x.SimCandidates
+	}
+	return 0
+}
+
+func (x *EvaluationRequest) GetSimIterations() int32 {
+	if x != nil {
+		return x.SimIterations
+	}
+	return 0
+}
+
+func (x *EvaluationRequest) GetPhonyChallengeChance() float64 {
+	if x != nil {
+		return x.PhonyChallengeChance
+	}
+	return 0
+}
+
 type Evaluation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	PlayEval []*SingleEvaluation `protobuf:"bytes,1,rep,name=play_eval,json=playEval,proto3" json:"play_eval,omitempty"`
+	// Every opponent play that the user challenged or should have
+	// challenged.
+	ChallengeEval []*ChallengeEvaluation `protobuf:"bytes,2,rep,name=challenge_eval,json=challengeEval,proto3" json:"challenge_eval,omitempty"`
 }
 
 func (x *Evaluation) Reset() {
 	*x = Evaluation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[5]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1020,7 +1339,7 @@ func (x *Evaluation) String() string {
 func (*Evaluation) ProtoMessage() {}
 
 func (x *Evaluation) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[5]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1033,7 +1352,7 @@ func (x *Evaluation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Evaluation.ProtoReflect.Descriptor instead.
 func (*Evaluation) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Evaluation) GetPlayEval() []*SingleEvaluation {
@@ -1043,6 +1362,13 @@ func (x *Evaluation) GetPlayEval() []*SingleEvaluation {
 	return nil
 }
 
+func (x *Evaluation) GetChallengeEval() []*ChallengeEvaluation {
+	if x != nil {
+		return x.ChallengeEval
+	}
+	return nil
+}
+
 type SingleEvaluation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1054,12 +1380,29 @@ type SingleEvaluation struct {
 	PossibleStarPlay bool    `protobuf:"varint,4,opt,name=possible_star_play,json=possibleStarPlay,proto3" json:"possible_star_play,omitempty"`
 	MissedStarPlay   bool    `protobuf:"varint,5,opt,name=missed_star_play,json=missedStarPlay,proto3" json:"missed_star_play,omitempty"`
 	TopIsBingo       bool    `protobuf:"varint,6,opt,name=top_is_bingo,json=topIsBingo,proto3" json:"top_is_bingo,omitempty"`
+	// The best move according to the evaluation, in GCG notation.
+	TopMove string `protobuf:"bytes,7,opt,name=top_move,json=topMove,proto3" json:"top_move,omitempty"`
+	// Set by sim evaluations. Win percentages go from 0 to 100, and
+	// win_pct_loss is the difference between them.
+	PlayedWinPct  float64 `protobuf:"fixed64,8,opt,name=played_win_pct,json=playedWinPct,proto3" json:"played_win_pct,omitempty"`
+	TopWinPct     float64 `protobuf:"fixed64,9,opt,name=top_win_pct,json=topWinPct,proto3" json:"top_win_pct,omitempty"`
+	SimIterations int32   `protobuf:"varint,10,opt,name=sim_iterations,json=simIterations,proto3" json:"sim_iterations,omitempty"`
+	// Set by sim evaluations when the bag was empty and the endgame was
+	// solved exactly instead. The win percentages are then 0, 50 or 100.
+	EndgameSolved bool `protobuf:"varint,11,opt,name=endgame_solved,json=endgameSolved,proto3" json:"endgame_solved,omitempty"`
+	// The spread lost compared to the best endgame sequence.
+	EndgameSpreadLoss int32 `protobuf:"varint,12,opt,name=endgame_spread_loss,json=endgameSpreadLoss,proto3" json:"endgame_spread_loss,omitempty"`
+	// The move formed a word not in the lexicon. Its equity is the average of
+	// its equity if it stands and the equity of losing the turn, weighted by
+	// the chance of it being challenged.
+	Phony                bool    `protobuf:"varint,13,opt,name=phony,proto3" json:"phony,omitempty"`
+	PhonyChallengeChance float64 `protobuf:"fixed64,14,opt,name=phony_challenge_chance,json=phonyChallengeChance,proto3" json:"phony_challenge_chance,omitempty"`
 }
 
 func (x *SingleEvaluation) Reset() {
 	*x = SingleEvaluation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[6]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1072,7 +1415,7 @@ func (x *SingleEvaluation) String() string {
 func (*SingleEvaluation) ProtoMessage() {}
 
 func (x *SingleEvaluation) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[6]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1085,7 +1428,7 @@ func (x *SingleEvaluation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SingleEvaluation.ProtoReflect.Descriptor instead.
 func (*SingleEvaluation) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SingleEvaluation) GetEquityLoss() float64 {
@@ -1130,37 +1473,193 @@ func (x *SingleEvaluation) GetTopIsBingo() bool {
 	return false
 }
 
-type BotResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Types that are assignable to Response:
-	//
-	//	*BotResponse_Move
-	//	*BotResponse_Error
-	Response isBotResponse_Response `protobuf_oneof:"response"`
-	Eval     *Evaluation            `protobuf:"bytes,3,opt,name=eval,proto3" json:"eval,omitempty"`
-	GameId   string                 `protobuf:"bytes,4,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+func (x *SingleEvaluation) GetTopMove() string {
+	if x != nil {
+		return x.TopMove
+	}
+	return ""
 }
 
-func (x *BotResponse) Reset() {
-	*x = BotResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *SingleEvaluation) GetPlayedWinPct() float64 {
+	if x != nil {
+		return x.PlayedWinPct
 	}
+	return 0
 }
 
-func (x *BotResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SingleEvaluation) GetTopWinPct() float64 {
+	if x != nil {
+		return x.TopWinPct
+	}
+	return 0
 }
 
-func (*BotResponse) ProtoMessage() {}
+func (x *SingleEvaluation) GetSimIterations() int32 {
+	if x != nil {
+		return x.SimIterations
+	}
+	return 0
+}
 
-func (x *BotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[7]
+func (x *SingleEvaluation) GetEndgameSolved() bool {
+	if x != nil {
+		return x.EndgameSolved
+	}
+	return false
+}
+
+func (x *SingleEvaluation) GetEndgameSpreadLoss() int32 {
+	if x != nil {
+		return x.EndgameSpreadLoss
+	}
+	return 0
+}
+
+func (x *SingleEvaluation) GetPhony() bool {
+	if x != nil {
+		return x.Phony
+	}
+	return false
+}
+
+func (x *SingleEvaluation) GetPhonyChallengeChance() float64 {
+	if x != nil {
+		return x.PhonyChallengeChance
+	}
+	return 0
+}
+
+type ChallengeEvaluation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The index of the event with the opponent's play.
+	EventIndex int32 `protobuf:"varint,1,opt,name=event_index,json=eventIndex,proto3" json:"event_index,omitempty"`
+	// The play, in GCG notation.
+	Play        string   `protobuf:"bytes,2,opt,name=play,proto3" json:"play,omitempty"`
+	WordsFormed []string `protobuf:"bytes,3,rep,name=words_formed,json=wordsFormed,proto3" json:"words_formed,omitempty"`
+	// The words formed that are not in the lexicon.
+	Phonies    []string `protobuf:"bytes,4,rep,name=phonies,proto3" json:"phonies,omitempty"`
+	Challenged bool     `protobuf:"varint,5,opt,name=challenged,proto3" json:"challenged,omitempty"`
+	// The user let a phony stand.
+	MissedChallenge bool `protobuf:"varint,6,opt,name=missed_challenge,json=missedChallenge,proto3" json:"missed_challenge,omitempty"`
+	// The user challenged a valid play.
+	WrongfulChallenge bool `protobuf:"varint,7,opt,name=wrongful_challenge,json=wrongfulChallenge,proto3" json:"wrongful_challenge,omitempty"`
+}
+
+func (x *ChallengeEvaluation) Reset() {
+	*x = ChallengeEvaluation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChallengeEvaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengeEvaluation) ProtoMessage() {}
+
+func (x *ChallengeEvaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengeEvaluation.ProtoReflect.Descriptor instead.
+func (*ChallengeEvaluation) Descriptor() ([]byte, []int) {
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChallengeEvaluation) GetEventIndex() int32 {
+	if x != nil {
+		return x.EventIndex
+	}
+	return 0
+}
+
+func (x *ChallengeEvaluation) GetPlay() string {
+	if x != nil {
+		return x.Play
+	}
+	return ""
+}
+
+func (x *ChallengeEvaluation) GetWordsFormed() []string {
+	if x != nil {
+		return x.WordsFormed
+	}
+	return nil
+}
+
+func (x *ChallengeEvaluation) GetPhonies() []string {
+	if x != nil {
+		return x.Phonies
+	}
+	return nil
+}
+
+func (x *ChallengeEvaluation) GetChallenged() bool {
+	if x != nil {
+		return x.Challenged
+	}
+	return false
+}
+
+func (x *ChallengeEvaluation) GetMissedChallenge() bool {
+	if x != nil {
+		return x.MissedChallenge
+	}
+	return false
+}
+
+func (x *ChallengeEvaluation) GetWrongfulChallenge() bool {
+	if x != nil {
+		return x.WrongfulChallenge
+	}
+	return false
+}
+
+type BotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Response:
+	//
+	//	*BotResponse_Move
+	//	*BotResponse_Error
+	Response isBotResponse_Response `protobuf_oneof:"response"`
+	Eval     *Evaluation            `protobuf:"bytes,3,opt,name=eval,proto3" json:"eval,omitempty"`
+	GameId   string                 `protobuf:"bytes,4,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+}
+
+func (x *BotResponse) Reset() {
+	*x = BotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BotResponse) ProtoMessage() {}
+
+func (x *BotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1173,7 +1672,7 @@ func (x *BotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BotResponse.ProtoReflect.Descriptor instead.
 func (*BotResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{9}
 }
 
 func (m *BotResponse) GetResponse() isBotResponse_Response {
@@ -1237,12 +1736,23 @@ type PuzzleCreationResponse struct {
 	Answer      *GameEvent  `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`
 	Tags        []PuzzleTag `protobuf:"varint,4,rep,packed,name=tags,proto3,enum=macondo.PuzzleTag" json:"tags,omitempty"`
 	BucketIndex int32       `protobuf:"varint,5,opt,name=bucket_index,json=bucketIndex,proto3" json:"bucket_index,omitempty"`
+	// For endgame puzzles, the best sequence of moves for both players,
+	// starting with the answer.
+	PrincipalVariation []*GameEvent `protobuf:"bytes,6,rep,name=principal_variation,json=principalVariation,proto3" json:"principal_variation,omitempty"`
+	// If the puzzle was verified by simming, the result of the sim.
+	SimStats *PuzzleSimStats `protobuf:"bytes,7,opt,name=sim_stats,json=simStats,proto3" json:"sim_stats,omitempty"`
+	// An estimate of how hard the puzzle is, from 0, the easiest, to 100,
+	// the hardest. It goes up the less findable, longer and rarer the
+	// answer's word is, the more moves compete with it, the less it is ahead
+	// of the next best move by, and the more words it forms and tiles it
+	// plays through.
+	Difficulty float64 `protobuf:"fixed64,8,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
 }
 
 func (x *PuzzleCreationResponse) Reset() {
 	*x = PuzzleCreationResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[8]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1255,7 +1765,7 @@ func (x *PuzzleCreationResponse) String() string {
 func (*PuzzleCreationResponse) ProtoMessage() {}
 
 func (x *PuzzleCreationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[8]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1268,7 +1778,7 @@ func (x *PuzzleCreationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PuzzleCreationResponse.ProtoReflect.Descriptor instead.
 func (*PuzzleCreationResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *PuzzleCreationResponse) GetGameId() string {
@@ -1306,6 +1816,156 @@ func (x *PuzzleCreationResponse) GetBucketIndex() int32 {
 	return 0
 }
 
+func (x *PuzzleCreationResponse) GetPrincipalVariation() []*GameEvent {
+	if x != nil {
+		return x.PrincipalVariation
+	}
+	return nil
+}
+
+func (x *PuzzleCreationResponse) GetSimStats() *PuzzleSimStats {
+	if x != nil {
+		return x.SimStats
+	}
+	return nil
+}
+
+func (x *PuzzleCreationResponse) GetDifficulty() float64 {
+	if x != nil {
+		return x.Difficulty
+	}
+	return 0
+}
+
+type PuzzleSimStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Iterations int32 `protobuf:"varint,1,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	Plies      int32 `protobuf:"varint,2,opt,name=plies,proto3" json:"plies,omitempty"`
+	// The candidate plays, best first by win percentage. The answer is
+	// first.
+	Plays []*SimmedPuzzlePlay `protobuf:"bytes,3,rep,name=plays,proto3" json:"plays,omitempty"`
+}
+
+func (x *PuzzleSimStats) Reset() {
+	*x = PuzzleSimStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PuzzleSimStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PuzzleSimStats) ProtoMessage() {}
+
+func (x *PuzzleSimStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PuzzleSimStats.ProtoReflect.Descriptor instead.
+func (*PuzzleSimStats) Descriptor() ([]byte, []int) {
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PuzzleSimStats) GetIterations() int32 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
+}
+
+func (x *PuzzleSimStats) GetPlies() int32 {
+	if x != nil {
+		return x.Plies
+	}
+	return 0
+}
+
+func (x *PuzzleSimStats) GetPlays() []*SimmedPuzzlePlay {
+	if x != nil {
+		return x.Plays
+	}
+	return nil
+}
+
+type SimmedPuzzlePlay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Play string `protobuf:"bytes,1,opt,name=play,proto3" json:"play,omitempty"`
+	// From 0 to 100.
+	WinPct float64 `protobuf:"fixed64,2,opt,name=win_pct,json=winPct,proto3" json:"win_pct,omitempty"`
+	Equity float64 `protobuf:"fixed64,3,opt,name=equity,proto3" json:"equity,omitempty"`
+}
+
+func (x *SimmedPuzzlePlay) Reset() {
+	*x = SimmedPuzzlePlay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimmedPuzzlePlay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimmedPuzzlePlay) ProtoMessage() {}
+
+func (x *SimmedPuzzlePlay) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimmedPuzzlePlay.ProtoReflect.Descriptor instead.
+func (*SimmedPuzzlePlay) Descriptor() ([]byte, []int) {
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SimmedPuzzlePlay) GetPlay() string {
+	if x != nil {
+		return x.Play
+	}
+	return ""
+}
+
+func (x *SimmedPuzzlePlay) GetWinPct() float64 {
+	if x != nil {
+		return x.WinPct
+	}
+	return 0
+}
+
+func (x *SimmedPuzzlePlay) GetEquity() float64 {
+	if x != nil {
+		return x.Equity
+	}
+	return 0
+}
+
 type PuzzleBucket struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1320,7 +1980,7 @@ type PuzzleBucket struct {
 func (x *PuzzleBucket) Reset() {
 	*x = PuzzleBucket{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[9]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1333,7 +1993,7 @@ func (x *PuzzleBucket) String() string {
 func (*PuzzleBucket) ProtoMessage() {}
 
 func (x *PuzzleBucket) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[9]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1346,7 +2006,7 @@ func (x *PuzzleBucket) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PuzzleBucket.ProtoReflect.Descriptor instead.
 func (*PuzzleBucket) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *PuzzleBucket) GetIndex() int32 {
@@ -1383,12 +2043,34 @@ type PuzzleGenerationRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	Buckets []*PuzzleBucket `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	// endgames solves the positions with an empty bag to make endgame
+	// puzzles from them, and sims the pre-endgame positions, with fewer tiles
+	// in the bag than a full rack, to make pre-endgame puzzles. It is off by
+	// default, since solving and simming are slow.
+	Endgames bool `protobuf:"varint,2,opt,name=endgames,proto3" json:"endgames,omitempty"`
+	// endgame_plies is how many plies deep to solve endgames. By default, 6.
+	EndgamePlies int32 `protobuf:"varint,3,opt,name=endgame_plies,json=endgamePlies,proto3" json:"endgame_plies,omitempty"`
+	// endgame_seconds is how long to spend on each endgame position, solving
+	// it and all its first moves, before giving up on it. By default, 30.
+	EndgameSeconds int32 `protobuf:"varint,9,opt,name=endgame_seconds,json=endgameSeconds,proto3" json:"endgame_seconds,omitempty"`
+	// sim_verify sims the best moves by static equity in every position that
+	// makes a puzzle, and only keeps the puzzle if its answer, the best
+	// static move, also wins the sim by sim_margin win percentage points.
+	// Endgame puzzles are solved exactly instead, and pre-endgame puzzles
+	// are always simmed.
+	SimVerify bool    `protobuf:"varint,4,opt,name=sim_verify,json=simVerify,proto3" json:"sim_verify,omitempty"`
+	SimMargin float64 `protobuf:"fixed64,5,opt,name=sim_margin,json=simMargin,proto3" json:"sim_margin,omitempty"`
+	// Options for the sim. Zero values mean defaults.
+	SimPlies int32 `protobuf:"varint,6,opt,name=sim_plies,json=simPlies,proto3" json:"sim_plies,omitempty"`
+	// The number of moves, by static equity, to sim.
+	SimCandidates int32 `protobuf:"varint,7,opt,name=sim_candidates,json=simCandidates,proto3" json:"sim_candidates,omitempty"`
+	SimIterations int32 `protobuf:"varint,8,opt,name=sim_iterations,json=simIterations,proto3" json:"sim_iterations,omitempty"`
 }
 
 func (x *PuzzleGenerationRequest) Reset() {
 	*x = PuzzleGenerationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_macondo_macondo_proto_msgTypes[10]
+		mi := &file_api_proto_macondo_macondo_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1401,7 +2083,7 @@ func (x *PuzzleGenerationRequest) String() string {
 func (*PuzzleGenerationRequest) ProtoMessage() {}
 
 func (x *PuzzleGenerationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_macondo_macondo_proto_msgTypes[10]
+	mi := &file_api_proto_macondo_macondo_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1414,7 +2096,7 @@ func (x *PuzzleGenerationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PuzzleGenerationRequest.ProtoReflect.Descriptor instead.
 func (*PuzzleGenerationRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_macondo_macondo_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *PuzzleGenerationRequest) GetBuckets() []*PuzzleBucket {
@@ -1424,12 +2106,68 @@ func (x *PuzzleGenerationRequest) GetBuckets() []*PuzzleBucket {
 	return nil
 }
 
+func (x *PuzzleGenerationRequest) GetEndgames() bool {
+	if x != nil {
+		return x.Endgames
+	}
+	return false
+}
+
+func (x *PuzzleGenerationRequest) GetEndgamePlies() int32 {
+	if x != nil {
+		return x.EndgamePlies
+	}
+	return 0
+}
+
+func (x *PuzzleGenerationRequest) GetEndgameSeconds() int32 {
+	if x != nil {
+		return x.EndgameSeconds
+	}
+	return 0
+}
+
+func (x *PuzzleGenerationRequest) GetSimVerify() bool {
+	if x != nil {
+		return x.SimVerify
+	}
+	return false
+}
+
+func (x *PuzzleGenerationRequest) GetSimMargin() float64 {
+	if x != nil {
+		return x.SimMargin
+	}
+	return 0
+}
+
+func (x *PuzzleGenerationRequest) GetSimPlies() int32 {
+	if x != nil {
+		return x.SimPlies
+	}
+	return 0
+}
+
+func (x *PuzzleGenerationRequest) GetSimCandidates() int32 {
+	if x != nil {
+		return x.SimCandidates
+	}
+	return 0
+}
+
+func (x *PuzzleGenerationRequest) GetSimIterations() int32 {
+	if x != nil {
+		return x.SimIterations
+	}
+	return 0
+}
+
 var File_api_proto_macondo_macondo_proto protoreflect.FileDescriptor
 
 var file_api_proto_macondo_macondo_proto_rawDesc = []byte{
 	0x0a, 0x1f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x61, 0x63, 0x6f,
 	0x6e, 0x64, 0x6f, 0x2f, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x07, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x22, 0xba, 0x05, 0x0a, 0x0b, 0x47,
+	0x6f, 0x12, 0x07, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x22, 0x9e, 0x06, 0x0a, 0x0b, 0x47,
 	0x61, 0x6d, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x76,
 	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63,
 	0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06,
@@ -1473,7 +2211,25 @@ var file_api_proto_macondo_macondo_proto_rawDesc = []byte{
 	0x52, 0x12, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75,
 	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67,
 	0x5f, 0x63, 0x67, 0x70, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x69, 0x6e, 0x67, 0x43, 0x67, 0x70, 0x22, 0x94, 0x07, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65,
+	0x74, 0x69, 0x6e, 0x67, 0x43, 0x67, 0x70, 0x12, 0x2b, 0x0a, 0x06, 0x74, 0x69, 0x6d, 0x65, 0x72,
+	0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64,
+	0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x06, 0x74, 0x69,
+	0x6d, 0x65, 0x72, 0x73, 0x12, 0x35, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x5f, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e,
+	0x64, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x52, 0x09, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xb9, 0x01, 0x0a, 0x0a,
+	0x47, 0x61, 0x6d, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0d, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4d, 0x69, 0x6c, 0x6c, 0x69,
+	0x73, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d,
+	0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x69, 0x6e, 0x63,
+	0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x12, 0x2e, 0x0a, 0x13,
+	0x6d, 0x61, 0x78, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6d, 0x69, 0x6c,
+	0x6c, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x4f, 0x76,
+	0x65, 0x72, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x12, 0x29, 0x0a, 0x10,
+	0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x52, 0x65,
+	0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x94, 0x07, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65,
 	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x02, 0x18, 0x01, 0x52, 0x08, 0x6e, 0x69, 0x63,
 	0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f, 0x74, 0x65, 0x18, 0x02, 0x20,
@@ -1536,8 +2292,8 @@ var file_api_proto_macondo_macondo_proto_rawDesc = []byte{
 	0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x6c,
 	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x61,
 	0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x98,
-	0x04, 0x0a, 0x0a, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x93,
+	0x05, 0x0a, 0x0a, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a,
 	0x0c, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61,
 	0x6d, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x0b, 0x67, 0x61, 0x6d, 0x65, 0x48,
@@ -1549,110 +2305,229 @@ var file_api_proto_macondo_macondo_proto_rawDesc = []byte{
 	0x74, 0x12, 0x36, 0x0a, 0x08, 0x62, 0x6f, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x42, 0x6f,
 	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x42, 0x6f, 0x74, 0x43, 0x6f, 0x64, 0x65,
-	0x52, 0x07, 0x62, 0x6f, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0xcd, 0x02, 0x0a, 0x07, 0x42, 0x6f,
-	0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x48, 0x41, 0x53, 0x54, 0x59, 0x5f, 0x42,
-	0x4f, 0x54, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x31, 0x5f, 0x43,
-	0x45, 0x4c, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x56, 0x45,
-	0x4c, 0x32, 0x5f, 0x43, 0x45, 0x4c, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e,
-	0x4c, 0x45, 0x56, 0x45, 0x4c, 0x33, 0x5f, 0x43, 0x45, 0x4c, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x03,
-	0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x34, 0x5f, 0x43, 0x45, 0x4c, 0x5f, 0x42,
-	0x4f, 0x54, 0x10, 0x04, 0x12, 0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x31, 0x5f, 0x50,
-	0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x05, 0x12, 0x18,
-	0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x32, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49,
-	0x4c, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x06, 0x12, 0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45,
-	0x4c, 0x33, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x53, 0x54, 0x49, 0x43,
-	0x10, 0x07, 0x12, 0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x34, 0x5f, 0x50, 0x52, 0x4f,
-	0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x08, 0x12, 0x18, 0x0a, 0x14,
-	0x4c, 0x45, 0x56, 0x45, 0x4c, 0x35, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49,
-	0x53, 0x54, 0x49, 0x43, 0x10, 0x09, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x4f, 0x5f, 0x4c, 0x45, 0x41,
-	0x56, 0x45, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x0a, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x49, 0x4d, 0x4d,
-	0x49, 0x4e, 0x47, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x0b, 0x12, 0x1a, 0x0a, 0x16, 0x48, 0x41, 0x53,
-	0x54, 0x59, 0x5f, 0x50, 0x4c, 0x55, 0x53, 0x5f, 0x45, 0x4e, 0x44, 0x47, 0x41, 0x4d, 0x45, 0x5f,
-	0x42, 0x4f, 0x54, 0x10, 0x0c, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x49, 0x4d, 0x4d, 0x49, 0x4e, 0x47,
-	0x5f, 0x49, 0x4e, 0x46, 0x45, 0x52, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x0d, 0x12, 0x0b, 0x0a, 0x07,
-	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x64, 0x22, 0x27, 0x0a, 0x11, 0x45, 0x76, 0x61,
-	0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
-	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73,
-	0x65, 0x72, 0x22, 0x44, 0x0a, 0x0a, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x36, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x65, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20,
+	0x52, 0x07, 0x62, 0x6f, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x6c,
+	0x6c, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0c, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x29,
+	0x0a, 0x10, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69,
+	0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73,
+	0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63,
+	0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x69,
+	0x6c, 0x6c, 0x69, 0x73, 0x22, 0xcd, 0x02, 0x0a, 0x07, 0x42, 0x6f, 0x74, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x0d, 0x0a, 0x09, 0x48, 0x41, 0x53, 0x54, 0x59, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x00, 0x12,
+	0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x31, 0x5f, 0x43, 0x45, 0x4c, 0x5f, 0x42, 0x4f,
+	0x54, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x32, 0x5f, 0x43, 0x45,
+	0x4c, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x56, 0x45, 0x4c,
+	0x33, 0x5f, 0x43, 0x45, 0x4c, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x4c,
+	0x45, 0x56, 0x45, 0x4c, 0x34, 0x5f, 0x43, 0x45, 0x4c, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x04, 0x12,
+	0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x31, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42,
+	0x49, 0x4c, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56,
+	0x45, 0x4c, 0x32, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x53, 0x54, 0x49,
+	0x43, 0x10, 0x06, 0x12, 0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x33, 0x5f, 0x50, 0x52,
+	0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x07, 0x12, 0x18, 0x0a,
+	0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x34, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c,
+	0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x08, 0x12, 0x18, 0x0a, 0x14, 0x4c, 0x45, 0x56, 0x45, 0x4c,
+	0x35, 0x5f, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10,
+	0x09, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x4f, 0x5f, 0x4c, 0x45, 0x41, 0x56, 0x45, 0x5f, 0x42, 0x4f,
+	0x54, 0x10, 0x0a, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x49, 0x4d, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x42,
+	0x4f, 0x54, 0x10, 0x0b, 0x12, 0x1a, 0x0a, 0x16, 0x48, 0x41, 0x53, 0x54, 0x59, 0x5f, 0x50, 0x4c,
+	0x55, 0x53, 0x5f, 0x45, 0x4e, 0x44, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x0c,
+	0x12, 0x15, 0x0a, 0x11, 0x53, 0x49, 0x4d, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x49, 0x4e, 0x46, 0x45,
+	0x52, 0x5f, 0x42, 0x4f, 0x54, 0x10, 0x0d, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
+	0x57, 0x4e, 0x10, 0x64, 0x22, 0xae, 0x02, 0x0a, 0x11, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x3d,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x6d,
+	0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x69, 0x6d, 0x5f, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x73, 0x69, 0x6d, 0x50, 0x6c, 0x69, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69,
+	0x6d, 0x5f, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0d, 0x73, 0x69, 0x6d, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x6d, 0x5f, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x69, 0x6d, 0x49, 0x74,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x70, 0x68, 0x6f, 0x6e,
+	0x79, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x70, 0x68, 0x6f, 0x6e, 0x79, 0x43,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x25,
+	0x0a, 0x0e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x41, 0x54, 0x49, 0x43, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
+	0x53, 0x49, 0x4d, 0x10, 0x01, 0x22, 0x89, 0x01, 0x0a, 0x0a, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x65, 0x76, 0x61,
+	0x6c, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64,
+	0x6f, 0x2e, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x45, 0x76, 0x61, 0x6c, 0x12, 0x43, 0x0a, 0x0e,
+	0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x65, 0x76, 0x61, 0x6c, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x43,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x45, 0x76, 0x61,
+	0x6c, 0x22, 0x9d, 0x04, 0x0a, 0x10, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x45, 0x76, 0x61, 0x6c,
+	0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79,
+	0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x65, 0x71, 0x75,
+	0x69, 0x74, 0x79, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x77, 0x69, 0x6e, 0x5f, 0x70,
+	0x63, 0x74, 0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x77,
+	0x69, 0x6e, 0x50, 0x63, 0x74, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x69, 0x73,
+	0x73, 0x65, 0x64, 0x5f, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x12, 0x2c, 0x0a, 0x12,
+	0x70, 0x6f, 0x73, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x5f, 0x70, 0x6c,
+	0x61, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x70, 0x6f, 0x73, 0x73, 0x69, 0x62,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x72, 0x50, 0x6c, 0x61, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x69,
+	0x73, 0x73, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x53, 0x74, 0x61, 0x72,
+	0x50, 0x6c, 0x61, 0x79, 0x12, 0x20, 0x0a, 0x0c, 0x74, 0x6f, 0x70, 0x5f, 0x69, 0x73, 0x5f, 0x62,
+	0x69, 0x6e, 0x67, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x6f, 0x70, 0x49,
+	0x73, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x70, 0x5f, 0x6d, 0x6f,
+	0x76, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6f, 0x70, 0x4d, 0x6f, 0x76,
+	0x65, 0x12, 0x24, 0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x5f, 0x77, 0x69, 0x6e, 0x5f,
+	0x70, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x64, 0x57, 0x69, 0x6e, 0x50, 0x63, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x74, 0x6f, 0x70, 0x5f, 0x77,
+	0x69, 0x6e, 0x5f, 0x70, 0x63, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x74, 0x6f,
+	0x70, 0x57, 0x69, 0x6e, 0x50, 0x63, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x6d, 0x5f, 0x69,
+	0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x73, 0x69, 0x6d, 0x49, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d, 0x65, 0x53,
+	0x6f, 0x6c, 0x76, 0x65, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d, 0x65,
+	0x5f, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x11, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x70, 0x72, 0x65, 0x61,
+	0x64, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x79, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x79, 0x12, 0x34, 0x0a, 0x16, 0x70,
+	0x68, 0x6f, 0x6e, 0x79, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x70, 0x68, 0x6f,
+	0x6e, 0x79, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x63,
+	0x65, 0x22, 0x81, 0x02, 0x0a, 0x13, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x45,
+	0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6c,
+	0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x79, 0x12, 0x21,
+	0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x46, 0x6f, 0x72, 0x6d, 0x65,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x68, 0x6f, 0x6e, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x68, 0x6f, 0x6e, 0x69, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x6d,
+	0x69, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x43, 0x68, 0x61,
+	0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x66,
+	0x75, 0x6c, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x11, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x66, 0x75, 0x6c, 0x43, 0x68, 0x61, 0x6c,
+	0x6c, 0x65, 0x6e, 0x67, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x0b, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12,
+	0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x27, 0x0a, 0x04, 0x65, 0x76, 0x61, 0x6c, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x65, 0x76, 0x61, 0x6c,
+	0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xe4, 0x02, 0x0a, 0x16, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x75, 0x72,
+	0x6e, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x74, 0x75, 0x72, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x06, 0x61, 0x6e,
+	0x73, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63,
+	0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06,
+	0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x50,
+	0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x43, 0x0a, 0x13, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x5f, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x12, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x56, 0x61, 0x72,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x09, 0x73, 0x69, 0x6d, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x63, 0x6f,
+	0x6e, 0x64, 0x6f, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x53, 0x69, 0x6d, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x08, 0x73, 0x69, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x22, 0x77, 0x0a, 0x0e,
+	0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x53, 0x69, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x70,
+	0x6c, 0x69, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x6c, 0x61, 0x79, 0x73, 0x18, 0x03, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x53, 0x69,
-	0x6e, 0x67, 0x6c, 0x65, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08,
-	0x70, 0x6c, 0x61, 0x79, 0x45, 0x76, 0x61, 0x6c, 0x22, 0xf2, 0x01, 0x0a, 0x10, 0x53, 0x69, 0x6e,
-	0x67, 0x6c, 0x65, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a,
-	0x0b, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x01, 0x52, 0x0a, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x20,
-	0x0a, 0x0c, 0x77, 0x69, 0x6e, 0x5f, 0x70, 0x63, 0x74, 0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x77, 0x69, 0x6e, 0x50, 0x63, 0x74, 0x4c, 0x6f, 0x73, 0x73,
-	0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x69, 0x6e, 0x67, 0x6f,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x42, 0x69,
-	0x6e, 0x67, 0x6f, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6f, 0x73, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x5f,
-	0x73, 0x74, 0x61, 0x72, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x10, 0x70, 0x6f, 0x73, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x72, 0x50, 0x6c, 0x61,
-	0x79, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x72,
-	0x5f, 0x70, 0x6c, 0x61, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6d, 0x69, 0x73,
-	0x73, 0x65, 0x64, 0x53, 0x74, 0x61, 0x72, 0x50, 0x6c, 0x61, 0x79, 0x12, 0x20, 0x0a, 0x0c, 0x74,
-	0x6f, 0x70, 0x5f, 0x69, 0x73, 0x5f, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0a, 0x74, 0x6f, 0x70, 0x49, 0x73, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x22, 0x9d, 0x01,
-	0x0a, 0x0b, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
-	0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61,
-	0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
-	0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
-	0x27, 0x0a, 0x04, 0x65, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x04, 0x65, 0x76, 0x61, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49,
-	0x64, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xc9, 0x01,
-	0x0a, 0x16, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49,
-	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x75, 0x72, 0x6e, 0x4e, 0x75, 0x6d, 0x62,
-	0x65, 0x72, 0x12, 0x2a, 0x0a, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x47, 0x61, 0x6d,
-	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x26,
-	0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d,
-	0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67,
-	0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74,
-	0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x62, 0x75,
-	0x63, 0x6b, 0x65, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x98, 0x01, 0x0a, 0x0c, 0x50, 0x75,
-	0x7a, 0x7a, 0x6c, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e,
-	0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
-	0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
-	0x73, 0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x08, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73,
-	0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f,
-	0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x52, 0x08, 0x69, 0x6e, 0x63, 0x6c,
-	0x75, 0x64, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x08, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f,
-	0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x52, 0x08, 0x65, 0x78, 0x63, 0x6c,
-	0x75, 0x64, 0x65, 0x73, 0x22, 0x4a, 0x0a, 0x17, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x47, 0x65,
-	0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x2f, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x15, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c,
-	0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73,
-	0x2a, 0x43, 0x0a, 0x09, 0x50, 0x6c, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a,
-	0x07, 0x50, 0x4c, 0x41, 0x59, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x57, 0x41,
-	0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x5f, 0x46, 0x49, 0x4e, 0x41, 0x4c, 0x5f,
-	0x50, 0x41, 0x53, 0x53, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x4f,
-	0x56, 0x45, 0x52, 0x10, 0x02, 0x2a, 0x5c, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e,
-	0x67, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x4f, 0x49, 0x44, 0x10, 0x00,
-	0x12, 0x0a, 0x0a, 0x06, 0x53, 0x49, 0x4e, 0x47, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06,
-	0x44, 0x4f, 0x55, 0x42, 0x4c, 0x45, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x49, 0x56, 0x45,
-	0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45, 0x4e, 0x5f,
-	0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x52, 0x49, 0x50, 0x4c,
-	0x45, 0x10, 0x05, 0x2a, 0x89, 0x01, 0x0a, 0x09, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61,
-	0x67, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x51, 0x55, 0x49, 0x54, 0x59, 0x10, 0x00, 0x12, 0x09, 0x0a,
-	0x05, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x4e, 0x4c, 0x59,
-	0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x4c, 0x41, 0x4e,
-	0x4b, 0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x4e,
-	0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x04, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x4f, 0x57, 0x45,
-	0x52, 0x5f, 0x54, 0x49, 0x4c, 0x45, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x42, 0x49, 0x4e, 0x47,
-	0x4f, 0x5f, 0x4e, 0x49, 0x4e, 0x45, 0x5f, 0x4f, 0x52, 0x5f, 0x41, 0x42, 0x4f, 0x56, 0x45, 0x10,
-	0x06, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x45, 0x4c, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x07, 0x42,
-	0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f,
-	0x6d, 0x69, 0x6e, 0x6f, 0x31, 0x34, 0x2f, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2f, 0x67,
-	0x65, 0x6e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x61, 0x63,
-	0x6f, 0x6e, 0x64, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6d, 0x6d, 0x65, 0x64, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x05,
+	0x70, 0x6c, 0x61, 0x79, 0x73, 0x22, 0x57, 0x0a, 0x10, 0x53, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x50,
+	0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6c, 0x61,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x79, 0x12, 0x17, 0x0a,
+	0x07, 0x77, 0x69, 0x6e, 0x5f, 0x70, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x77, 0x69, 0x6e, 0x50, 0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79, 0x22, 0x98,
+	0x01, 0x0a, 0x0c, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x08, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61,
+	0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x52,
+	0x08, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x08, 0x65, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61,
+	0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x52,
+	0x08, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x22, 0xdd, 0x02, 0x0a, 0x17, 0x50, 0x75,
+	0x7a, 0x7a, 0x6c, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f,
+	0x2e, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x07, 0x62,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d,
+	0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x70, 0x6c,
+	0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x67, 0x61,
+	0x6d, 0x65, 0x50, 0x6c, 0x69, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x64, 0x67, 0x61,
+	0x6d, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x65, 0x6e, 0x64, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x69, 0x6d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x6d, 0x5f, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x73, 0x69, 0x6d, 0x4d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x69, 0x6d, 0x5f, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x73, 0x69, 0x6d, 0x50, 0x6c, 0x69, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73,
+	0x69, 0x6d, 0x5f, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x69, 0x6d, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x6d, 0x5f, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x69, 0x6d, 0x49,
+	0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2a, 0x9c, 0x01, 0x0a, 0x0d, 0x47, 0x61,
+	0x6d, 0x65, 0x45, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x08, 0x0a, 0x04, 0x4e,
+	0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x49, 0x4d, 0x45, 0x10, 0x01, 0x12,
+	0x0c, 0x0a, 0x08, 0x53, 0x54, 0x41, 0x4e, 0x44, 0x41, 0x52, 0x44, 0x10, 0x02, 0x12, 0x16, 0x0a,
+	0x12, 0x43, 0x4f, 0x4e, 0x53, 0x45, 0x43, 0x55, 0x54, 0x49, 0x56, 0x45, 0x5f, 0x5a, 0x45, 0x52,
+	0x4f, 0x45, 0x53, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x53, 0x49, 0x47, 0x4e, 0x45,
+	0x44, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x42, 0x4f, 0x52, 0x54, 0x45, 0x44, 0x10, 0x05,
+	0x12, 0x14, 0x0a, 0x10, 0x54, 0x52, 0x49, 0x50, 0x4c, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4c, 0x4c,
+	0x45, 0x4e, 0x47, 0x45, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c,
+	0x4c, 0x45, 0x44, 0x10, 0x07, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x4f, 0x52, 0x43, 0x45, 0x5f, 0x46,
+	0x4f, 0x52, 0x46, 0x45, 0x49, 0x54, 0x10, 0x08, 0x2a, 0x43, 0x0a, 0x09, 0x50, 0x6c, 0x61, 0x79,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x49, 0x4e, 0x47,
+	0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x4f,
+	0x52, 0x5f, 0x46, 0x49, 0x4e, 0x41, 0x4c, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x10, 0x01, 0x12, 0x0d,
+	0x0a, 0x09, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x02, 0x2a, 0x5c, 0x0a,
+	0x0d, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x08,
+	0x0a, 0x04, 0x56, 0x4f, 0x49, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x49, 0x4e, 0x47,
+	0x4c, 0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x4f, 0x55, 0x42, 0x4c, 0x45, 0x10, 0x02,
+	0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x49, 0x56, 0x45, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x03,
+	0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45, 0x4e, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x12,
+	0x0a, 0x0a, 0x06, 0x54, 0x52, 0x49, 0x50, 0x4c, 0x45, 0x10, 0x05, 0x2a, 0xe5, 0x01, 0x0a, 0x09,
+	0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x51, 0x55,
+	0x49, 0x54, 0x59, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x01,
+	0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x4e, 0x4c, 0x59, 0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x02,
+	0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x4c, 0x41, 0x4e, 0x4b, 0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10,
+	0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x4e, 0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x10, 0x04,
+	0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f, 0x54, 0x49, 0x4c, 0x45, 0x10, 0x05,
+	0x12, 0x17, 0x0a, 0x13, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x5f, 0x4e, 0x49, 0x4e, 0x45, 0x5f, 0x4f,
+	0x52, 0x5f, 0x41, 0x42, 0x4f, 0x56, 0x45, 0x10, 0x06, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x45, 0x4c,
+	0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x07, 0x12, 0x0f, 0x0a, 0x0b, 0x45, 0x4e, 0x44, 0x47, 0x41,
+	0x4d, 0x45, 0x5f, 0x57, 0x49, 0x4e, 0x10, 0x08, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x4e, 0x44, 0x47,
+	0x41, 0x4d, 0x45, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x5f, 0x57, 0x49, 0x4e, 0x4e, 0x49, 0x4e, 0x47,
+	0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x09, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x55, 0x54, 0x5f, 0x49,
+	0x4e, 0x5f, 0x54, 0x57, 0x4f, 0x10, 0x0a, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x45, 0x54, 0x55, 0x50,
+	0x10, 0x0b, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x45, 0x5f, 0x45, 0x4e, 0x44, 0x47, 0x41, 0x4d,
+	0x45, 0x10, 0x0c, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69, 0x6e, 0x6f, 0x31, 0x34, 0x2f, 0x6d, 0x61, 0x63, 0x6f, 0x6e,
+	0x64, 0x6f, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x6d, 0x61, 0x63, 0x6f, 0x6e, 0x64, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1667,50 +2542,63 @@ func file_api_proto_macondo_macondo_proto_rawDescGZIP() []byte {
 	return file_api_proto_macondo_macondo_proto_rawDescData
 }
 
-var file_api_proto_macondo_macondo_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_api_proto_macondo_macondo_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_proto_macondo_macondo_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_api_proto_macondo_macondo_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_api_proto_macondo_macondo_proto_goTypes = []interface{}{
-	(PlayState)(0),                  // 0: macondo.PlayState
-	(ChallengeRule)(0),              // 1: macondo.ChallengeRule
-	(PuzzleTag)(0),                  // 2: macondo.PuzzleTag
-	(GameEvent_Type)(0),             // 3: macondo.GameEvent.Type
-	(GameEvent_Direction)(0),        // 4: macondo.GameEvent.Direction
-	(BotRequest_BotCode)(0),         // 5: macondo.BotRequest.BotCode
-	(*GameHistory)(nil),             // 6: macondo.GameHistory
-	(*GameEvent)(nil),               // 7: macondo.GameEvent
-	(*PlayerInfo)(nil),              // 8: macondo.PlayerInfo
-	(*BotRequest)(nil),              // 9: macondo.BotRequest
-	(*EvaluationRequest)(nil),       // 10: macondo.EvaluationRequest
-	(*Evaluation)(nil),              // 11: macondo.Evaluation
-	(*SingleEvaluation)(nil),        // 12: macondo.SingleEvaluation
-	(*BotResponse)(nil),             // 13: macondo.BotResponse
-	(*PuzzleCreationResponse)(nil),  // 14: macondo.PuzzleCreationResponse
-	(*PuzzleBucket)(nil),            // 15: macondo.PuzzleBucket
-	(*PuzzleGenerationRequest)(nil), // 16: macondo.PuzzleGenerationRequest
+	(GameEndReason)(0),                    // 0: macondo.GameEndReason
+	(PlayState)(0),                        // 1: macondo.PlayState
+	(ChallengeRule)(0),                    // 2: macondo.ChallengeRule
+	(PuzzleTag)(0),                        // 3: macondo.PuzzleTag
+	(GameEvent_Type)(0),                   // 4: macondo.GameEvent.Type
+	(GameEvent_Direction)(0),              // 5: macondo.GameEvent.Direction
+	(BotRequest_BotCode)(0),               // 6: macondo.BotRequest.BotCode
+	(EvaluationRequest_EvaluationType)(0), // 7: macondo.EvaluationRequest.EvaluationType
+	(*GameHistory)(nil),                   // 8: macondo.GameHistory
+	(*GameTimers)(nil),                    // 9: macondo.GameTimers
+	(*GameEvent)(nil),                     // 10: macondo.GameEvent
+	(*PlayerInfo)(nil),                    // 11: macondo.PlayerInfo
+	(*BotRequest)(nil),                    // 12: macondo.BotRequest
+	(*EvaluationRequest)(nil),             // 13: macondo.EvaluationRequest
+	(*Evaluation)(nil),                    // 14: macondo.Evaluation
+	(*SingleEvaluation)(nil),              // 15: macondo.SingleEvaluation
+	(*ChallengeEvaluation)(nil),           // 16: macondo.ChallengeEvaluation
+	(*BotResponse)(nil),                   // 17: macondo.BotResponse
+	(*PuzzleCreationResponse)(nil),        // 18: macondo.PuzzleCreationResponse
+	(*PuzzleSimStats)(nil),                // 19: macondo.PuzzleSimStats
+	(*SimmedPuzzlePlay)(nil),              // 20: macondo.SimmedPuzzlePlay
+	(*PuzzleBucket)(nil),                  // 21: macondo.PuzzleBucket
+	(*PuzzleGenerationRequest)(nil),       // 22: macondo.PuzzleGenerationRequest
 }
 var file_api_proto_macondo_macondo_proto_depIdxs = []int32{
-	7,  // 0: macondo.GameHistory.events:type_name -> macondo.GameEvent
-	8,  // 1: macondo.GameHistory.players:type_name -> macondo.PlayerInfo
-	1,  // 2: macondo.GameHistory.challenge_rule:type_name -> macondo.ChallengeRule
-	0,  // 3: macondo.GameHistory.play_state:type_name -> macondo.PlayState
-	3,  // 4: macondo.GameEvent.type:type_name -> macondo.GameEvent.Type
-	4,  // 5: macondo.GameEvent.direction:type_name -> macondo.GameEvent.Direction
-	6,  // 6: macondo.BotRequest.game_history:type_name -> macondo.GameHistory
-	10, // 7: macondo.BotRequest.evaluation_request:type_name -> macondo.EvaluationRequest
-	5,  // 8: macondo.BotRequest.bot_type:type_name -> macondo.BotRequest.BotCode
-	12, // 9: macondo.Evaluation.play_eval:type_name -> macondo.SingleEvaluation
-	7,  // 10: macondo.BotResponse.move:type_name -> macondo.GameEvent
-	11, // 11: macondo.BotResponse.eval:type_name -> macondo.Evaluation
-	7,  // 12: macondo.PuzzleCreationResponse.answer:type_name -> macondo.GameEvent
-	2,  // 13: macondo.PuzzleCreationResponse.tags:type_name -> macondo.PuzzleTag
-	2,  // 14: macondo.PuzzleBucket.includes:type_name -> macondo.PuzzleTag
-	2,  // 15: macondo.PuzzleBucket.excludes:type_name -> macondo.PuzzleTag
-	15, // 16: macondo.PuzzleGenerationRequest.buckets:type_name -> macondo.PuzzleBucket
-	17, // [17:17] is the sub-list for method output_type
-	17, // [17:17] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	10, // 0: macondo.GameHistory.events:type_name -> macondo.GameEvent
+	11, // 1: macondo.GameHistory.players:type_name -> macondo.PlayerInfo
+	2,  // 2: macondo.GameHistory.challenge_rule:type_name -> macondo.ChallengeRule
+	1,  // 3: macondo.GameHistory.play_state:type_name -> macondo.PlayState
+	9,  // 4: macondo.GameHistory.timers:type_name -> macondo.GameTimers
+	0,  // 5: macondo.GameHistory.end_reason:type_name -> macondo.GameEndReason
+	4,  // 6: macondo.GameEvent.type:type_name -> macondo.GameEvent.Type
+	5,  // 7: macondo.GameEvent.direction:type_name -> macondo.GameEvent.Direction
+	8,  // 8: macondo.BotRequest.game_history:type_name -> macondo.GameHistory
+	13, // 9: macondo.BotRequest.evaluation_request:type_name -> macondo.EvaluationRequest
+	6,  // 10: macondo.BotRequest.bot_type:type_name -> macondo.BotRequest.BotCode
+	7,  // 11: macondo.EvaluationRequest.type:type_name -> macondo.EvaluationRequest.EvaluationType
+	15, // 12: macondo.Evaluation.play_eval:type_name -> macondo.SingleEvaluation
+	16, // 13: macondo.Evaluation.challenge_eval:type_name -> macondo.ChallengeEvaluation
+	10, // 14: macondo.BotResponse.move:type_name -> macondo.GameEvent
+	14, // 15: macondo.BotResponse.eval:type_name -> macondo.Evaluation
+	10, // 16: macondo.PuzzleCreationResponse.answer:type_name -> macondo.GameEvent
+	3,  // 17: macondo.PuzzleCreationResponse.tags:type_name -> macondo.PuzzleTag
+	10, // 18: macondo.PuzzleCreationResponse.principal_variation:type_name -> macondo.GameEvent
+	19, // 19: macondo.PuzzleCreationResponse.sim_stats:type_name -> macondo.PuzzleSimStats
+	20, // 20: macondo.PuzzleSimStats.plays:type_name -> macondo.SimmedPuzzlePlay
+	3,  // 21: macondo.PuzzleBucket.includes:type_name -> macondo.PuzzleTag
+	3,  // 22: macondo.PuzzleBucket.excludes:type_name -> macondo.PuzzleTag
+	21, // 23: macondo.PuzzleGenerationRequest.buckets:type_name -> macondo.PuzzleBucket
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_macondo_macondo_proto_init() }
@@ -1732,7 +2620,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GameEvent); i {
+			switch v := v.(*GameTimers); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1744,7 +2632,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PlayerInfo); i {
+			switch v := v.(*GameEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1756,7 +2644,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BotRequest); i {
+			switch v := v.(*PlayerInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1768,7 +2656,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EvaluationRequest); i {
+			switch v := v.(*BotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1780,7 +2668,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Evaluation); i {
+			switch v := v.(*EvaluationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1792,7 +2680,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SingleEvaluation); i {
+			switch v := v.(*Evaluation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1804,7 +2692,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BotResponse); i {
+			switch v := v.(*SingleEvaluation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1816,7 +2704,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PuzzleCreationResponse); i {
+			switch v := v.(*ChallengeEvaluation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1828,7 +2716,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PuzzleBucket); i {
+			switch v := v.(*BotResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1840,6 +2728,54 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 		file_api_proto_macondo_macondo_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PuzzleCreationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_macondo_macondo_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PuzzleSimStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_macondo_macondo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimmedPuzzlePlay); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_macondo_macondo_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PuzzleBucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_macondo_macondo_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PuzzleGenerationRequest); i {
 			case 0:
 				return &v.state
@@ -1852,7 +2788,7 @@ func file_api_proto_macondo_macondo_proto_init() {
 			}
 		}
 	}
-	file_api_proto_macondo_macondo_proto_msgTypes[7].OneofWrappers = []interface{}{
+	file_api_proto_macondo_macondo_proto_msgTypes[9].OneofWrappers = []interface{}{
 		(*BotResponse_Move)(nil),
 		(*BotResponse_Error)(nil),
 	}
@@ -1861,8 +2797,8 @@ func file_api_proto_macondo_macondo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_macondo_macondo_proto_rawDesc,
-			NumEnums:      6,
-			NumMessages:   11,
+			NumEnums:      8,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,107 @@
+package kwg
+
+import (
+	"sort"
+
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// ForEachWord calls f with every word in the KWG, in the order of its
+// alphabet. f must not modify the given slice. If f returns an error, the
+// iteration stops and returns it.
+func (k *KWG) ForEachWord(f func(tilemapping.MachineWord) error) error {
+	word := tilemapping.MachineWord{}
+	var iterate func(nodeIdx uint32) error
+	iterate = func(nodeIdx uint32) error {
+		for ; ; nodeIdx++ {
+			word = append(word, tilemapping.MachineLetter(k.Tile(nodeIdx)))
+			if k.Accepts(nodeIdx) {
+				if err := f(word); err != nil {
+					return err
+				}
+			}
+			if arcIndex := k.ArcIndex(nodeIdx); arcIndex != 0 {
+				if err := iterate(arcIndex); err != nil {
+					return err
+				}
+			}
+			word = word[:len(word)-1]
+			if k.IsEnd(nodeIdx) {
+				return nil
+			}
+		}
+	}
+	if k.ArcIndex(0) == 0 {
+		return nil
+	}
+	return iterate(k.ArcIndex(0))
+}
+
+// A LexiconDiff is what changed from one lexicon to another. Words are
+// compared as they are written, so lexicons with different alphabets can
+// be compared too. All maps are by word length, in tiles.
+type LexiconDiff struct {
+	// Added and Removed words are in alphabetical order.
+	Added   map[int][]string
+	Removed map[int][]string
+	// OldCounts and NewCounts are the number of words in each lexicon.
+	OldCounts map[int]int
+	NewCounts map[int]int
+}
+
+// Diff compares the words of two lexicons.
+func Diff(oldLex, newLex *KWG) (*LexiconDiff, error) {
+	d := &LexiconDiff{
+		Added:     map[int][]string{},
+		Removed:   map[int][]string{},
+		OldCounts: map[int]int{},
+		NewCounts: map[int]int{},
+	}
+	oldWords := map[string]int{}
+	err := oldLex.ForEachWord(func(w tilemapping.MachineWord) error {
+		oldWords[w.UserVisible(oldLex.GetAlphabet())] = len(w)
+		d.OldCounts[len(w)]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = newLex.ForEachWord(func(w tilemapping.MachineWord) error {
+		s := w.UserVisible(newLex.GetAlphabet())
+		if _, ok := oldWords[s]; ok {
+			delete(oldWords, s)
+		} else {
+			d.Added[len(w)] = append(d.Added[len(w)], s)
+		}
+		d.NewCounts[len(w)]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for s, n := range oldWords {
+		d.Removed[n] = append(d.Removed[n], s)
+	}
+	for _, words := range d.Added {
+		sort.Strings(words)
+	}
+	for _, words := range d.Removed {
+		sort.Strings(words)
+	}
+	return d, nil
+}
+
+// Lengths returns every word length in either lexicon, from the shortest.
+func (d *LexiconDiff) Lengths() []int {
+	lengths := []int{}
+	for n := range d.OldCounts {
+		lengths = append(lengths, n)
+	}
+	for n := range d.NewCounts {
+		if _, ok := d.OldCounts[n]; !ok {
+			lengths = append(lengths, n)
+		}
+	}
+	sort.Ints(lengths)
+	return lengths
+}
@@ -0,0 +1,34 @@
+package kwg
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/tilemapping"
+)
+
+func TestForEachWord(t *testing.T) {
+	is := is.New(t)
+	d := wordsKWG(is, "ZA", "QI", "AA", "AAH", "AAHED")
+	var words []string
+	is.NoErr(d.ForEachWord(func(w tilemapping.MachineWord) error {
+		words = append(words, w.UserVisible(d.GetAlphabet()))
+		return nil
+	}))
+	is.Equal(words, []string{"AA", "AAH", "AAHED", "QI", "ZA"})
+}
+
+func TestDiff(t *testing.T) {
+	is := is.New(t)
+	oldLex := wordsKWG(is, "AA", "QI", "ZA", "CAT", "CATS", "DOG", "FOO")
+	newLex := wordsKWG(is, "AA", "OK", "QI", "ZA", "EW", "CAT", "CATS", "DOG", "ZEN")
+
+	d, err := Diff(oldLex, newLex)
+	is.NoErr(err)
+	is.Equal(d.Added, map[int][]string{2: {"EW", "OK"}, 3: {"ZEN"}})
+	is.Equal(d.Removed, map[int][]string{3: {"FOO"}})
+	is.Equal(d.OldCounts, map[int]int{2: 3, 3: 3, 4: 1})
+	is.Equal(d.NewCounts, map[int]int{2: 5, 3: 3, 4: 1})
+	is.Equal(d.Lengths(), []int{2, 3, 4})
+}
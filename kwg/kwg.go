@@ -3,6 +3,7 @@ package kwg
 import (
 	"encoding/binary"
 	"io"
+	"sort"
 
 	"github.com/domino14/macondo/tilemapping"
 	"github.com/rs/zerolog/log"
@@ -36,6 +37,60 @@ func ScanKWG(data io.Reader) (*KWG, error) {
 	return &KWG{nodes: nodes}, nil
 }
 
+// FromWords builds a KWG with just the given words. It is a trie rather
+// than a minimal graph, and its GADDAG is empty, so it can look words up
+// and list them, but can't generate moves. It is meant for small word
+// lists, such as test fixtures.
+func FromWords(alph *tilemapping.TileMapping, words []tilemapping.MachineWord) *KWG {
+	type trie struct {
+		children map[tilemapping.MachineLetter]*trie
+		accepts  bool
+	}
+	root := &trie{children: map[tilemapping.MachineLetter]*trie{}}
+	for _, w := range words {
+		t := root
+		for _, ml := range w {
+			if t.children[ml] == nil {
+				t.children[ml] = &trie{children: map[tilemapping.MachineLetter]*trie{}}
+			}
+			t = t.children[ml]
+		}
+		t.accepts = true
+	}
+	// Nodes 0 and 1 point to the DAWG and the GADDAG. Each node has the
+	// letter in its top 8 bits, then the accepts and end-of-siblings bits,
+	// then the index of its first child.
+	nodes := []uint32{0, 0x400000}
+	var place func(t *trie) uint32
+	place = func(t *trie) uint32 {
+		if len(t.children) == 0 {
+			return 0
+		}
+		letters := []tilemapping.MachineLetter{}
+		for ml := range t.children {
+			letters = append(letters, ml)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		start := len(nodes)
+		nodes = append(nodes, make([]uint32, len(letters))...)
+		for i, ml := range letters {
+			child := t.children[ml]
+			n := uint32(ml) << 24
+			if child.accepts {
+				n |= 0x800000
+			}
+			if i == len(letters)-1 {
+				n |= 0x400000
+			}
+			n |= place(child)
+			nodes[start+i] = n
+		}
+		return uint32(start)
+	}
+	nodes[0] = 0x400000 | place(root)
+	return &KWG{nodes: nodes, alphabet: alph}
+}
+
 func (k *KWG) GetRootNodeIndex() uint32 {
 	return k.ArcIndex(1) // (1) for a GADDAG, (0) for a DAWG
 }
@@ -0,0 +1,213 @@
+package kwg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// maxPatternTokens is the most letters and wildcards a pattern can have, as
+// the states of a match are kept in a bit mask.
+const maxPatternTokens = 63
+
+var (
+	errPatternTooLong    = errors.New("pattern is too long")
+	errEmptyPattern      = errors.New("pattern is empty")
+	errUnclosedClass     = errors.New("pattern has a [ without a ]")
+	errUnopenedClass     = errors.New("pattern has a ] without a [")
+	errEmptyPatternClass = errors.New("pattern has an empty [] class")
+)
+
+// A Pattern matches words letter by letter. In a pattern string, ? matches
+// any one letter and * any number of letters, including none. Letters in
+// brackets, such as [AEIO], match any one of them, and [^AEIO] matches any
+// letter but them. Anything else is a letter that must be matched as is.
+type Pattern struct {
+	tokens []patternToken
+}
+
+type patternToken struct {
+	letters tilemapping.LetterSet
+	// star tokens match any number of letters in the set.
+	star bool
+}
+
+// ParsePattern parses a pattern string, such as ?A[EIO]*S, for the given
+// alphabet.
+func ParsePattern(pattern string, alph *tilemapping.TileMapping) (*Pattern, error) {
+	var anyLetter tilemapping.LetterSet
+	for ml := tilemapping.MachineLetter(1); ml < tilemapping.MachineLetter(alph.NumLetters()); ml++ {
+		anyLetter |= 1 << ml
+	}
+	letters := func(s string) ([]tilemapping.MachineLetter, error) {
+		mls, err := tilemapping.ToMachineLetters(strings.ToUpper(s), alph)
+		if err != nil {
+			return nil, err
+		}
+		for _, ml := range mls {
+			if ml == 0 {
+				return nil, fmt.Errorf("pattern %v has a space or a blank", pattern)
+			}
+		}
+		return mls, nil
+	}
+
+	p := &Pattern{}
+	literal := strings.Builder{}
+	// flush adds the literal letters read so far, one token for each.
+	flush := func() error {
+		if literal.Len() == 0 {
+			return nil
+		}
+		mls, err := letters(literal.String())
+		if err != nil {
+			return err
+		}
+		for _, ml := range mls {
+			p.tokens = append(p.tokens, patternToken{letters: 1 << ml})
+		}
+		literal.Reset()
+		return nil
+	}
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '?' && r != '*' && r != '[' && r != ']' {
+			literal.WriteRune(r)
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		switch r {
+		case '?':
+			p.tokens = append(p.tokens, patternToken{letters: anyLetter})
+		case '*':
+			p.tokens = append(p.tokens, patternToken{letters: anyLetter, star: true})
+		case ']':
+			return nil, errUnopenedClass
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errUnclosedClass
+			}
+			class := string(runes[i+1 : end])
+			negated := strings.HasPrefix(class, "^")
+			class = strings.TrimPrefix(class, "^")
+			if class == "" {
+				return nil, errEmptyPatternClass
+			}
+			mls, err := letters(class)
+			if err != nil {
+				return nil, err
+			}
+			var ls tilemapping.LetterSet
+			for _, ml := range mls {
+				ls |= 1 << ml
+			}
+			if negated {
+				ls = anyLetter &^ ls
+			}
+			p.tokens = append(p.tokens, patternToken{letters: ls})
+			i = end
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return nil, errEmptyPattern
+	}
+	if len(p.tokens) > maxPatternTokens {
+		return nil, errPatternTooLong
+	}
+	return p, nil
+}
+
+// The states of a match are a bit mask of the tokens that can match the
+// next letter; bit len(tokens) means the whole pattern has been matched.
+// closure adds the tokens after stars to the states, since a star can match
+// no letters at all.
+func (p *Pattern) closure(states uint64) uint64 {
+	for i, t := range p.tokens {
+		if t.star && states&(1<<i) != 0 {
+			states |= 1 << (i + 1)
+		}
+	}
+	return states
+}
+
+func (p *Pattern) start() uint64 {
+	return p.closure(1)
+}
+
+func (p *Pattern) step(states uint64, ml tilemapping.MachineLetter) uint64 {
+	next := uint64(0)
+	for i, t := range p.tokens {
+		if states&(1<<i) == 0 || t.letters&(1<<ml) == 0 {
+			continue
+		}
+		if t.star {
+			next |= 1 << i
+		} else {
+			next |= 1 << (i + 1)
+		}
+	}
+	return p.closure(next)
+}
+
+func (p *Pattern) accepts(states uint64) bool {
+	return states&(1<<len(p.tokens)) != 0
+}
+
+// Matches returns whether the pattern matches the word.
+func (p *Pattern) Matches(word tilemapping.MachineWord) bool {
+	states := p.start()
+	for _, ml := range word {
+		states = p.step(states, ml.Unblank())
+		if states == 0 {
+			return false
+		}
+	}
+	return p.accepts(states)
+}
+
+// Search calls f with every word in the KWG that matches the pattern, in
+// alphabetical order. f must not modify the given slice. If f returns an
+// error, the search stops and returns it.
+func (p *Pattern) Search(d *KWG, f func(tilemapping.MachineWord) error) error {
+	word := tilemapping.MachineWord{}
+	var iterate func(nodeIdx uint32, states uint64) error
+	iterate = func(nodeIdx uint32, states uint64) error {
+		for ; ; nodeIdx++ {
+			ml := tilemapping.MachineLetter(d.Tile(nodeIdx))
+			if next := p.step(states, ml); next != 0 {
+				word = append(word, ml)
+				if p.accepts(next) && d.Accepts(nodeIdx) && len(word) > 1 {
+					if err := f(word); err != nil {
+						return err
+					}
+				}
+				if arcIndex := d.ArcIndex(nodeIdx); arcIndex != 0 {
+					if err := iterate(arcIndex, next); err != nil {
+						return err
+					}
+				}
+				word = word[:len(word)-1]
+			}
+			if d.IsEnd(nodeIdx) {
+				return nil
+			}
+		}
+	}
+	if d.ArcIndex(0) == 0 {
+		return nil
+	}
+	return iterate(d.ArcIndex(0), p.start())
+}
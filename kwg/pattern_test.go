@@ -0,0 +1,92 @@
+package kwg
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// wordsKWG builds a KWG with the given words, in the English alphabet.
+func wordsKWG(is *is.I, words ...string) *KWG {
+	dist, err := tilemapping.GetDistribution(&DefaultConfig, "english")
+	is.NoErr(err)
+	mws := make([]tilemapping.MachineWord, len(words))
+	for i, s := range words {
+		mws[i], err = tilemapping.ToMachineWord(s, dist.TileMapping())
+		is.NoErr(err)
+	}
+	return FromWords(dist.TileMapping(), mws)
+}
+
+func TestPatternMatches(t *testing.T) {
+	is := is.New(t)
+	dist, err := tilemapping.GetDistribution(&DefaultConfig, "english")
+	is.NoErr(err)
+	alph := dist.TileMapping()
+
+	testcases := []struct {
+		pattern string
+		word    string
+		matches bool
+	}{
+		{"?A[EIO]*S", "RAINS", true},
+		{"?A[EIO]*S", "RAES", true},
+		{"?A[EIO]*S", "RATS", false},
+		{"?A[EIO]*S", "RAIS", true},
+		{"?A[EIO]*S", "RAISE", false},
+		{"Q[^U]*", "QAT", true},
+		{"Q[^U]*", "QUA", false},
+		{"*ZZ*", "PIZZA", true},
+		{"*ZZ*", "ZZZ", true},
+		{"*ZZ*", "ZAZ", false},
+		{"c*t", "CAT", true},
+		{"**", "QI", true},
+		{"???", "QI", false},
+	}
+	for _, tc := range testcases {
+		p, err := ParsePattern(tc.pattern, alph)
+		is.NoErr(err)
+		word, err := tilemapping.ToMachineWord(tc.word, alph)
+		is.NoErr(err)
+		if p.Matches(word) != tc.matches {
+			t.Errorf("%v against %v: got %v", tc.pattern, tc.word, !tc.matches)
+		}
+	}
+
+	for _, bad := range []string{"", "A[EI", "AE]", "A[]", "A B", "[^]"} {
+		_, err := ParsePattern(bad, alph)
+		is.True(err != nil) // bad
+	}
+}
+
+func TestPatternSearch(t *testing.T) {
+	is := is.New(t)
+	d := wordsKWG(is, "QAT", "QI", "QUA", "QUAT", "ZA")
+	alph := d.GetAlphabet()
+
+	p, err := ParsePattern("Q[^U]*", alph)
+	is.NoErr(err)
+	var words []string
+	err = p.Search(d, func(w tilemapping.MachineWord) error {
+		words = append(words, w.UserVisible(alph))
+		return nil
+	})
+	is.NoErr(err)
+	is.Equal(words, []string{"QAT", "QI"})
+}
+
+func TestHooks(t *testing.T) {
+	is := is.New(t)
+	d := wordsKWG(is, "CAR", "CARE", "CARED", "CARES", "CARET", "SCARE")
+	alph := d.GetAlphabet()
+
+	word, err := tilemapping.ToMachineWord("CARE", alph)
+	is.NoErr(err)
+	is.Equal(tilemapping.MachineWord(FrontHooks(d, word)).UserVisible(alph), "S")
+	is.Equal(tilemapping.MachineWord(BackHooks(d, word)).UserVisible(alph), "DST")
+	word, err = tilemapping.ToMachineWord("ZA", alph)
+	is.NoErr(err)
+	is.Equal(len(FrontHooks(d, word)), 0)
+}
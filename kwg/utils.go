@@ -51,3 +51,33 @@ func findMachineWord(d *KWG, nodeIdx uint32, word tilemapping.MachineWord) bool
 		}
 	}
 }
+
+// FrontHooks returns the letters that make a word when put in front of
+// word, in alphabetical order.
+func FrontHooks(d *KWG, word tilemapping.MachineWord) []tilemapping.MachineLetter {
+	hooks := []tilemapping.MachineLetter{}
+	hooked := make(tilemapping.MachineWord, len(word)+1)
+	copy(hooked[1:], word)
+	for ml := tilemapping.MachineLetter(1); ml < tilemapping.MachineLetter(d.GetAlphabet().NumLetters()); ml++ {
+		hooked[0] = ml
+		if FindMachineWord(d, hooked) {
+			hooks = append(hooks, ml)
+		}
+	}
+	return hooks
+}
+
+// BackHooks returns the letters that make a word when put after word, in
+// alphabetical order.
+func BackHooks(d *KWG, word tilemapping.MachineWord) []tilemapping.MachineLetter {
+	hooks := []tilemapping.MachineLetter{}
+	hooked := make(tilemapping.MachineWord, len(word)+1)
+	copy(hooked, word)
+	for ml := tilemapping.MachineLetter(1); ml < tilemapping.MachineLetter(d.GetAlphabet().NumLetters()); ml++ {
+		hooked[len(word)] = ml
+		if FindMachineWord(d, hooked) {
+			hooks = append(hooks, ml)
+		}
+	}
+	return hooks
+}
@@ -109,10 +109,10 @@ func (sp *SimmedPlay) Ignore() {
 	sp.Unlock()
 }
 
-func (sp *SimmedPlay) addScoreStat(play *move.Move, ply int) {
+func (sp *SimmedPlay) addScoreStat(play *move.Move, bingo bool, ply int) {
 	// log.Debug().Msgf("Adding a stat for %v (pidx %v ply %v)", play, pidx, ply)
 	var bingos int
-	if play.BingoPlayed() {
+	if bingo {
 		bingos = 1
 	}
 	sp.Lock()
@@ -185,6 +185,21 @@ func (s *SimmedPlay) Move() *move.Move {
 	return s.play
 }
 
+// WinProb returns the estimated probability, from 0 to 1, that the play
+// wins the game.
+func (s *SimmedPlay) WinProb() float64 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.winPctStats.Mean()
+}
+
+// EquityMean returns the mean equity of the play over all iterations so far.
+func (s *SimmedPlay) EquityMean() float64 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.equityStats.Mean()
+}
+
 // Simmer implements the actual look-ahead search
 type Simmer struct {
 	origGame *game.Game
@@ -211,6 +226,7 @@ type Simmer struct {
 
 	logStream         io.Writer
 	stoppingCondition StoppingCondition
+	maxIterations     int
 
 	// See rangefinder.
 	inferences    [][]tilemapping.MachineLetter
@@ -221,6 +237,7 @@ func (s *Simmer) Init(game *game.Game, eqCalcs []equity.EquityCalculator,
 	leaves equity.Leaves, cfg *config.Config) {
 	s.origGame = game
 	s.stoppingCondition = StopNone
+	s.maxIterations = 0
 	s.equityCalculators = eqCalcs
 	s.leaveValues = leaves
 	s.threads = int(math.Max(1, float64(runtime.NumCPU()-1)))
@@ -247,6 +264,12 @@ func (s *Simmer) SetStoppingCondition(sc StoppingCondition) {
 	s.stoppingCondition = sc
 }
 
+// SetMaxIterations stops the sim once it has started this many iterations.
+// Zero means no limit.
+func (s *Simmer) SetMaxIterations(n int) {
+	s.maxIterations = n
+}
+
 func (s *Simmer) SetThreads(threads int) {
 	s.threads = threads
 }
@@ -315,6 +338,9 @@ func (s *Simmer) Reset() {
 
 // PrepareSim resets all the stats before a simulation.
 func (s *Simmer) PrepareSim(plies int, plays []*move.Move) error {
+	if s.origGame.NumPlayers() != 2 {
+		return game.ErrNotTwoPlayerGame
+	}
 	err := s.makeGameCopies()
 	if err != nil {
 		return err
@@ -416,6 +442,9 @@ func (s *Simmer) Simulate(ctx context.Context) error {
 					log.Err(err).Msg("error simming iteration; canceling")
 					cancel()
 				}
+				if s.maxIterations > 0 && iterNum >= s.maxIterations {
+					cancel()
+				}
 				select {
 				case v := <-syncExitChan:
 					log.Debug().Msgf("Thread %v got sync msg %v", t, v)
@@ -565,7 +594,7 @@ func (s *Simmer) simSingleIteration(plies, thread, iterationCount int, logChan c
 			}
 			// Maybe these add{X}Stat functions can instead write them to
 			// a channel to avoid mutices
-			simmedPlay.addScoreStat(bestPlay, ply)
+			simmedPlay.addScoreStat(bestPlay, g.IsBingo(bestPlay), ply)
 
 		}
 		// log.Debug().Msgf("Spread for initial player: %v, leftover: %v",
@@ -30,7 +30,7 @@ var DefaultConfig = config.DefaultConfig()
 
 func defaultSimCalculators(lexiconName string) ([]equity.EquityCalculator, equity.EquityCalculator) {
 	c, err := equity.NewCombinedStaticCalculator(
-		lexiconName, &DefaultConfig, "", equity.PEGAdjustmentFilename)
+		lexiconName, &DefaultConfig, "", equity.PEGAdjustmentFilename, game.RackTileLimit)
 	if err != nil {
 		panic(err)
 	}
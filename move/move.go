@@ -235,6 +235,20 @@ func (m *Move) ShortDescription() string {
 	return fmt.Sprint("UNHANDLED")
 }
 
+// Notation returns the move as written in GCG files: the coordinates and
+// word of a tile play, with a . for every tile played through, such as
+// "8D ST.IR"; "-" followed by the tiles for an exchange, such as "-QV";
+// and "-" for a pass.
+func (m *Move) Notation() string {
+	switch m.action {
+	case MoveTypePlay:
+		return m.BoardCoords() + " " + m.TilesString()
+	case MoveTypeExchange:
+		return "-" + m.TilesStringExchange()
+	}
+	return "-"
+}
+
 // FullRack returns the entire rack that the move was made from. This
 // can be calculated from the tiles it uses and the leave.
 func (m *Move) FullRack() string {
@@ -273,10 +287,6 @@ func (m *Move) TilesPlayed() int {
 	return m.tilesPlayed
 }
 
-func (m *Move) BingoPlayed() bool {
-	return (m.action == MoveTypePlay) && (m.tilesPlayed == 7)
-}
-
 // NewScoringMove creates a scoring *Move and returns it.
 func NewScoringMove(score int, tiles tilemapping.MachineWord,
 	leave tilemapping.MachineWord, vertical bool, tilesPlayed int,
@@ -90,8 +90,8 @@ func NewGordonGenerator(gd gaddag.WordGraph, board *board.GameBoard,
 		sortingParameter:   SortByScore,
 		letterDistribution: ld,
 		strip:              make([]tilemapping.MachineLetter, board.Dim()),
-		exchangestrip:      make([]tilemapping.MachineLetter, 7), // max rack size. can make a parameter later.
-		leavestrip:         make([]tilemapping.MachineLetter, 7),
+		exchangestrip:      make([]tilemapping.MachineLetter, game.MaxRackSize),
+		leavestrip:         make([]tilemapping.MachineLetter, game.MaxRackSize),
 		playRecorder:       AllPlaysRecorder,
 		winner:             new(move.Move),
 		placeholder:        new(move.Move),
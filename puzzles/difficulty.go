@@ -0,0 +1,115 @@
+package puzzles
+
+import (
+	"math"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/game"
+	"github.com/domino14/macondo/move"
+)
+
+const (
+	// CompetitiveMargin is how close in equity, or in spread for endgames,
+	// a move must be to the answer to compete with it.
+	CompetitiveMargin = 3.5
+	// rareLetterCount is the most tiles a letter can have in the bag to
+	// count as rare, such as J, Q, X and Z in English.
+	rareLetterCount = 2
+)
+
+// The weights of the parts of a puzzle's difficulty. They add up to 1.
+const (
+	findabilityWeight  = 0.3
+	lengthWeight       = 0.15
+	rarityWeight       = 0.1
+	alternativesWeight = 0.15
+	gapWeight          = 0.15
+	overlapWeight      = 0.15
+)
+
+// difficultyFeatures are what makes a puzzle hard.
+type difficultyFeatures struct {
+	// findability is that of the longest word the answer forms, from 0 to
+	// 1.
+	findability float64
+	// wordLength is the length of the longest word the answer forms.
+	wordLength int
+	// rareLetters is how many letters of that word are rare.
+	rareLetters int
+	// alternatives is how many other moves compete with the answer.
+	alternatives int
+	// gap is the equity, or endgame spread, the answer is ahead of the
+	// next best move by.
+	gap float64
+	// wordsFormed and playedThrough count the words the answer forms and
+	// the tiles on the board it plays through: parallel and overlap plays
+	// are harder to see.
+	wordsFormed   int
+	playedThrough int
+}
+
+// answerFeatures finds the features of the answer in the position in g,
+// where others are the values of the other moves, in equity or endgame
+// spread, against the answer's best.
+func answerFeatures(g *game.Game, answer *move.Move, best float64, others []float64) difficultyFeatures {
+	f := difficultyFeatures{findability: 1, gap: math.Inf(1)}
+	for _, v := range others {
+		if best-v <= CompetitiveMargin {
+			f.alternatives++
+		}
+		if best-v < f.gap {
+			f.gap = best - v
+		}
+	}
+	if answer.Action() != move.MoveTypePlay {
+		return f
+	}
+	words, err := g.Board().FormedWords(answer)
+	if err != nil {
+		return f
+	}
+	dist := g.Bag().LetterDistribution()
+	f.wordsFormed = len(words)
+	f.playedThrough = len(answer.Tiles()) - answer.TilesPlayed()
+	for _, w := range words {
+		if len(w) > f.wordLength {
+			f.wordLength = len(w)
+			f.findability = bot.Findability(dist, w)
+			f.rareLetters = 0
+			for _, ml := range w {
+				if dist.Distribution()[ml] <= rareLetterCount {
+					f.rareLetters++
+				}
+			}
+		}
+	}
+	return f
+}
+
+// difficulty rates the features from 0, the easiest, to 100, the hardest.
+func difficulty(f difficultyFeatures) float64 {
+	clamp := func(x float64) float64 {
+		return math.Max(0, math.Min(1, x))
+	}
+	d := findabilityWeight * (1 - f.findability)
+	// Two-letter words are the easiest to see, and ten or more letters are
+	// as hard as it gets.
+	d += lengthWeight * clamp(float64(f.wordLength-2)/8)
+	d += rarityWeight * clamp(float64(f.rareLetters)/2)
+	d += alternativesWeight * clamp(float64(f.alternatives)/5)
+	// A move far ahead of the rest stands out. With no other moves to
+	// compare with, the gap is infinite.
+	d += gapWeight * (1 - clamp(f.gap/30))
+	d += overlapWeight * clamp(float64(f.wordsFormed-1)/3+float64(f.playedThrough)/6)
+	return math.Round(1000*d) / 10
+}
+
+// staticDifficulty rates a puzzle whose answer is the first of moves, which
+// are sorted by equity.
+func staticDifficulty(g *game.Game, moves []*move.Move) float64 {
+	others := make([]float64, 0, len(moves)-1)
+	for _, m := range moves[1:] {
+		others = append(others, m.Equity())
+	}
+	return difficulty(answerFeatures(g, moves[0], moves[0].Equity(), others))
+}
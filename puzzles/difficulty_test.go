@@ -0,0 +1,60 @@
+package puzzles
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+func TestDifficulty(t *testing.T) {
+	is := is.New(t)
+	easiest := difficultyFeatures{findability: 1, wordLength: 2, gap: math.Inf(1), wordsFormed: 1}
+	is.Equal(difficulty(easiest), 0.0)
+	hardest := difficultyFeatures{wordLength: 10, rareLetters: 2, alternatives: 5, wordsFormed: 4}
+	is.Equal(difficulty(hardest), 100.0)
+
+	// Competition makes a puzzle harder.
+	contested := easiest
+	contested.alternatives = 2
+	contested.gap = 1
+	is.True(difficulty(contested) > difficulty(easiest))
+}
+
+func TestAnswerFeatures(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, game.VarClassic)
+	is.NoErr(err)
+	g, err := game.NewGame(rules, []*pb.PlayerInfo{
+		{Nickname: "p1", RealName: "Player 1"},
+		{Nickname: "p2", RealName: "Player 2"},
+	})
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	is.NoErr(g.SetRackFor(0, tilemapping.RackFromString("?EINRST", g.Alphabet())))
+	m, err := g.CreateAndScorePlacementMove("8D", "STaIR", "?EINRST")
+	is.NoErr(err)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.NoErr(g.SetRackFor(1, tilemapping.RackFromString("AEGLNOT", g.Alphabet())))
+
+	through, err := g.CreateAndScorePlacementMove("H6", "TO.", "AEGLNOT")
+	is.NoErr(err)
+	f := answerFeatures(g, through, 10, []float64{8, 0})
+	is.Equal(f, difficultyFeatures{findability: 1, wordLength: 3, alternatives: 1, gap: 2,
+		wordsFormed: 1, playedThrough: 1})
+
+	parallel, err := g.CreateAndScorePlacementMove("7G", "TO", "AEGLNOT")
+	is.NoErr(err)
+	f = answerFeatures(g, parallel, 10, nil)
+	is.Equal(f.wordsFormed, 3)
+	is.Equal(f.playedThrough, 0)
+	is.Equal(f.gap, math.Inf(1))
+	is.True(difficulty(f) > difficulty(answerFeatures(g, through, 10, nil)))
+}
@@ -0,0 +1,183 @@
+package puzzles
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/endgame/alphabeta"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/movegen"
+)
+
+const (
+	// DefaultEndgamePlies is how many plies deep endgames are solved, unless
+	// the request says otherwise.
+	DefaultEndgamePlies = 6
+	// EndgameMargin is how much more spread the answer to an endgame puzzle
+	// must win by than every other move, unless it is the only winning move.
+	EndgameMargin = 10
+	// DefaultEndgameTimeLimit is how long to spend solving a position and
+	// all its first moves before giving up on it, unless the request says
+	// otherwise.
+	DefaultEndgameTimeLimit = 30 * time.Second
+)
+
+// endgameSolution is the solution of an endgame puzzle.
+type endgameSolution struct {
+	tags []pb.PuzzleTag
+	// pv is the principal variation, starting with the answer.
+	pv         []*move.Move
+	difficulty float64
+}
+
+// endgamePuzzle solves the endgame in g, for the player on turn, and
+// returns its solution if the position makes a puzzle, or nil.
+//
+// The answer must win, and every other first move must lose, or win by at
+// least EndgameMargin less. Every first move is solved to check this, so
+// this is much slower than a single solve. If ctx is done before all the
+// solves are, the position doesn't make a puzzle.
+func endgamePuzzle(ctx context.Context, conf *config.Config, g *game.Game, plies int) (*endgameSolution, error) {
+	if plies <= 0 {
+		plies = DefaultEndgamePlies
+	}
+	gd, err := kwg.Get(conf, g.LexiconName())
+	if err != nil {
+		return nil, err
+	}
+	ld := g.Rules().LetterDistribution()
+	// timedOut gives up on the position, as the solves were cut short and
+	// can't be trusted.
+	timedOut := func() (*endgameSolution, error) {
+		log.Info().Str("gid", g.Uid()).Int("turn", g.Turn()).Msg("endgame-puzzle-timed-out")
+		return nil, nil
+	}
+
+	// solve returns the final spread for the player on turn in pos, and the
+	// best sequence.
+	solve := func(pos *game.Game, plies int) (int, []*move.Move, error) {
+		gameCopy := pos.Copy()
+		gameCopy.SetBackupMode(game.SimulationMode)
+		gameCopy.SetStateStackLength(plies)
+		spread := gameCopy.CurrentSpread()
+		solver := &alphabeta.Solver{}
+		err := solver.Init(movegen.NewGordonGenerator(gd, gameCopy.Board(), ld),
+			movegen.NewGordonGenerator(gd, gameCopy.Board(), ld), gameCopy, conf)
+		if err != nil {
+			return 0, nil, err
+		}
+		v, seq, err := solver.Solve(ctx, plies)
+		if err != nil {
+			return 0, nil, err
+		}
+		return spread + int(math.Round(float64(v))), seq, nil
+	}
+
+	best, pv, err := solve(g, plies)
+	if ctx.Err() != nil {
+		return timedOut()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if best <= 0 || len(pv) == 0 {
+		return nil, nil
+	}
+
+	onturn := g.PlayerOnTurn()
+	rack := g.RackFor(onturn)
+	gen := movegen.NewGordonGenerator(gd, g.Board(), ld)
+	firstMoves := gen.GenAll(rack, false)
+	if firstMoves[0].Action() != move.MoveTypePass {
+		firstMoves = append(firstMoves, move.NewPassMove(rack.TilesOn(), g.Alphabet()))
+	}
+	onlyWin := true
+	topScore := 0
+	others := []float64{}
+	for _, m := range firstMoves {
+		if m.Score() > topScore {
+			topScore = m.Score()
+		}
+		if m.Equals(pv[0], false, true) {
+			continue
+		}
+		gameCopy := g.Copy()
+		gameCopy.SetBackupMode(game.SimulationMode)
+		gameCopy.SetStateStackLength(1)
+		if err := gameCopy.PlayMove(m, false, 0); err != nil {
+			return nil, err
+		}
+		var spread int
+		if gameCopy.Playing() != pb.PlayState_PLAYING || plies == 1 {
+			spread = gameCopy.SpreadFor(onturn)
+		} else {
+			oppSpread, _, err := solve(gameCopy, plies-1)
+			if ctx.Err() != nil {
+				return timedOut()
+			}
+			if err != nil {
+				return nil, err
+			}
+			spread = -oppSpread
+		}
+		if spread > 0 {
+			onlyWin = false
+			if spread > best-EndgameMargin {
+				return nil, nil
+			}
+		}
+		others = append(others, float64(spread))
+	}
+	if ctx.Err() != nil {
+		return timedOut()
+	}
+	return &endgameSolution{
+		tags:       endgameTags(int(rack.NumTiles()), pv, topScore, onlyWin),
+		pv:         pv,
+		difficulty: difficulty(answerFeatures(g, pv[0], float64(best), others)),
+	}, nil
+}
+
+// endgameTags returns the tags of an endgame puzzle with a principal
+// variation pv, where the player on turn has rackSize tiles and the
+// highest-scoring first move scores topScore.
+func endgameTags(rackSize int, pv []*move.Move, topScore int, onlyWin bool) []pb.PuzzleTag {
+	tags := []pb.PuzzleTag{pb.PuzzleTag_ENDGAME_WIN}
+	if onlyWin {
+		tags = append(tags, pb.PuzzleTag_ENDGAME_ONLY_WINNING_MOVE)
+	}
+	if len(pv) < 3 {
+		return tags
+	}
+	answer, next := pv[0], pv[2]
+	if answer.TilesPlayed() < rackSize && answer.TilesPlayed()+next.TilesPlayed() == rackSize {
+		tags = append(tags, pb.PuzzleTag_OUT_IN_TWO)
+	}
+	if answer.Score() < topScore && next.Score() > topScore {
+		tags = append(tags, pb.PuzzleTag_SETUP)
+	}
+	return tags
+}
+
+// variationEvents returns the events of the moves in pv, played in turn
+// from the position in g.
+func variationEvents(g *game.Game, pv []*move.Move) ([]*pb.GameEvent, error) {
+	gameCopy := g.Copy()
+	gameCopy.SetBackupMode(game.SimulationMode)
+	gameCopy.SetStateStackLength(len(pv))
+	evts := []*pb.GameEvent{}
+	for _, m := range pv {
+		evts = append(evts, gameCopy.EventFromMove(m))
+		if err := gameCopy.PlayMove(m, false, 0); err != nil {
+			return nil, err
+		}
+	}
+	return evts, nil
+}
@@ -0,0 +1,86 @@
+package puzzles
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/cgp"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+func TestEndgameTags(t *testing.T) {
+	is := is.New(t)
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	alph := ld.TileMapping()
+
+	// The player on turn has DEIQ.
+	setup := move.NewScoringMoveSimple(6, "H8", "DE", "IQ", alph)
+	block := move.NewScoringMoveSimple(12, "A1", "ZA", "", alph)
+	out := move.NewScoringMoveSimple(45, "J9", "QI", "", alph)
+	pass := move.NewPassMove(nil, alph)
+
+	is.Equal(endgameTags(4, []*move.Move{setup, block, out}, 30, false), []pb.PuzzleTag{
+		pb.PuzzleTag_ENDGAME_WIN, pb.PuzzleTag_OUT_IN_TWO, pb.PuzzleTag_SETUP})
+	// Going out in one is not going out in two, and the second move only
+	// sets up if it outscores every first move.
+	is.Equal(endgameTags(2, []*move.Move{out}, 45, true), []pb.PuzzleTag{
+		pb.PuzzleTag_ENDGAME_WIN, pb.PuzzleTag_ENDGAME_ONLY_WINNING_MOVE})
+	is.Equal(endgameTags(4, []*move.Move{setup, block, out}, 50, true), []pb.PuzzleTag{
+		pb.PuzzleTag_ENDGAME_WIN, pb.PuzzleTag_ENDGAME_ONLY_WINNING_MOVE, pb.PuzzleTag_OUT_IN_TWO})
+	// Passing first and going out after is out in two, but not a setup
+	// unless going out scores more than any first move.
+	is.Equal(endgameTags(2, []*move.Move{pass, block, out}, 45, false), []pb.PuzzleTag{
+		pb.PuzzleTag_ENDGAME_WIN, pb.PuzzleTag_OUT_IN_TWO})
+}
+
+func TestEndgamePuzzleTimedOut(t *testing.T) {
+	is := is.New(t)
+	// This endgame takes 25 plies to solve, far more than a millisecond.
+	pos := "14C/13QI/12FIE/10VEE1R/9KIT2G/8CIG1IDE/8UTA2AS/7ST1SYPh1/6JA5A1/5WOLD2BOBA/3PLOT1R1NU1EX/Y1VEIN1NOR1mOA1/UT1AT1N1L2FEH1/GUR2WIRER5/SNEEZED8 ADENOOO/AHIILMM 353/236 0 lex CSW19;"
+	g, err := cgp.ParseCGP(&DefaultConfig, pos)
+	is.NoErr(err)
+	g.RecalculateBoard()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	// Running out of time isn't an error; the position just doesn't make
+	// a puzzle.
+	sol, err := endgamePuzzle(ctx, &DefaultConfig, g, 25)
+	is.NoErr(err)
+	is.True(sol == nil)
+}
+
+func TestMayFitBucket(t *testing.T) {
+	is := is.New(t)
+	always := []pb.PuzzleTag{pb.PuzzleTag_ENDGAME_WIN}
+	maybe := []pb.PuzzleTag{pb.PuzzleTag_ENDGAME_ONLY_WINNING_MOVE, pb.PuzzleTag_OUT_IN_TWO, pb.PuzzleTag_SETUP}
+
+	bingos := &pb.PuzzleBucket{Includes: []pb.PuzzleTag{pb.PuzzleTag_BINGO}}
+	noEndgames := &pb.PuzzleBucket{Excludes: []pb.PuzzleTag{pb.PuzzleTag_ENDGAME_WIN}}
+	is.True(!mayFitBucket(always, maybe, []*pb.PuzzleBucket{bingos, noEndgames}))
+
+	// A bucket can include tags that only some endgames have, and exclude
+	// others.
+	setups := &pb.PuzzleBucket{
+		Includes: []pb.PuzzleTag{pb.PuzzleTag_SETUP, pb.PuzzleTag_OUT_IN_TWO},
+		Excludes: []pb.PuzzleTag{pb.PuzzleTag_ENDGAME_ONLY_WINNING_MOVE},
+	}
+	is.True(mayFitBucket(always, maybe, []*pb.PuzzleBucket{bingos, setups}))
+	is.True(mayFitBucket(always, maybe, []*pb.PuzzleBucket{{}}))
+}
+
+func TestNegativeEndgameSeconds(t *testing.T) {
+	is := is.New(t)
+	err := InitializePuzzleGenerationRequest(&pb.PuzzleGenerationRequest{
+		Buckets:        []*pb.PuzzleBucket{{}},
+		Endgames:       true,
+		EndgameSeconds: -1,
+	})
+	is.Equal(err.Error(), "endgame seconds are negative in puzzle generation request")
+}
@@ -1,10 +1,12 @@
 package puzzles
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"time"
 
 	"github.com/domino14/macondo/ai/turnplayer"
 	"github.com/domino14/macondo/config"
@@ -40,12 +42,21 @@ func CreatePuzzlesFromGame(conf *config.Config, eqLossLimit int, g *game.Game, r
 		return nil, err
 	}
 	totalEquityLoss := 0.0
-	puzzleCalc, err := equity.NewCombinedStaticCalculator(g.LexiconName(), conf, "", "")
+	puzzleCalc, err := equity.NewCombinedStaticCalculator(g.LexiconName(), conf, "", "", g.RackSize())
 	if err != nil {
 		return nil, err
 	}
 
 	eqCalcs := []equity.EquityCalculator{puzzleCalc}
+	// Solving is slow, so don't solve endgames that could never make a
+	// puzzle for any of the buckets.
+	endgames := req.Endgames && mayFitBucket([]pb.PuzzleTag{pb.PuzzleTag_ENDGAME_WIN},
+		[]pb.PuzzleTag{pb.PuzzleTag_ENDGAME_ONLY_WINNING_MOVE, pb.PuzzleTag_OUT_IN_TWO, pb.PuzzleTag_SETUP},
+		req.Buckets)
+	endgameTimeLimit := DefaultEndgameTimeLimit
+	if req.EndgameSeconds > 0 {
+		endgameTimeLimit = time.Duration(req.EndgameSeconds) * time.Second
+	}
 	for evtIdx, evt := range evts {
 		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE &&
 			evt.Type != pb.GameEvent_EXCHANGE &&
@@ -56,8 +67,39 @@ func CreatePuzzlesFromGame(conf *config.Config, eqLossLimit int, g *game.Game, r
 		if err != nil {
 			return nil, err
 		}
-		// Don't create endgame puzzles for now
-		if g.Bag().TilesRemaining() < 7 {
+		if g.Bag().TilesRemaining() == 0 {
+			if !endgames || g.Playing() != pb.PlayState_PLAYING {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), endgameTimeLimit)
+			sol, err := endgamePuzzle(ctx, conf, g, int(req.EndgamePlies))
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+			if sol == nil {
+				continue
+			}
+			if bucketIndex, ok := puzzleBucket(sol.tags, req.Buckets); ok {
+				variation, err := variationEvents(g, sol.pv)
+				if err != nil {
+					return nil, err
+				}
+				puzzles = append(puzzles, &pb.PuzzleCreationResponse{
+					GameId:             g.Uid(),
+					TurnNumber:         int32(evtIdx),
+					Answer:             variation[0],
+					BucketIndex:        bucketIndex,
+					Tags:               sol.tags,
+					PrincipalVariation: variation,
+					Difficulty:         sol.difficulty})
+			}
+			continue
+		}
+		// Static equity is a poor guide this close to the end, so
+		// pre-endgame puzzles are only kept if their answer wins a sim.
+		preEndgame := g.Bag().TilesRemaining() < g.RackSize()
+		if preEndgame && !req.Endgames {
 			continue
 		}
 
@@ -89,17 +131,33 @@ func CreatePuzzlesFromGame(conf *config.Config, eqLossLimit int, g *game.Game, r
 				tags = append(tags, tag)
 			}
 		}
-		for _, bucket := range req.Buckets {
-			if tagsFitInBucket(tags, bucket) {
-				puzzles = append(puzzles, &pb.PuzzleCreationResponse{
-					GameId:      g.Uid(),
-					TurnNumber:  int32(evtIdx),
-					Answer:      g.EventFromMove(moves[0]),
-					BucketIndex: int32(bucket.Index),
-					Tags:        tags})
-				break
+		if preEndgame {
+			tags = append(tags, pb.PuzzleTag_PRE_ENDGAME)
+		}
+		bucketIndex, ok := puzzleBucket(tags, req.Buckets)
+		if !ok {
+			continue
+		}
+		var simStats *pb.PuzzleSimStats
+		if req.SimVerify || preEndgame {
+			simStats, err = simPuzzle(conf, g, moves, puzzleCalc, req)
+			if err != nil {
+				return nil, err
+			}
+			if !simVerified(simStats, moves[0].Notation(), req.SimMargin) {
+				log.Info().Str("gid", g.Uid()).Int("turn", evtIdx).Str("answer", moves[0].Notation()).
+					Str("sim-winner", simStats.Plays[0].Play).Msg("puzzle-not-verified-by-sim")
+				continue
 			}
 		}
+		puzzles = append(puzzles, &pb.PuzzleCreationResponse{
+			GameId:      g.Uid(),
+			TurnNumber:  int32(evtIdx),
+			Answer:      g.EventFromMove(moves[0]),
+			BucketIndex: bucketIndex,
+			Tags:        tags,
+			SimStats:    simStats,
+			Difficulty:  staticDifficulty(g, moves)})
 	}
 	return puzzles, nil
 }
@@ -121,16 +179,16 @@ func EquityPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 
 func BingoPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 	m := moves[0]
-	return moveIsBingo(m), pb.PuzzleTag_BINGO
+	return moveIsBingo(g, m), pb.PuzzleTag_BINGO
 }
 
 func OnlyBingoPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 	tag := pb.PuzzleTag_ONLY_BINGO
-	if len(moves) == 0 || !moveIsBingo(moves[0]) {
+	if len(moves) == 0 || !moveIsBingo(g, moves[0]) {
 		return false, tag
 	}
 	for _, m := range moves[1:] {
-		if moveIsBingo(m) && m.Action() == move.MoveTypePlay {
+		if moveIsBingo(g, m) {
 			return false, tag
 		}
 	}
@@ -139,11 +197,11 @@ func OnlyBingoPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 
 func BlankBingoPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 	m := moves[0]
-	return moveIsBingo(m) && moveContainsBlank(m), pb.PuzzleTag_BLANK_BINGO
+	return moveIsBingo(g, m) && moveContainsBlank(m), pb.PuzzleTag_BLANK_BINGO
 }
 
 func NonBingoPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
-	return !moveIsBingo(moves[0]), pb.PuzzleTag_NON_BINGO
+	return !moveIsBingo(g, moves[0]), pb.PuzzleTag_NON_BINGO
 }
 
 // XXX: Must be expanded to other languages
@@ -159,7 +217,7 @@ func PowerTilePuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 
 func BingoNineOrAbovePuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 	m := moves[0]
-	return moveIsBingo(m) && moveLength(m) >= 9, pb.PuzzleTag_BINGO_NINE_OR_ABOVE
+	return moveIsBingo(g, m) && moveLength(m) >= 9, pb.PuzzleTag_BINGO_NINE_OR_ABOVE
 }
 
 func CELOnlyPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
@@ -179,6 +237,37 @@ func CELOnlyPuzzle(g *game.Game, moves []*move.Move) (bool, pb.PuzzleTag) {
 	return isCEL, pb.PuzzleTag_CEL_ONLY
 }
 
+// puzzleBucket returns the index of the first bucket the tags fit in.
+func puzzleBucket(tags []pb.PuzzleTag, buckets []*pb.PuzzleBucket) (int32, bool) {
+	for _, bucket := range buckets {
+		if tagsFitInBucket(tags, bucket) {
+			return bucket.Index, true
+		}
+	}
+	return 0, false
+}
+
+// mayFitBucket returns whether a puzzle tagged with always, and with any of
+// maybe, could fit in one of the buckets.
+func mayFitBucket(always, maybe []pb.PuzzleTag, buckets []*pb.PuzzleBucket) bool {
+	for _, bucket := range buckets {
+		// The puzzle most likely to fit has every tag of maybe that the
+		// bucket includes, and none that it doesn't.
+		tags := append([]pb.PuzzleTag{}, always...)
+		for _, tag := range bucket.Includes {
+			for _, m := range maybe {
+				if tag == m {
+					tags = append(tags, tag)
+				}
+			}
+		}
+		if tagsFitInBucket(tags, bucket) {
+			return true
+		}
+	}
+	return false
+}
+
 func tagsFitInBucket(tags []pb.PuzzleTag, bucket *pb.PuzzleBucket) bool {
 	tagMap := map[pb.PuzzleTag]bool{}
 	for _, tag := range tags {
@@ -211,6 +300,12 @@ func validatePuzzleGenerationRequest(req *pb.PuzzleGenerationRequest) error {
 	if req.Buckets == nil {
 		return errors.New("buckets are nil in puzzle generation request")
 	}
+	if req.EndgameSeconds < 0 {
+		return errors.New("endgame seconds are negative in puzzle generation request")
+	}
+	if req.SimMargin < 0 {
+		return errors.New("sim margin is negative in puzzle generation request")
+	}
 	bucketEncryptions := map[string]bool{}
 	numberOfTags := len(pb.PuzzleTag_name)
 	for idx, bucket := range req.Buckets {
@@ -259,8 +354,8 @@ func moveLength(m *move.Move) int {
 	return len(m.Tiles())
 }
 
-func moveIsBingo(m *move.Move) bool {
-	return m.TilesPlayed() == game.RackTileLimit
+func moveIsBingo(g *game.Game, m *move.Move) bool {
+	return g.IsBingo(m)
 }
 
 func moveContainsBlank(m *move.Move) bool {
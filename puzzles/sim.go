@@ -0,0 +1,70 @@
+package puzzles
+
+import (
+	"context"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/equity"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/montecarlo"
+	"github.com/domino14/macondo/move"
+)
+
+// Defaults for verifying puzzles by simming.
+const (
+	DefaultSimPlies      = 2
+	DefaultSimCandidates = 10
+	DefaultSimIterations = 1000
+	// PreEndgameSimPlies is the fewest plies pre-endgame positions are
+	// simmed for, so that most sims play the game out.
+	PreEndgameSimPlies = 8
+)
+
+func orDefault(n int32, def int) int {
+	if n > 0 {
+		return int(n)
+	}
+	return def
+}
+
+// simPuzzle sims the best moves, by static equity, in the position in g.
+func simPuzzle(conf *config.Config, g *game.Game, moves []*move.Move, calc *equity.CombinedStaticCalculator,
+	req *pb.PuzzleGenerationRequest) (*pb.PuzzleSimStats, error) {
+
+	ncands := orDefault(req.SimCandidates, DefaultSimCandidates)
+	if ncands > len(moves) {
+		ncands = len(moves)
+	}
+	plies := orDefault(req.SimPlies, DefaultSimPlies)
+	if g.Bag().TilesRemaining() < g.RackSize() && plies < PreEndgameSimPlies {
+		plies = PreEndgameSimPlies
+	}
+	simmer := &montecarlo.Simmer{}
+	simmer.Init(g, []equity.EquityCalculator{calc}, calc, conf)
+	simmer.SetMaxIterations(orDefault(req.SimIterations, DefaultSimIterations))
+	if err := simmer.PrepareSim(plies, moves[:ncands]); err != nil {
+		return nil, err
+	}
+	if err := simmer.Simulate(context.Background()); err != nil {
+		return nil, err
+	}
+	stats := &pb.PuzzleSimStats{Iterations: int32(simmer.Iterations()), Plies: int32(plies)}
+	for _, sp := range simmer.WinningPlays() {
+		stats.Plays = append(stats.Plays, &pb.SimmedPuzzlePlay{
+			Play:   sp.Move().Notation(),
+			WinPct: 100 * sp.WinProb(),
+			Equity: sp.EquityMean(),
+		})
+	}
+	return stats, nil
+}
+
+// simVerified returns whether the answer won the sim by at least margin
+// win percentage points.
+func simVerified(stats *pb.PuzzleSimStats, answer string, margin float64) bool {
+	if len(stats.Plays) == 0 || stats.Plays[0].Play != answer {
+		return false
+	}
+	return len(stats.Plays) == 1 || stats.Plays[0].WinPct-stats.Plays[1].WinPct >= margin
+}
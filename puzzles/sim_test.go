@@ -0,0 +1,35 @@
+package puzzles
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+func TestSimVerified(t *testing.T) {
+	is := is.New(t)
+	stats := &pb.PuzzleSimStats{Iterations: 1000, Plies: 2, Plays: []*pb.SimmedPuzzlePlay{
+		{Play: "8D STAIR", WinPct: 62.5, Equity: 70},
+		{Play: "8G STAIR", WinPct: 60, Equity: 68},
+	}}
+	is.True(simVerified(stats, "8D STAIR", 0))
+	is.True(simVerified(stats, "8D STAIR", 2.5))
+	is.True(!simVerified(stats, "8D STAIR", 3))
+	// The static winner lost the sim.
+	is.True(!simVerified(stats, "8G STAIR", 0))
+
+	stats.Plays = stats.Plays[:1]
+	is.True(simVerified(stats, "8D STAIR", 10))
+}
+
+func TestNegativeSimMargin(t *testing.T) {
+	is := is.New(t)
+	err := InitializePuzzleGenerationRequest(&pb.PuzzleGenerationRequest{
+		Buckets:   []*pb.PuzzleBucket{{}},
+		SimVerify: true,
+		SimMargin: -1,
+	})
+	is.Equal(err.Error(), "sim margin is negative in puzzle generation request")
+}
@@ -94,6 +94,9 @@ func (r *RangeFinder) PrepareFinder(myRack []tilemapping.MachineLetter) error {
 	if r.origGame.Bag().TilesRemaining() == 0 {
 		return ErrBagEmpty
 	}
+	if r.origGame.NumPlayers() != 2 {
+		return game.ErrNotTwoPlayerGame
+	}
 	oppEvt := evts[len(evts)-1]
 	if oppEvt.Type != macondo.GameEvent_EXCHANGE && oppEvt.Type != macondo.GameEvent_TILE_PLACEMENT_MOVE {
 		log.Info().Str("oppEvtType", oppEvt.Type.String()).Msg("type")
@@ -116,7 +119,7 @@ func (r *RangeFinder) PrepareFinder(myRack []tilemapping.MachineLetter) error {
 		return err
 	}
 
-	if r.lastOppMove.TilesPlayed() == game.RackTileLimit {
+	if r.lastOppMove.TilesPlayed() == gameCopy.RackSize() {
 		return ErrNoInformation
 	}
 	r.lastOppMoveRackTiles = []tilemapping.MachineLetter{}
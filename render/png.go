@@ -0,0 +1,112 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/domino14/macondo/game"
+)
+
+// PNG draws the position of g as a PNG image.
+func PNG(w io.Writer, g *game.Game, opts *Options) error {
+	img, err := layout(g, opts).rasterize()
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// Image draws the position of g.
+func Image(g *game.Game, opts *Options) (image.Image, error) {
+	return layout(g, opts).rasterize()
+}
+
+func (sc *scene) rasterize() (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, sc.w, sc.h))
+	for _, r := range sc.rects {
+		bounds := image.Rect(r.x, r.y, r.x+r.w, r.y+r.h)
+		draw.Draw(img, bounds, image.NewUniform(r.fill), image.Point{}, draw.Over)
+		if r.stroke.A == 0 || r.strokeWidth <= 0 {
+			continue
+		}
+		stroke := image.NewUniform(r.stroke)
+		sw := r.strokeWidth
+		for _, edge := range []image.Rectangle{
+			image.Rect(r.x, r.y, r.x+r.w, r.y+sw),
+			image.Rect(r.x, r.y+r.h-sw, r.x+r.w, r.y+r.h),
+			image.Rect(r.x, r.y+sw, r.x+sw, r.y+r.h-sw),
+			image.Rect(r.x+r.w-sw, r.y+sw, r.x+r.w, r.y+r.h-sw),
+		} {
+			draw.Draw(img, edge, stroke, image.Point{}, draw.Over)
+		}
+	}
+	// Faces are not safe for concurrent use, so each picture makes its own.
+	faces := map[faceKey]font.Face{}
+	for _, t := range sc.texts {
+		key := faceKey{t.size, t.bold}
+		face, ok := faces[key]
+		if !ok {
+			var err error
+			if face, err = newFace(key); err != nil {
+				return nil, err
+			}
+			faces[key] = face
+		}
+		d := &font.Drawer{Dst: img, Src: image.NewUniform(t.fill), Face: face}
+		x := fixed.I(t.x)
+		switch t.anchor {
+		case anchorMiddle:
+			x -= d.MeasureString(t.s) / 2
+		case anchorEnd:
+			x -= d.MeasureString(t.s)
+		}
+		// Center capitals on y, as dominant-baseline="central" roughly does
+		// in the SVG.
+		d.Dot = fixed.Point26_6{X: x, Y: fixed.I(t.y) + face.Metrics().CapHeight/2}
+		d.DrawString(t.s)
+	}
+	return img, nil
+}
+
+type faceKey struct {
+	size int
+	bold bool
+}
+
+var (
+	fontsOnce sync.Once
+	fonts     map[bool]*opentype.Font
+	fontsErr  error
+)
+
+// newFace returns a face of the Go font, with the size in pixels.
+func newFace(key faceKey) (font.Face, error) {
+	fontsOnce.Do(func() {
+		fonts = map[bool]*opentype.Font{}
+		for b, ttf := range map[bool][]byte{false: goregular.TTF, true: gobold.TTF} {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				fontsErr = err
+				return
+			}
+			fonts[b] = f
+		}
+	})
+	if fontsErr != nil {
+		return nil, fontsErr
+	}
+	return opentype.NewFace(fonts[key.bold], &opentype.FaceOptions{
+		Size:    float64(key.size),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
@@ -0,0 +1,327 @@
+// Package render draws game positions as pictures: the board with its bonus
+// squares and tiles, the last move highlighted, the players' racks and
+// scores, and optionally some candidate moves overlaid on the board. It
+// writes SVG, or PNG through a pure-Go rasterizer.
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// DefaultSquareSize is the side of a board square, in pixels.
+const DefaultSquareSize = 36
+
+// maxCandidates is how many candidate moves can be overlaid; there is a
+// color for each.
+const maxCandidates = 5
+
+// Options say what to draw.
+type Options struct {
+	// SquareSize is the side of a board square, in pixels. Everything else
+	// is scaled to it.
+	SquareSize int
+	// Candidates are moves to overlay on the board, numbered in order. Only
+	// tile placements are drawn on the board, and only the first five.
+	Candidates []*move.Move
+	// HideOpponentRacks only shows the rack of the player on turn, as in a
+	// puzzle.
+	HideOpponentRacks bool
+	// NoLastMove leaves the last move unhighlighted.
+	NoLastMove bool
+}
+
+func (o *Options) squareSize() int {
+	if o == nil || o.SquareSize <= 0 {
+		return DefaultSquareSize
+	}
+	return o.SquareSize
+}
+
+var (
+	background     = rgb(0xffffff)
+	gridColor      = rgb(0xc9c2b0)
+	textColor      = rgb(0x222222)
+	labelColor     = rgb(0x777777)
+	tileColor      = rgb(0xf2d39b)
+	tileEdge       = rgb(0xc9a66b)
+	lastMoveColor  = rgb(0xffe066)
+	lastMoveEdge   = rgb(0xd18b00)
+	blankTextColor = rgb(0xc0392b)
+	bonusColors    = map[board.BonusSquare]color.NRGBA{
+		board.NoBonus:  rgb(0xeeeae0),
+		board.Bonus2LS: rgb(0xaad4ee),
+		board.Bonus3LS: rgb(0x3f8fd0),
+		board.Bonus4LS: rgb(0x1d4f91),
+		board.Bonus2WS: rgb(0xf4b6b4),
+		board.Bonus3WS: rgb(0xd9534f),
+		board.Bonus4WS: rgb(0x8b1a1a),
+	}
+	bonusLabels = map[board.BonusSquare]string{
+		board.Bonus2LS: "DL",
+		board.Bonus3LS: "TL",
+		board.Bonus4LS: "QL",
+		board.Bonus2WS: "DW",
+		board.Bonus3WS: "TW",
+		board.Bonus4WS: "QW",
+	}
+	candidateColors = []color.NRGBA{
+		rgb(0x2e86de), rgb(0x27ae60), rgb(0x8e44ad), rgb(0xe67e22), rgb(0x16a085),
+	}
+)
+
+func rgb(c uint32) color.NRGBA {
+	return color.NRGBA{R: uint8(c >> 16), G: uint8(c >> 8), B: uint8(c), A: 0xff}
+}
+
+func withAlpha(c color.NRGBA, a uint8) color.NRGBA {
+	c.A = a
+	return c
+}
+
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// rect is a filled rectangle, with an optional border drawn inside it.
+type rect struct {
+	x, y, w, h  int
+	fill        color.NRGBA
+	stroke      color.NRGBA
+	strokeWidth int
+}
+
+// text is a line of text. y is its vertical center.
+type text struct {
+	x, y   int
+	size   int
+	bold   bool
+	anchor anchor
+	fill   color.NRGBA
+	s      string
+}
+
+// scene is a picture laid out in pixels, which the SVG and PNG writers
+// both draw: rects first, then texts over them.
+type scene struct {
+	w, h  int
+	rects []rect
+	texts []text
+}
+
+func (sc *scene) rect(x, y, w, h int, fill color.NRGBA) {
+	sc.rects = append(sc.rects, rect{x: x, y: y, w: w, h: h, fill: fill})
+}
+
+func (sc *scene) text(x, y, size int, bold bool, a anchor, fill color.NRGBA, s string) {
+	sc.texts = append(sc.texts, text{x: x, y: y, size: size, bold: bold, anchor: a, fill: fill, s: s})
+}
+
+// tile draws a tile at x, y with side s. A blank shows its letter in another
+// color, and no score.
+func (sc *scene) tile(x, y, s int, ml tilemapping.MachineLetter, tm *tilemapping.TileMapping,
+	ld *tilemapping.LetterDistribution, fill, edge color.NRGBA) {
+
+	sc.rects = append(sc.rects, rect{x: x + 1, y: y + 1, w: s - 2, h: s - 2, fill: fill,
+		stroke: edge, strokeWidth: max(1, s/24)})
+	letter := strings.ToUpper(ml.UserVisible(tm, false))
+	if ml == 0 || ml.IsBlanked() {
+		sc.text(x+s/2, y+s/2, s*3/5, true, anchorMiddle, blankTextColor, letter)
+		return
+	}
+	sc.text(x+s/2-s/12, y+s/2, s*3/5, true, anchorMiddle, textColor, letter)
+	sc.text(x+s-s/8, y+s-s/5, s/4, false, anchorEnd, textColor, fmt.Sprint(ld.Score(ml)))
+}
+
+// lastMove returns the squares where the last move placed its tiles, or nil
+// if the last turn was not a tile placement.
+func lastMove(g *game.Game) map[[2]int]bool {
+	events := g.History().GetEvents()
+	idx := g.Turn() - 1
+	if idx >= len(events) {
+		idx = len(events) - 1
+	}
+	if idx >= 0 && events[idx].Type == pb.GameEvent_CHALLENGE_BONUS {
+		idx--
+	}
+	if idx < 0 || events[idx].Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+		return nil
+	}
+	evt := events[idx]
+	tiles, err := tilemapping.ToMachineWord(evt.PlayedTiles, g.Alphabet())
+	if err != nil {
+		return nil
+	}
+	dr, dc := 0, 1
+	if evt.Direction == pb.GameEvent_VERTICAL {
+		dr, dc = 1, 0
+	}
+	squares := map[[2]int]bool{}
+	for i, t := range tiles {
+		if t != 0 {
+			squares[[2]int{int(evt.Row) + i*dr, int(evt.Column) + i*dc}] = true
+		}
+	}
+	return squares
+}
+
+// overlaid is a tile of a candidate move, drawn on an empty square.
+type overlaid struct {
+	candidate int
+	ml        tilemapping.MachineLetter
+}
+
+// candidateSquares returns the squares the candidate tile placements put
+// tiles on. Where candidates overlap, the first one wins.
+func candidateSquares(candidates []*move.Move, dim int) map[[2]int]overlaid {
+	squares := map[[2]int]overlaid{}
+	for i, m := range candidates {
+		if m.Action() != move.MoveTypePlay {
+			continue
+		}
+		row, col, vertical := m.CoordsAndVertical()
+		dr, dc := 0, 1
+		if vertical {
+			dr, dc = 1, 0
+		}
+		for j, t := range m.Tiles() {
+			sq := [2]int{row + j*dr, col + j*dc}
+			if _, ok := squares[sq]; ok || t == 0 || sq[0] >= dim || sq[1] >= dim {
+				continue
+			}
+			squares[sq] = overlaid{candidate: i, ml: t}
+		}
+	}
+	return squares
+}
+
+// layout lays out the picture of the position.
+func layout(g *game.Game, opts *Options) *scene {
+	s := opts.squareSize()
+	bd := g.Board()
+	dim := bd.Dim()
+	tm := g.Alphabet()
+	ld := g.Bag().LetterDistribution()
+
+	band := s * 3 / 4
+	ox, oy := band, band
+	boardSize := dim * s
+	nameWidth := 6 * s
+	sc := &scene{w: max(ox+boardSize+band/2, ox+nameWidth+7*s+band/2)}
+	sc.rect(0, 0, sc.w, 0, background) // the height is set below
+	sc.rect(ox, oy, boardSize, boardSize, gridColor)
+
+	for i := 0; i < dim; i++ {
+		sc.text(ox+i*s+s/2, oy-band/2, s*7/20, false, anchorMiddle, labelColor, string(rune('A'+i)))
+		sc.text(ox-band/2, oy+i*s+s/2, s*7/20, false, anchorMiddle, labelColor, fmt.Sprint(i+1))
+	}
+
+	var last map[[2]int]bool
+	if opts == nil || !opts.NoLastMove {
+		last = lastMove(g)
+	}
+	var candidates []*move.Move
+	if opts != nil {
+		candidates = opts.Candidates
+		if len(candidates) > maxCandidates {
+			candidates = candidates[:maxCandidates]
+		}
+	}
+	overlay := candidateSquares(candidates, dim)
+
+	for r := 0; r < dim; r++ {
+		for c := 0; c < dim; c++ {
+			x, y := ox+c*s, oy+r*s
+			if ml := bd.GetLetter(r, c); ml != 0 {
+				fill, edge := tileColor, tileEdge
+				if last[[2]int{r, c}] {
+					fill, edge = lastMoveColor, lastMoveEdge
+				}
+				sc.tile(x, y, s, ml, tm, ld, fill, edge)
+				continue
+			}
+			bonus := bd.GetBonus(r, c)
+			fill, ok := bonusColors[bonus]
+			if !ok {
+				fill = bonusColors[board.NoBonus]
+			}
+			sc.rect(x+1, y+1, s-2, s-2, fill)
+			if o, ok := overlay[[2]int{r, c}]; ok {
+				sc.rect(x+1, y+1, s-2, s-2, withAlpha(candidateColors[o.candidate], 0xb0))
+				sc.text(x+s/2, y+s/2, s/2, true, anchorMiddle, background,
+					strings.ToUpper(o.ml.UserVisible(tm, false)))
+				sc.text(x+s/8, y+s/5, s/5, true, anchorStart, background, fmt.Sprint(o.candidate+1))
+				continue
+			}
+			if label, ok := bonusLabels[bonus]; ok {
+				labelFill := textColor
+				if bonus == board.Bonus3LS || bonus == board.Bonus4LS || bonus == board.Bonus3WS ||
+					bonus == board.Bonus4WS {
+					labelFill = background
+				}
+				sc.text(x+s/2, y+s/2, s*3/10, false, anchorMiddle, labelFill, label)
+			}
+		}
+	}
+
+	y := oy + boardSize + s/2
+	for i, m := range candidates {
+		col := candidateColors[i]
+		sc.rect(ox, y+s/8, s*3/4, s*3/4, col)
+		sc.text(ox+s*3/8, y+s/2, s*2/5, true, anchorMiddle, background, fmt.Sprint(i+1))
+		sc.text(ox+s, y+s/2, s*2/5, false, anchorStart, textColor,
+			fmt.Sprintf("%s  %d pts  %.1f eq", m.Notation(), m.Score(), m.Equity()))
+		y += s
+	}
+	if len(candidates) > 0 {
+		y += s / 2
+	}
+
+	players := g.History().GetPlayers()
+	for i := 0; i < g.NumPlayers(); i++ {
+		name := fmt.Sprintf("Player %d", i+1)
+		if i < len(players) {
+			name = players[i].Nickname
+		}
+		if len([]rune(name)) > 12 {
+			name = string([]rune(name)[:11]) + "…"
+		}
+		onTurn := g.PlayerOnTurn() == i && g.Playing() != pb.PlayState_GAME_OVER
+		if onTurn {
+			sc.rect(ox, y+s/4, s/6, s/2, lastMoveEdge)
+		}
+		sc.text(ox+s/2, y+s/2, s*2/5, onTurn, anchorStart, textColor, name)
+		sc.text(ox+nameWidth-s/4, y+s/2, s*2/5, true, anchorEnd, textColor, fmt.Sprint(g.PointsFor(i)))
+		if !(opts != nil && opts.HideOpponentRacks && !onTurn) {
+			for j, ml := range g.RackFor(i).TilesOn() {
+				sc.tile(ox+nameWidth+j*s, y, s, ml, tm, ld, tileColor, tileEdge)
+			}
+		}
+		y += s + s/8
+	}
+	sc.text(ox, y+s/2, s*7/20, false, anchorStart, labelColor,
+		fmt.Sprintf("%d tiles in the bag", g.Bag().TilesRemaining()))
+	y += s
+
+	sc.h = y + band/2
+	sc.rects[0].h = sc.h
+	return sc
+}
+
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,134 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/board"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/game"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/move"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// testGame returns a game where p1 opened with 8D STaIR, and <p2> is on
+// turn with AEGLNOT.
+func testGame(is *is.I) *game.Game {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, "", board.CrosswordGameLayout,
+		"english", game.CrossScoreOnly, game.VarClassic)
+	is.NoErr(err)
+	g, err := game.NewGame(rules, []*pb.PlayerInfo{
+		{Nickname: "p1", RealName: "Player 1"},
+		{Nickname: "<p2>", RealName: "Player 2"},
+	})
+	is.NoErr(err)
+	g.StartGame()
+	g.SetPlayerOnTurn(0)
+	is.NoErr(g.SetRackFor(0, tilemapping.RackFromString("?EINRST", g.Alphabet())))
+	m, err := g.CreateAndScorePlacementMove("8D", "STaIR", "?EINRST")
+	is.NoErr(err)
+	is.NoErr(g.PlayMove(m, true, 0))
+	// Set both racks, so that p1 can't draw the other blank.
+	is.NoErr(g.SetRacksForBoth([]*tilemapping.Rack{
+		tilemapping.RackFromString("EENNORU", g.Alphabet()),
+		tilemapping.RackFromString("AEGLNOT", g.Alphabet()),
+	}))
+	return g
+}
+
+func countRects(sc *scene, fill color.NRGBA) int {
+	n := 0
+	for _, r := range sc.rects {
+		if r.fill == fill {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLayout(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+
+	sc := layout(g, nil)
+	is.Equal(countRects(sc, lastMoveColor), 5)
+	// Seven tiles on each rack.
+	is.Equal(countRects(sc, tileColor), 14)
+	blanks := []string{}
+	for _, t := range sc.texts {
+		if t.fill == blankTextColor {
+			blanks = append(blanks, t.s)
+		}
+	}
+	is.Equal(blanks, []string{"A"})
+
+	sc = layout(g, &Options{NoLastMove: true, HideOpponentRacks: true})
+	is.Equal(countRects(sc, lastMoveColor), 0)
+	is.Equal(countRects(sc, tileColor), 5+7)
+}
+
+func TestCandidateSquares(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+	m, err := g.CreateAndScorePlacementMove("H6", "TO.", "AEGLNOT")
+	is.NoErr(err)
+	other, err := g.CreateAndScorePlacementMove("7G", "TO", "AEGLNOT")
+	is.NoErr(err)
+	pass := move.NewPassMove(nil, g.Alphabet())
+
+	squares := candidateSquares([]*move.Move{m, other, pass}, 15)
+	is.Equal(squares, map[[2]int]overlaid{
+		{5, 7}: {candidate: 0, ml: 20}, // T
+		{6, 7}: {candidate: 0, ml: 15}, // O; H7 is also 7G TO's O
+		{6, 6}: {candidate: 1, ml: 20},
+	})
+}
+
+func TestSVG(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+	m, err := g.CreateAndScorePlacementMove("H6", "TO.", "AEGLNOT")
+	is.NoErr(err)
+
+	var buf bytes.Buffer
+	is.NoErr(SVG(&buf, g, &Options{SquareSize: 40, Candidates: []*move.Move{m}}))
+	svg := buf.String()
+	is.True(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="`))
+	is.True(strings.HasSuffix(svg, "</svg>\n"))
+	is.True(strings.Contains(svg, ">TW</text>"))
+	is.True(strings.Contains(svg, ">&lt;p2&gt;</text>"))
+	is.True(!strings.Contains(svg, "<p2>"))
+	is.True(strings.Contains(svg, ">H6 TO.  3 pts  0.0 eq</text>"))
+	is.True(strings.Contains(svg, `fill="#2e86de" fill-opacity="0.69"`))
+}
+
+func TestPNG(t *testing.T) {
+	is := is.New(t)
+	g := testGame(is)
+	sc := layout(g, nil)
+
+	var buf bytes.Buffer
+	is.NoErr(PNG(&buf, g, nil))
+	img, err := png.Decode(&buf)
+	is.NoErr(err)
+	is.Equal(img.Bounds().Dx(), sc.w)
+	is.Equal(img.Bounds().Dy(), sc.h)
+
+	s := DefaultSquareSize
+	ox, oy := s*3/4, s*3/4
+	at := func(x, y int) color.NRGBA {
+		return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+	}
+	// A1 is a triple word score, and 8D is a tile of the last move.
+	is.Equal(at(ox+4, oy+4), bonusColors[board.Bonus3WS])
+	is.Equal(at(ox+3*s+4, oy+7*s+4), lastMoveColor)
+	is.Equal(at(1, 1), background)
+}
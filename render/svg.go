@@ -0,0 +1,63 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/domino14/macondo/game"
+)
+
+// SVG draws the position of g as an SVG image.
+func SVG(w io.Writer, g *game.Game, opts *Options) error {
+	return layout(g, opts).writeSVG(w)
+}
+
+func (sc *scene) writeSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Go, Helvetica, Arial, sans-serif">`+"\n",
+		sc.w, sc.h, sc.w, sc.h)
+	for _, r := range sc.rects {
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" %s`, r.x, r.y, r.w, r.h, svgPaint("fill", r.fill))
+		if r.stroke.A > 0 && r.strokeWidth > 0 {
+			// SVG strokes straddle the edge; inset them to stay inside, as
+			// in the PNG.
+			inset := float64(r.strokeWidth) / 2
+			fmt.Fprintf(bw, `/>`+"\n"+`<rect x="%g" y="%g" width="%g" height="%g" fill="none" %s stroke-width="%d"`,
+				float64(r.x)+inset, float64(r.y)+inset, float64(r.w)-2*inset, float64(r.h)-2*inset,
+				svgPaint("stroke", r.stroke), r.strokeWidth)
+		}
+		fmt.Fprintf(bw, "/>\n")
+	}
+	for _, t := range sc.texts {
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-size="%d" text-anchor="%s" dominant-baseline="central" %s`,
+			t.x, t.y, t.size, svgAnchors[t.anchor], svgPaint("fill", t.fill))
+		if t.bold {
+			fmt.Fprintf(bw, ` font-weight="bold"`)
+		}
+		fmt.Fprintf(bw, ">")
+		if err := xml.EscapeText(bw, []byte(t.s)); err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, "</text>\n")
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+var svgAnchors = map[anchor]string{
+	anchorStart:  "start",
+	anchorMiddle: "middle",
+	anchorEnd:    "end",
+}
+
+// svgPaint returns the attributes that paint c, such as fill="#aabbcc".
+func svgPaint(attr string, c color.NRGBA) string {
+	paint := fmt.Sprintf(`%s="#%02x%02x%02x"`, attr, c.R, c.G, c.B)
+	if c.A < 0xff {
+		paint += fmt.Sprintf(` %s-opacity="%.2f"`, attr, float64(c.A)/0xff)
+	}
+	return paint
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"winPctLoss": winPctLoss,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; }
+td.num { text-align: right; }
+tr.played { background: #ffe9a8; }
+.diagram svg { max-width: 100%; height: auto; }
+pre { background: #f6f6f6; padding: 0.5em; overflow-x: auto; }
+.turn { border-top: 1px solid #ccc; margin-top: 1.5em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Final score: {{.FinalScore}}. Lexicon: {{.Report.Lexicon}}.</p>
+<p>{{.Report.Evaluation}}</p>
+
+<h2>Scorecard</h2>
+<table>
+<tr><th>Player</th><th>Score</th><th>Turns</th><th>Best moves</th><th>Equity lost</th><th>Per turn</th><th>Win % lost</th><th>Bingos</th><th>Missed bingos</th><th>Missed star plays</th><th>Phonies</th><th>Missed challenges</th><th>Wrongful challenges</th></tr>
+{{range .Report.Scorecards}}<tr><td>{{.Player}}</td><td class="num">{{.Score}}</td><td class="num">{{.Turns}}</td><td class="num">{{.TopMoves}}</td><td class="num">{{printf "%.2f" .EquityLoss}}</td><td class="num">{{printf "%.2f" .MeanEquityLoss}}</td><td class="num">{{winPctLoss .}}</td><td class="num">{{.Bingos}}</td><td class="num">{{.MissedBingos}}</td><td class="num">{{.MissedStarPlays}}</td><td class="num">{{.Phonies}}</td><td class="num">{{.MissedChallenges}}</td><td class="num">{{.WrongfulChallenges}}</td></tr>
+{{end}}</table>
+
+<h2>Turns</h2>
+{{range .Turns}}<div class="turn">
+<h3>Turn {{.Number}}: {{.Player}} played {{.Played}} for {{.Score}}</h3>
+{{if .Diagram}}<div class="diagram">{{.Diagram}}</div>{{else}}<pre>{{.Board}}</pre>{{end}}
+<table>
+<tr><th>Move</th><th>Score</th><th>Equity</th><th>Leave</th></tr>
+{{$played := .Played}}{{range .Best}}<tr{{if eq .Notation $played}} class="played"{{end}}><td>{{.Notation}}</td><td class="num">{{.Score}}</td><td class="num">{{printf "%.2f" .Equity}}</td><td>{{.Leave}}</td></tr>
+{{end}}</table>
+<ul>
+{{range .Summary}}<li>{{.}}</li>
+{{end}}</ul>
+</div>
+{{end}}
+{{if .Report.Challenges}}<h2>Challenges</h2>
+<table>
+<tr><th>Turn</th><th>Player</th><th>Play</th><th>Words formed</th><th>Phonies</th><th>Result</th></tr>
+{{range .Report.Challenges}}<tr><td class="num">{{.Number}}</td><td>{{.Player}}</td><td>{{.Play}}</td><td>{{range $i, $w := .WordsFormed}}{{if $i}}, {{end}}{{$w}}{{end}}</td><td>{{range $i, $w := .Phonies}}{{if $i}}, {{end}}{{$w}}{{end}}</td><td>{{.Result}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// htmlTurn adds the summary to a turn, and marks its diagram as safe to
+// embed, for the template.
+type htmlTurn struct {
+	*Turn
+	Diagram template.HTML
+	Summary []string
+}
+
+// WriteHTML writes the report as a self-contained HTML page.
+func (r *Report) WriteHTML(w io.Writer) error {
+	turns := make([]htmlTurn, len(r.Turns))
+	for i := range r.Turns {
+		turns[i] = htmlTurn{Turn: &r.Turns[i], Diagram: template.HTML(r.Turns[i].Diagram),
+			Summary: r.Turns[i].summary()}
+	}
+	return htmlTemplate.Execute(w, struct {
+		Report     *Report
+		Title      string
+		FinalScore string
+		Turns      []htmlTurn
+	}{r, r.Title(), r.finalScore(), turns})
+}
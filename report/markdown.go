@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes the report as Markdown.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", mdEscape(r.Title()))
+	fmt.Fprintf(bw, "Final score: %s. Lexicon: %s.\n\n", mdEscape(r.finalScore()), r.Lexicon)
+	fmt.Fprintf(bw, "%s\n\n", r.Evaluation())
+
+	fmt.Fprintf(bw, "## Scorecard\n\n")
+	fmt.Fprintf(bw, "| Player | Score | Turns | Best moves | Equity lost | Per turn | Win %% lost | Bingos | Missed bingos | Missed star plays | Phonies | Missed challenges | Wrongful challenges |\n")
+	fmt.Fprintf(bw, "|---|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|\n")
+	for _, c := range r.Scorecards {
+		fmt.Fprintf(bw, "| %s | %d | %d | %d | %.2f | %.2f | %s | %d | %d | %d | %d | %d | %d |\n",
+			mdEscape(c.Player), c.Score, c.Turns, c.TopMoves, c.EquityLoss, c.MeanEquityLoss(),
+			winPctLoss(&c), c.Bingos, c.MissedBingos, c.MissedStarPlays, c.Phonies,
+			c.MissedChallenges, c.WrongfulChallenges)
+	}
+
+	fmt.Fprintf(bw, "\n## Turns\n")
+	for i := range r.Turns {
+		t := &r.Turns[i]
+		fmt.Fprintf(bw, "\n### Turn %d: %s played %s for %d\n\n", t.Number(),
+			mdEscape(t.Player), t.Played, t.Score)
+		fmt.Fprintf(bw, "```\n%s\n```\n\n", strings.TrimRight(t.Board, "\n"))
+		fmt.Fprintf(bw, "| | Move | Score | Equity | Leave |\n")
+		fmt.Fprintf(bw, "|---|---|--:|--:|---|\n")
+		for _, m := range t.Best {
+			mark := ""
+			if m.Notation == t.Played {
+				mark = "played"
+			}
+			fmt.Fprintf(bw, "| %s | %s | %d | %.2f | %s |\n", mark, m.Notation, m.Score, m.Equity, m.Leave)
+		}
+		fmt.Fprintf(bw, "\n")
+		for _, line := range t.summary() {
+			fmt.Fprintf(bw, "- %s\n", line)
+		}
+	}
+
+	if len(r.Challenges) > 0 {
+		fmt.Fprintf(bw, "\n## Challenges\n\n")
+		fmt.Fprintf(bw, "| Turn | Player | Play | Words formed | Phonies | Result |\n")
+		fmt.Fprintf(bw, "|--:|---|---|---|---|---|\n")
+		for _, c := range r.Challenges {
+			fmt.Fprintf(bw, "| %d | %s | %s | %s | %s | %s |\n", c.Number(), mdEscape(c.Player),
+				c.Play, strings.Join(c.WordsFormed, ", "), strings.Join(c.Phonies, ", "), c.Result())
+		}
+	}
+	return bw.Flush()
+}
+
+// mdEscape keeps nicknames from breaking tables and formatting.
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`).Replace(s)
+}
+
+func (r *Report) finalScore() string {
+	scores := make([]string, len(r.Players))
+	for i, pl := range r.Players {
+		scores[i] = fmt.Sprintf("%s %d", pl, r.Scores[i])
+	}
+	return strings.Join(scores, ", ")
+}
+
+func winPctLoss(c *Scorecard) string {
+	if c.SimmedTurns == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", c.WinPctLoss)
+}
+
+// summary is the turn's losses followed by its notes.
+func (t *Turn) summary() []string {
+	lines := []string{}
+	if t.Top() {
+		lines = append(lines, "Best move")
+	} else {
+		lines = append(lines, fmt.Sprintf("Best move: %s", t.Eval.TopMove))
+	}
+	lines = append(lines, fmt.Sprintf("Equity lost: %.2f", t.Eval.EquityLoss))
+	if t.Simmed() {
+		lines = append(lines, fmt.Sprintf("Win %% lost: %.2f (%.2f played, %.2f best)",
+			t.Eval.WinPctLoss, t.Eval.PlayedWinPct, t.Eval.TopWinPct))
+	}
+	return append(lines, t.Notes()...)
+}
@@ -0,0 +1,337 @@
+// Package report reviews games: it evaluates every turn of one or both
+// players, and writes what they played, what they could have played
+// instead, and a scorecard, as Markdown or as a self-contained HTML page.
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/analysis"
+	mbot "github.com/domino14/macondo/bot"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+	"github.com/domino14/macondo/render"
+)
+
+// DefaultNumMoves is how many of the best moves are listed for each turn.
+const DefaultNumMoves = 5
+
+// Options say what to review and how.
+type Options struct {
+	// Users are the nicknames of the players to review. By default, every
+	// player is reviewed.
+	Users []string
+	// Sim sims every turn, as a sim evaluation does, and solves the endgame
+	// exactly once the bag is empty.
+	Sim bool
+	// Endgame solves the endgame exactly once the bag is empty, even if
+	// the other turns are not simmed.
+	Endgame bool
+	// These are as in an EvaluationRequest.
+	SimPlies             int
+	SimCandidates        int
+	SimIterations        int
+	PhonyChallengeChance float64
+	// NumMoves is how many of the best moves by static equity to list for
+	// each turn.
+	NumMoves int
+}
+
+// Turn is a reviewed turn.
+type Turn struct {
+	// EventIndex is the index of the turn's event in the game history.
+	EventIndex int
+	Player     string
+	Rack       string
+	// Board is the position before the move, as the shell displays it.
+	Board string
+	// Diagram is the position before the move as an SVG image, with the
+	// best moves overlaid.
+	Diagram string
+	Played  string
+	Score   int
+	Bingo   bool
+	// Best are the best moves by static equity.
+	Best []analysis.Move
+	Eval *pb.SingleEvaluation
+}
+
+// Number is the turn's number in the game, starting at 1.
+func (t *Turn) Number() int {
+	return t.EventIndex + 1
+}
+
+// Top returns whether the move played was the best one.
+func (t *Turn) Top() bool {
+	return t.Played == t.Eval.TopMove
+}
+
+// Simmed returns whether the turn was simmed, or its endgame solved.
+func (t *Turn) Simmed() bool {
+	return t.Eval.SimIterations > 0 || t.Eval.EndgameSolved
+}
+
+// Notes are the remarks on the turn besides its losses.
+func (t *Turn) Notes() []string {
+	notes := []string{}
+	if t.Eval.MissedBingo {
+		notes = append(notes, "Missed a bingo")
+	}
+	if t.Eval.MissedStarPlay {
+		notes = append(notes, "Missed a star play")
+	}
+	if t.Eval.Phony {
+		notes = append(notes, fmt.Sprintf("Phony, challenged off %.0f%% of the time",
+			100*t.Eval.PhonyChallengeChance))
+	}
+	if t.Eval.EndgameSolved {
+		notes = append(notes, fmt.Sprintf("Endgame solved: %d points lost to the best sequence",
+			t.Eval.EndgameSpreadLoss))
+	}
+	return notes
+}
+
+// Challenge is an opponent play that a reviewed player challenged or
+// should have.
+type Challenge struct {
+	Player string
+	*pb.ChallengeEvaluation
+}
+
+// Number is the number of the turn with the opponent's play, starting at 1.
+func (c *Challenge) Number() int {
+	return int(c.EventIndex) + 1
+}
+
+// Result says how the challenge went for the player.
+func (c *Challenge) Result() string {
+	switch {
+	case c.MissedChallenge:
+		return "Missed challenge"
+	case c.WrongfulChallenge:
+		return "Wrongful challenge"
+	case c.Challenged:
+		return "Challenged off"
+	}
+	return ""
+}
+
+// Scorecard sums up a player's turns.
+type Scorecard struct {
+	Player string
+	// Score is the player's final score.
+	Score int
+	Turns int
+	// TopMoves is the number of turns where the player played the best
+	// move.
+	TopMoves int
+	// EquityLoss is the total equity lost, which is 0 or negative.
+	EquityLoss float64
+	// WinPctLoss is the total win percentage lost over simmed turns.
+	WinPctLoss         float64
+	SimmedTurns        int
+	Bingos             int
+	MissedBingos       int
+	MissedStarPlays    int
+	Phonies            int
+	MissedChallenges   int
+	WrongfulChallenges int
+}
+
+// MeanEquityLoss is the equity lost per turn.
+func (s *Scorecard) MeanEquityLoss() float64 {
+	if s.Turns == 0 {
+		return 0
+	}
+	return s.EquityLoss / float64(s.Turns)
+}
+
+// Report is the review of a game.
+type Report struct {
+	Players []string
+	// Scores are the final scores, in the order of Players.
+	Scores  []int
+	Lexicon string
+	Options Options
+	// Turns are in the order they were played.
+	Turns      []Turn
+	Challenges []Challenge
+	Scorecards []Scorecard
+}
+
+// Title names the players.
+func (r *Report) Title() string {
+	return strings.Join(r.Players, " vs. ")
+}
+
+// Evaluation describes how the turns were evaluated.
+func (r *Report) Evaluation() string {
+	switch {
+	case r.Options.Sim:
+		return fmt.Sprintf("Every turn was simmed %d plies deep against the %d best moves, for %d iterations. Endgames were solved exactly.",
+			orDefault(r.Options.SimPlies, mbot.DefaultEvalSimPlies),
+			orDefault(r.Options.SimCandidates, mbot.DefaultEvalSimCandidates),
+			orDefault(r.Options.SimIterations, mbot.DefaultEvalSimIterations))
+	case r.Options.Endgame:
+		return "Every turn was evaluated by static equity, except that endgames were solved exactly."
+	}
+	return "Every turn was evaluated by static equity."
+}
+
+func orDefault(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
+
+// New reviews the game p was loaded with. It leaves p at the end of the
+// game.
+func New(ctx context.Context, p *bot.BotTurnPlayer, opts Options) (*Report, error) {
+	history := p.History()
+	r := &Report{Lexicon: p.LexiconName(), Options: opts, Turns: []Turn{}, Challenges: []Challenge{}}
+	for _, pi := range history.Players {
+		r.Players = append(r.Players, pi.Nickname)
+	}
+	users := opts.Users
+	if len(users) == 0 {
+		users = r.Players
+	}
+	req := &pb.EvaluationRequest{
+		SimPlies:             int32(opts.SimPlies),
+		SimCandidates:        int32(opts.SimCandidates),
+		SimIterations:        int32(opts.SimIterations),
+		PhonyChallengeChance: opts.PhonyChallengeChance,
+	}
+
+	idxs := []int{}
+	for _, u := range users {
+		idxs = append(idxs, mbot.EvaluatedTurns(history, u)...)
+	}
+	sort.Ints(idxs)
+	for _, idx := range idxs {
+		t, err := reviewTurn(ctx, p, idx, req, opts)
+		if err != nil {
+			return nil, fmt.Errorf("turn %d: %w", idx+1, err)
+		}
+		r.Turns = append(r.Turns, *t)
+	}
+
+	for _, u := range users {
+		challenges, err := mbot.EvaluateChallenges(p.Game, p.Lexicon(), u)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range challenges {
+			r.Challenges = append(r.Challenges, Challenge{Player: u, ChallengeEvaluation: c})
+		}
+	}
+	sort.SliceStable(r.Challenges, func(i, j int) bool {
+		return r.Challenges[i].EventIndex < r.Challenges[j].EventIndex
+	})
+
+	if err := p.PlayToTurn(len(history.Events)); err != nil {
+		return nil, err
+	}
+	for i := range r.Players {
+		r.Scores = append(r.Scores, p.PointsFor(i))
+	}
+	r.Scorecards = scorecards(r, users)
+	return r, nil
+}
+
+// reviewTurn evaluates the play or exchange in the event at idx.
+func reviewTurn(ctx context.Context, p *bot.BotTurnPlayer, idx int, req *pb.EvaluationRequest,
+	opts Options) (*Turn, error) {
+
+	history := p.History()
+	evt := history.Events[idx]
+	if err := p.PlayToTurn(idx); err != nil {
+		return nil, err
+	}
+	t := &Turn{
+		EventIndex: idx,
+		Player:     history.Players[evt.PlayerIndex].Nickname,
+		Rack:       evt.Rack,
+		Board:      strings.TrimPrefix(p.ToDisplayText(), "\n"),
+		Played:     analysis.EventNotation(evt),
+		Score:      int(evt.Score),
+		Bingo:      evt.IsBingo,
+	}
+	best := p.GenerateMoves(orDefault(opts.NumMoves, DefaultNumMoves))
+	for _, m := range best {
+		t.Best = append(t.Best, analysis.MakeMove(m))
+	}
+	var diagram strings.Builder
+	if err := render.SVG(&diagram, p.Game, &render.Options{Candidates: best}); err != nil {
+		return nil, err
+	}
+	t.Diagram = diagram.String()
+	var err error
+	if opts.Sim || (opts.Endgame && p.Bag().TilesRemaining() == 0) {
+		t.Eval, err = mbot.SimEvaluateMove(ctx, p, idx, req)
+	} else {
+		t.Eval, err = mbot.EvaluateMove(p, idx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// scorecards sums up the turns and challenges of each user.
+func scorecards(r *Report, users []string) []Scorecard {
+	cards := []Scorecard{}
+	for _, u := range users {
+		card := Scorecard{Player: u}
+		for i, pl := range r.Players {
+			if strings.EqualFold(pl, u) && i < len(r.Scores) {
+				card.Player = pl
+				card.Score = r.Scores[i]
+			}
+		}
+		for i := range r.Turns {
+			t := &r.Turns[i]
+			if !strings.EqualFold(t.Player, u) {
+				continue
+			}
+			card.Turns++
+			if t.Top() {
+				card.TopMoves++
+			}
+			card.EquityLoss += t.Eval.EquityLoss
+			if t.Simmed() {
+				card.SimmedTurns++
+				card.WinPctLoss += t.Eval.WinPctLoss
+			}
+			if t.Bingo {
+				card.Bingos++
+			}
+			if t.Eval.MissedBingo {
+				card.MissedBingos++
+			}
+			if t.Eval.MissedStarPlay {
+				card.MissedStarPlays++
+			}
+			if t.Eval.Phony {
+				card.Phonies++
+			}
+		}
+		for _, c := range r.Challenges {
+			if !strings.EqualFold(c.Player, u) {
+				continue
+			}
+			if c.MissedChallenge {
+				card.MissedChallenges++
+			}
+			if c.WrongfulChallenge {
+				card.WrongfulChallenges++
+			}
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
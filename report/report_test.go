@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/analysis"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+// testReport is a review of a short game between cesar and <emely>.
+func testReport() *Report {
+	r := &Report{
+		Players: []string{"cesar", "<emely>"},
+		Scores:  []int{420, 380},
+		Lexicon: "NWL20",
+		Turns: []Turn{
+			{EventIndex: 0, Player: "cesar", Rack: "AEINRST", Board: "board 1", Played: "8D STAIR",
+				Score: 72, Bingo: true,
+				Best: []analysis.Move{{Notation: "8D STAIR", Score: 72, Equity: 72}},
+				Eval: &pb.SingleEvaluation{TopMove: "8D STAIR"}},
+			{EventIndex: 1, Player: "<emely>", Rack: "AEGLNOT", Board: "board 2", Diagram: "<svg><text>2</text></svg>",
+				Played: "H6 TO.",
+				Score:  3,
+				Best: []analysis.Move{{Notation: "H2 TANGLE.O", Score: 70, Equity: 70},
+					{Notation: "H6 TO.", Score: 3, Equity: 9}},
+				Eval: &pb.SingleEvaluation{TopMove: "H2 TANGLE.O", EquityLoss: -61, MissedBingo: true,
+					TopIsBingo: true}},
+			{EventIndex: 2, Player: "cesar", Rack: "DEEILPR", Board: "board 3", Played: "5E PILED",
+				Score: 20,
+				Eval: &pb.SingleEvaluation{TopMove: "5E PLIED", EquityLoss: -4, Phony: true,
+					PhonyChallengeChance: 0.4, SimIterations: 1000, WinPctLoss: 6,
+					PlayedWinPct: 50, TopWinPct: 56}},
+		},
+		Challenges: []Challenge{
+			{Player: "<emely>", ChallengeEvaluation: &pb.ChallengeEvaluation{EventIndex: 2,
+				Play: "5E PILED", WordsFormed: []string{"PILED", "TAP"}, Phonies: []string{"TAP"},
+				MissedChallenge: true}},
+		},
+	}
+	r.Scorecards = scorecards(r, r.Players)
+	return r
+}
+
+func TestScorecards(t *testing.T) {
+	is := is.New(t)
+	r := testReport()
+	is.Equal(r.Scorecards, []Scorecard{
+		{Player: "cesar", Score: 420, Turns: 2, TopMoves: 1, EquityLoss: -4, WinPctLoss: 6,
+			SimmedTurns: 1, Bingos: 1, Phonies: 1},
+		{Player: "<emely>", Score: 380, Turns: 1, EquityLoss: -61, MissedBingos: 1,
+			MissedChallenges: 1},
+	})
+	is.Equal(r.Scorecards[0].MeanEquityLoss(), -2.0)
+
+	// Only the users asked for get a scorecard, whatever the case of
+	// their nickname.
+	cards := scorecards(r, []string{"CESAR"})
+	is.Equal(len(cards), 1)
+	is.Equal(cards[0].Player, "cesar")
+	is.Equal(cards[0].Turns, 2)
+}
+
+func TestTurnSummary(t *testing.T) {
+	is := is.New(t)
+	r := testReport()
+	is.Equal(r.Turns[0].summary(), []string{"Best move", "Equity lost: 0.00"})
+	is.Equal(r.Turns[2].summary(), []string{
+		"Best move: 5E PLIED",
+		"Equity lost: -4.00",
+		"Win % lost: 6.00 (50.00 played, 56.00 best)",
+		"Phony, challenged off 40% of the time",
+	})
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	is.NoErr(testReport().WriteMarkdown(&buf))
+	md := buf.String()
+	is.True(strings.HasPrefix(md, "# cesar vs. <emely>\n"))
+	is.True(strings.Contains(md, "| cesar | 420 | 2 | 1 | -4.00 | -2.00 | 6.00 | 1 | 0 | 0 | 1 | 0 | 0 |\n"))
+	is.True(strings.Contains(md, "### Turn 2: <emely> played H6 TO. for 3\n\n```\nboard 2\n```\n"))
+	is.True(strings.Contains(md, "| played | H6 TO. | 3 | 9.00 |  |\n"))
+	is.True(strings.Contains(md, "- Missed a bingo\n"))
+	is.True(strings.Contains(md, "| 3 | <emely> | 5E PILED | PILED, TAP | TAP | Missed challenge |\n"))
+}
+
+func TestWriteHTML(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	is.NoErr(testReport().WriteHTML(&buf))
+	page := buf.String()
+	is.True(strings.Contains(page, "<title>cesar vs. &lt;emely&gt;</title>"))
+	is.True(!strings.Contains(page, "<emely>"))
+	is.True(strings.Contains(page, `<tr class="played"><td>H6 TO.</td>`))
+	is.True(strings.Contains(page, "<pre>board 3</pre>"))
+	is.True(strings.Contains(page, `<div class="diagram"><svg><text>2</text></svg></div>`))
+	is.True(!strings.Contains(page, "<pre>board 2</pre>"))
+	is.True(strings.Contains(page, "<li>Phony, challenged off 40% of the time</li>"))
+	is.True(strings.Contains(page, "<td>Missed challenge</td>"))
+}
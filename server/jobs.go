@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// JobStatus is the state a job is in.
+type JobStatus string
+
+const (
+	StatusQueued   JobStatus = "queued"
+	StatusRunning  JobStatus = "running"
+	StatusDone     JobStatus = "done"
+	StatusFailed   JobStatus = "failed"
+	StatusCanceled JobStatus = "canceled"
+)
+
+var errQueueFull = errors.New("too many jobs; try again later")
+
+// Job is a snapshot of a job, as returned by the server.
+type Job struct {
+	ID       string     `json:"id"`
+	Kind     string     `json:"kind"`
+	Status   JobStatus  `json:"status"`
+	Error    string     `json:"error,omitempty"`
+	Progress any        `json:"progress,omitempty"`
+	Result   any        `json:"result,omitempty"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+func (j *Job) finished() bool {
+	return j.Status == StatusDone || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// runFunc does the work of a job. It calls progress with partial results
+// and returns the final result.
+type runFunc func(ctx context.Context, progress func(any)) (any, error)
+
+type job struct {
+	mu  sync.Mutex
+	job Job
+	// version goes up every time the job changes.
+	version int
+	// changed is closed, and replaced, every time the job changes.
+	changed chan struct{}
+	cancel  context.CancelFunc
+	// stopped is set once a client cancels the job.
+	stopped bool
+}
+
+func (j *job) snapshot() (Job, int, chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.job, j.version, j.changed
+}
+
+func (j *job) update(f func(*Job)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	f(&j.job)
+	j.version++
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// stop cancels the job on behalf of a client. A job that was already
+// running still returns the best result it found.
+func (j *job) stop() {
+	j.mu.Lock()
+	j.stopped = true
+	j.mu.Unlock()
+	j.cancel()
+}
+
+// jobManager runs at most maxRunning jobs at a time, keeps at most maxQueued
+// more waiting, and forgets finished jobs after ttl.
+type jobManager struct {
+	mu        sync.Mutex
+	jobs      map[string]*job
+	slots     chan struct{}
+	maxQueued int
+	ttl       time.Duration
+	wg        sync.WaitGroup
+	// active counts the jobs that are queued or running.
+	active int
+}
+
+func newJobManager(maxRunning, maxQueued int, ttl time.Duration) *jobManager {
+	return &jobManager{
+		jobs:      map[string]*job{},
+		slots:     make(chan struct{}, maxRunning),
+		maxQueued: maxQueued,
+		ttl:       ttl,
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// start queues a job and returns it right away. The job fails if it is still
+// running after timeLimit, unless it returns the best result it found so
+// far.
+func (m *jobManager) start(kind string, timeLimit time.Duration, run runFunc) (*job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active >= m.maxQueued+cap(m.slots) {
+		return nil, errQueueFull
+	}
+	m.active++
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeLimit > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeLimit)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	j := &job{
+		job: Job{
+			ID:      newJobID(),
+			Kind:    kind,
+			Status:  StatusQueued,
+			Created: time.Now(),
+		},
+		changed: make(chan struct{}),
+		cancel:  cancel,
+	}
+	m.jobs[j.job.ID] = j
+	m.wg.Add(1)
+	go m.run(ctx, j, run)
+	return j, nil
+}
+
+func (m *jobManager) run(ctx context.Context, j *job, run runFunc) {
+	defer m.wg.Done()
+	defer j.cancel()
+	select {
+	case m.slots <- struct{}{}:
+	case <-ctx.Done():
+		m.finish(j, nil, ctx.Err())
+		return
+	}
+	defer func() { <-m.slots }()
+
+	now := time.Now()
+	j.update(func(jb *Job) {
+		jb.Status = StatusRunning
+		jb.Started = &now
+	})
+	result, err := run(ctx, func(p any) {
+		j.update(func(jb *Job) { jb.Progress = p })
+	})
+	m.finish(j, result, err)
+}
+
+func (m *jobManager) finish(j *job, result any, err error) {
+	m.mu.Lock()
+	m.active--
+	m.mu.Unlock()
+
+	now := time.Now()
+	j.mu.Lock()
+	stopped := j.stopped
+	j.mu.Unlock()
+	j.update(func(jb *Job) {
+		jb.Finished = &now
+		jb.Result = result
+		switch {
+		case stopped:
+			jb.Status = StatusCanceled
+		case err != nil:
+			jb.Status = StatusFailed
+			jb.Error = err.Error()
+		default:
+			jb.Status = StatusDone
+		}
+	})
+	time.AfterFunc(m.ttl, func() {
+		m.mu.Lock()
+		delete(m.jobs, j.job.ID)
+		m.mu.Unlock()
+	})
+}
+
+func (m *jobManager) get(id string) *job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jobs[id]
+}
+
+// shutdown cancels all jobs and waits for them to finish.
+func (m *jobManager) shutdown() {
+	m.mu.Lock()
+	for _, j := range m.jobs {
+		j.stop()
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
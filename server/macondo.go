@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+
+	"github.com/domino14/macondo/analysis"
+	mbot "github.com/domino14/macondo/bot"
+	"github.com/domino14/macondo/config"
+	pb "github.com/domino14/macondo/gen/api/proto/macondo"
+)
+
+// macondoBackend analyzes with macondo's own analyzer, and evaluates games
+// with the bot's evaluator.
+type macondoBackend struct {
+	*analysis.Analyzer
+}
+
+func newMacondoBackend(cfg *config.Config) *macondoBackend {
+	return &macondoBackend{Analyzer: analysis.NewAnalyzer(cfg)}
+}
+
+func (b *macondoBackend) EvaluateGame(ctx context.Context, req *analysis.Request) (*analysis.EvaluationResult, error) {
+	p, err := b.Player(req)
+	if err != nil {
+		return nil, err
+	}
+	evalReq := &pb.EvaluationRequest{
+		User:                 req.User,
+		SimPlies:             int32(req.Plies),
+		SimCandidates:        int32(req.Candidates),
+		SimIterations:        int32(req.Iterations),
+		PhonyChallengeChance: req.PhonyChallengeChance,
+	}
+	if req.Evaluation == analysis.EvaluationSim {
+		evalReq.Type = pb.EvaluationRequest_SIM
+	}
+	evaluation, err := mbot.Evaluate(ctx, p, evalReq)
+	if err != nil {
+		return nil, err
+	}
+	evts := p.History().Events
+	res := &analysis.EvaluationResult{User: req.User, Plays: []analysis.MoveEvaluation{}}
+	for i, idx := range mbot.EvaluatedTurns(p.History(), req.User) {
+		eval := evaluation.PlayEval[i]
+		res.Plays = append(res.Plays, analysis.MoveEvaluation{
+			Turn:                 idx,
+			Played:               analysis.EventNotation(evts[idx]),
+			TopMove:              eval.TopMove,
+			EquityLoss:           eval.EquityLoss,
+			WinPctLoss:           eval.WinPctLoss,
+			MissedBingo:          eval.MissedBingo,
+			PossibleStarPlay:     eval.PossibleStarPlay,
+			MissedStarPlay:       eval.MissedStarPlay,
+			TopIsBingo:           eval.TopIsBingo,
+			PlayedWinPct:         eval.PlayedWinPct,
+			TopWinPct:            eval.TopWinPct,
+			SimIterations:        int(eval.SimIterations),
+			EndgameSolved:        eval.EndgameSolved,
+			EndgameSpreadLoss:    int(eval.EndgameSpreadLoss),
+			Phony:                eval.Phony,
+			PhonyChallengeChance: eval.PhonyChallengeChance,
+		})
+	}
+	res.Challenges = []analysis.ChallengeEvaluation{}
+	for _, ce := range evaluation.ChallengeEval {
+		res.Challenges = append(res.Challenges, analysis.ChallengeEvaluation{
+			Turn:              int(ce.EventIndex),
+			Play:              ce.Play,
+			WordsFormed:       ce.WordsFormed,
+			Phonies:           ce.Phonies,
+			Challenged:        ce.Challenged,
+			MissedChallenge:   ce.MissedChallenge,
+			WrongfulChallenge: ce.WrongfulChallenge,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,272 @@
+// Package server implements an HTTP server with a JSON API for analyzing
+// positions and games. Every analysis runs as a job, which can be waited
+// for, polled, streamed with server-sent events, or canceled. See
+// docs/manual/server.md for the API.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/macondo/analysis"
+	"github.com/domino14/macondo/config"
+)
+
+const (
+	// JobRetention is how long a finished job can still be fetched.
+	JobRetention = 10 * time.Minute
+	// maxRequestSize is the largest request body the server reads.
+	maxRequestSize = 1 << 20
+)
+
+var errNotFound = errors.New("no such job")
+
+// backend does the actual analysis.
+type backend interface {
+	Generate(ctx context.Context, req *analysis.Request) (*analysis.GenerateResult, error)
+	// Sim calls progress with the standings so far, every so often, and
+	// returns the final standings. It returns what it has when the context
+	// is done.
+	Sim(ctx context.Context, req *analysis.Request, progress func(*analysis.SimResult)) (*analysis.SimResult, error)
+	Endgame(ctx context.Context, req *analysis.Request) (*analysis.EndgameResult, error)
+	Infer(ctx context.Context, req *analysis.Request) (*analysis.InferResult, error)
+	EvaluateGame(ctx context.Context, req *analysis.Request) (*analysis.EvaluationResult, error)
+	Load(ctx context.Context, req *analysis.Request) (*analysis.LoadResult, error)
+	CheckWords(ctx context.Context, req *analysis.Request) (*analysis.CheckWordsResult, error)
+}
+
+// Server is an http.Handler for the analysis API.
+type Server struct {
+	b    backend
+	jobs *jobManager
+}
+
+// New creates a server that runs at most cfg.ServerMaxJobs jobs at once,
+// with at most cfg.ServerMaxQueue more waiting.
+func New(cfg *config.Config) *Server {
+	return newServer(newMacondoBackend(cfg), cfg.ServerMaxJobs, cfg.ServerMaxQueue)
+}
+
+func newServer(b backend, maxJobs, maxQueue int) *Server {
+	if maxJobs < 1 {
+		maxJobs = 1
+	}
+	return &Server{b: b, jobs: newJobManager(maxJobs, maxQueue, JobRetention)}
+}
+
+// Shutdown cancels all jobs and waits for them to finish.
+func (s *Server) Shutdown() {
+	s.jobs.shutdown()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	log.Debug().Str("method", r.Method).Str("path", path).Msg("server-request")
+
+	if len(parts) == 1 && isKind(parts[0]) {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.startJob(w, r, parts[0])
+		return
+	}
+	if parts[0] != "jobs" || len(parts) < 2 || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint /%v", path))
+		return
+	}
+	j := s.jobs.get(parts[1])
+	if j == nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+	if len(parts) == 3 {
+		if parts[2] != "events" {
+			writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint /%v", path))
+			return
+		}
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		streamEvents(w, r, j)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		snap, _, _ := j.snapshot()
+		writeJSON(w, http.StatusOK, snap)
+	case http.MethodDelete:
+		j.stop()
+		snap, _, _ := j.snapshot()
+		writeJSON(w, http.StatusOK, snap)
+	default:
+		methodNotAllowed(w, http.MethodGet+", "+http.MethodDelete)
+	}
+}
+
+func isKind(s string) bool {
+	for _, k := range analysis.Kinds {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}
+
+// startJob starts a job. By default it replies right away with the queued
+// job. With ?wait=true it replies once the job is finished, and with an
+// Accept: text/event-stream header it streams the job's events. In both of
+// those cases the job is canceled if the client goes away.
+func (s *Server) startJob(w http.ResponseWriter, r *http.Request, kind string) {
+	req := &analysis.Request{}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("bad request body: %w", err))
+		return
+	}
+	if err := req.Validate(kind); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	j, err := s.jobs.start(kind, req.TimeLimit(), s.runner(kind, req))
+	if err != nil {
+		writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	snap, _, _ := j.snapshot()
+	log.Info().Str("id", snap.ID).Str("kind", kind).Msg("job-started")
+	w.Header().Set("Location", "/jobs/"+snap.ID)
+
+	stream := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	wait := r.URL.Query().Get("wait") == "true"
+	if !stream && !wait {
+		writeJSON(w, http.StatusAccepted, snap)
+		return
+	}
+	// Stop the job if the client leaves before it's done.
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-r.Context().Done():
+			j.stop()
+		case <-finished:
+		}
+	}()
+	if stream {
+		streamEvents(w, r, j)
+		return
+	}
+	for {
+		snap, _, changed := j.snapshot()
+		if snap.finished() {
+			writeJSON(w, http.StatusOK, snap)
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// runner returns the function that does the work of a job.
+func (s *Server) runner(kind string, req *analysis.Request) runFunc {
+	return func(ctx context.Context, progress func(any)) (any, error) {
+		switch kind {
+		case analysis.KindGenerate:
+			return result(s.b.Generate(ctx, req))
+		case analysis.KindSim:
+			return result(s.b.Sim(ctx, req, func(p *analysis.SimResult) { progress(p) }))
+		case analysis.KindEndgame:
+			return result(s.b.Endgame(ctx, req))
+		case analysis.KindInfer:
+			return result(s.b.Infer(ctx, req))
+		case analysis.KindLoad:
+			return result(s.b.Load(ctx, req))
+		case analysis.KindCheckWords:
+			return result(s.b.CheckWords(ctx, req))
+		default:
+			return result(s.b.EvaluateGame(ctx, req))
+		}
+	}
+}
+
+// result makes sure that a nil result is a nil interface, so that it is
+// left out of the job.
+func result[T any](r *T, err error) (any, error) {
+	if r == nil {
+		return nil, err
+	}
+	return r, err
+}
+
+// streamEvents sends server-sent events until the job is finished or the
+// client goes away. Every event carries the whole job as its data. There is
+// a progress event whenever the job changes, and a single end event once it
+// is finished.
+func streamEvents(w http.ResponseWriter, r *http.Request, j *job) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	lastVersion := -1
+	for {
+		snap, version, changed := j.snapshot()
+		if version != lastVersion {
+			event := "progress"
+			if snap.finished() {
+				event = "end"
+			}
+			bts, err := json.Marshal(snap)
+			if err != nil {
+				log.Err(err).Msg("server-marshal-error")
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, bts)
+			flusher.Flush()
+			lastVersion = version
+			if snap.finished() {
+				return
+			}
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Err(err).Msg("server-write-error")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
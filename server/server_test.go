@@ -0,0 +1,367 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/analysis"
+)
+
+const testCGP = "15/15/15/15/15/15/15/15/15/15/15/15/15/15/15 AEINRST/ 0/0 0 lex NWL20;"
+
+// fakeBackend pretends to analyze. Its sims count one iteration per
+// millisecond and report every ten iterations.
+type fakeBackend struct{}
+
+func (fakeBackend) Generate(ctx context.Context, req *analysis.Request) (*analysis.GenerateResult, error) {
+	return &analysis.GenerateResult{Plays: []analysis.Move{{Notation: "8D STAIR", Action: "play", Score: 72}}}, nil
+}
+
+func (fakeBackend) Sim(ctx context.Context, req *analysis.Request, progress func(*analysis.SimResult)) (*analysis.SimResult, error) {
+	tk := time.NewTicker(time.Millisecond)
+	defer tk.Stop()
+	res := func(iters int) *analysis.SimResult {
+		return &analysis.SimResult{Iterations: iters, Plays: []analysis.SimPlay{{Move: analysis.Move{Notation: "8D STAIR"}, WinPct: 55}}}
+	}
+	iters := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return res(iters), nil
+		case <-tk.C:
+			iters++
+			if iters%10 == 0 {
+				progress(res(iters))
+			}
+			if req.Iterations > 0 && iters >= req.Iterations {
+				return res(iters), nil
+			}
+		}
+	}
+}
+
+func (fakeBackend) Endgame(ctx context.Context, req *analysis.Request) (*analysis.EndgameResult, error) {
+	return nil, analysis.ErrBagNotEmpty
+}
+
+func (fakeBackend) Infer(ctx context.Context, req *analysis.Request) (*analysis.InferResult, error) {
+	return &analysis.InferResult{Inferences: 1, Leaves: []analysis.InferredLeave{{Leave: "S", Count: 1, Pct: 100}}}, nil
+}
+
+func (fakeBackend) EvaluateGame(ctx context.Context, req *analysis.Request) (*analysis.EvaluationResult, error) {
+	return &analysis.EvaluationResult{User: req.User, Plays: []analysis.MoveEvaluation{{Turn: 0, Played: "8D STAIR"}}}, nil
+}
+
+func (fakeBackend) Load(ctx context.Context, req *analysis.Request) (*analysis.LoadResult, error) {
+	return &analysis.LoadResult{Lexicon: "NWL20", Events: []analysis.Event{}}, nil
+}
+
+func (fakeBackend) CheckWords(ctx context.Context, req *analysis.Request) (*analysis.CheckWordsResult, error) {
+	res := &analysis.CheckWordsResult{Lexicon: "NWL20", Words: []analysis.WordCheck{}, Valid: true}
+	for _, w := range req.Words {
+		res.Words = append(res.Words, analysis.WordCheck{Word: w, Valid: w != "ZZZ"})
+		res.Valid = res.Valid && w != "ZZZ"
+	}
+	return res, nil
+}
+
+func newTestServer(maxJobs, maxQueue int) (*Server, *httptest.Server) {
+	s := newServer(fakeBackend{}, maxJobs, maxQueue)
+	return s, httptest.NewServer(s)
+}
+
+func post(t *testing.T, url, body string) (*http.Response, map[string]any) {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	return resp, decode(t, resp)
+}
+
+func get(t *testing.T, method, url string) (*http.Response, map[string]any) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	return resp, decode(t, resp)
+}
+
+func decode(t *testing.T, resp *http.Response) map[string]any {
+	m := map[string]any{}
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+type event struct {
+	name string
+	job  Job
+}
+
+// readEvents reads server-sent events until the stream ends.
+func readEvents(t *testing.T, resp *http.Response) []event {
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	events := []event{}
+	var cur event
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			cur.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &cur.job); err != nil {
+				t.Fatal(err)
+			}
+		case line == "":
+			events = append(events, cur)
+			cur = event{}
+		}
+	}
+	return events
+}
+
+func stream(t *testing.T, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// waitForStatus polls a job until it has the given status.
+func waitForStatus(t *testing.T, url string, status JobStatus) map[string]any {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		_, job := get(t, http.MethodGet, url)
+		if job["status"] == string(status) {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %v to be %v", url, status)
+	return nil
+}
+
+func TestStartAndPoll(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	resp, job := post(t, ts.URL+"/generate", `{"cgp": "`+testCGP+`"}`)
+	is.Equal(resp.StatusCode, http.StatusAccepted)
+	id := job["id"].(string)
+	is.Equal(resp.Header.Get("Location"), "/jobs/"+id)
+	is.Equal(job["kind"], analysis.KindGenerate)
+
+	job = waitForStatus(t, ts.URL+"/jobs/"+id, StatusDone)
+	plays := job["result"].(map[string]any)["plays"].([]any)
+	is.Equal(plays[0].(map[string]any)["notation"], "8D STAIR")
+	is.True(job["finished"] != nil)
+}
+
+func TestWait(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	resp, job := post(t, ts.URL+"/evaluate-game?wait=true", `{"gcg": "#player1 cesar Cesar", "user": "cesar"}`)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(job["status"], string(StatusDone))
+	is.Equal(job["result"].(map[string]any)["user"], "cesar")
+
+	// A job that fails says why.
+	resp, job = post(t, ts.URL+"/endgame?wait=true", `{"cgp": "`+testCGP+`"}`)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(job["status"], string(StatusFailed))
+	is.Equal(job["error"], analysis.ErrBagNotEmpty.Error())
+	is.Equal(job["result"], nil)
+
+	// Checking words needs no position.
+	resp, job = post(t, ts.URL+"/check-words?wait=true", `{"words": ["QI", "ZZZ"]}`)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(job["status"], string(StatusDone))
+	is.Equal(job["result"].(map[string]any)["valid"], false)
+}
+
+func TestBadRequests(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	for _, tc := range []struct {
+		path string
+		body string
+		err  string
+	}{
+		{"/generate", `{}`, analysis.ErrNoPosition.Error()},
+		{"/generate", `{"cgp": "x", "gcg": "y"}`, analysis.ErrTwoPositions.Error()},
+		{"/sim", `{"cgp": "x", "stopCondition": "90"}`, "unknown stop condition 90"},
+		{"/sim", `{"cgp": "x", "plies": -1}`, "numbers in the request cannot be negative"},
+		{"/evaluate-game", `{"cgp": "x", "user": "cesar"}`, analysis.ErrNeedGCG.Error()},
+		{"/evaluate-game", `{"gcg": "x"}`, analysis.ErrNeedUser.Error()},
+		{"/evaluate-game", `{"gcg": "x", "user": "cesar", "evaluation": "deep"}`, "unknown evaluation deep"},
+		{"/evaluate-game", `{"gcg": "x", "user": "cesar", "phonyChallengeChance": 1.5}`, "phonyChallengeChance must be between 0 and 1"},
+		{"/check-words", `{"lexicon": "NWL20"}`, analysis.ErrNeedWords.Error()},
+		{"/load", `{}`, analysis.ErrNoPosition.Error()},
+	} {
+		resp, body := post(t, ts.URL+tc.path, tc.body)
+		is.Equal(resp.StatusCode, http.StatusBadRequest)
+		is.Equal(body["error"], tc.err)
+	}
+
+	resp, body := post(t, ts.URL+"/generate", `{"cgp": "x", "bogus": 1}`)
+	is.Equal(resp.StatusCode, http.StatusBadRequest)
+	is.True(strings.HasPrefix(body["error"].(string), "bad request body"))
+
+	resp, _ = get(t, http.MethodGet, ts.URL+"/generate")
+	is.Equal(resp.StatusCode, http.StatusMethodNotAllowed)
+	is.Equal(resp.Header.Get("Allow"), http.MethodPost)
+
+	resp, _ = get(t, http.MethodGet, ts.URL+"/solve")
+	is.Equal(resp.StatusCode, http.StatusNotFound)
+
+	resp, body = get(t, http.MethodGet, ts.URL+"/jobs/abc")
+	is.Equal(resp.StatusCode, http.StatusNotFound)
+	is.Equal(body["error"], errNotFound.Error())
+}
+
+func TestSimStream(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	resp := stream(t, http.MethodPost, ts.URL+"/sim", `{"cgp": "`+testCGP+`", "iterations": 30}`)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	events := readEvents(t, resp)
+	is.True(len(events) > 3)
+	is.Equal(events[0].name, "progress")
+	is.Equal(events[0].job.Status, StatusQueued)
+
+	last := events[len(events)-1]
+	is.Equal(last.name, "end")
+	is.Equal(last.job.Status, StatusDone)
+	res := last.job.Result.(map[string]any)
+	is.Equal(res["iterations"], float64(30))
+	for _, e := range events[:len(events)-1] {
+		is.Equal(e.name, "progress")
+		is.Equal(e.job.ID, last.job.ID)
+	}
+
+	// A finished job's stream has just the end event.
+	events = readEvents(t, stream(t, http.MethodGet, ts.URL+"/jobs/"+last.job.ID+"/events", ""))
+	is.Equal(len(events), 1)
+	is.Equal(events[0].name, "end")
+}
+
+func TestCancel(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	_, job := post(t, ts.URL+"/sim", `{"cgp": "`+testCGP+`"}`)
+	url := ts.URL + "/jobs/" + job["id"].(string)
+	waitForStatus(t, url, StatusRunning)
+
+	resp, _ := get(t, http.MethodDelete, url)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	job = waitForStatus(t, url, StatusCanceled)
+	// The sim still reports what it found.
+	is.True(job["result"] != nil)
+}
+
+func TestClientLeaving(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+"/sim",
+		strings.NewReader(`{"cgp": "`+testCGP+`"}`))
+	is.NoErr(err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+	defer resp.Body.Close()
+	cancel()
+
+	waitForStatus(t, ts.URL+resp.Header.Get("Location"), StatusCanceled)
+}
+
+func TestConcurrencyLimits(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(1, 1)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	body := `{"cgp": "` + testCGP + `"}`
+	_, first := post(t, ts.URL+"/sim", body)
+	firstURL := ts.URL + "/jobs/" + first["id"].(string)
+	waitForStatus(t, firstURL, StatusRunning)
+
+	resp, second := post(t, ts.URL+"/sim", body)
+	is.Equal(resp.StatusCode, http.StatusAccepted)
+	secondURL := ts.URL + "/jobs/" + second["id"].(string)
+	time.Sleep(20 * time.Millisecond)
+	_, second = get(t, http.MethodGet, secondURL)
+	is.Equal(second["status"], string(StatusQueued))
+
+	resp, body3 := post(t, ts.URL+"/generate", body)
+	is.Equal(resp.StatusCode, http.StatusTooManyRequests)
+	is.Equal(body3["error"], errQueueFull.Error())
+
+	// Once the first job is done, the second one runs.
+	get(t, http.MethodDelete, firstURL)
+	waitForStatus(t, secondURL, StatusRunning)
+	resp, _ = post(t, ts.URL+"/generate", body)
+	is.Equal(resp.StatusCode, http.StatusAccepted)
+
+	// A queued job can be canceled before it starts.
+	_, queued := get(t, http.MethodGet, ts.URL+resp.Header.Get("Location"))
+	is.Equal(queued["status"], string(StatusQueued))
+	get(t, http.MethodDelete, ts.URL+resp.Header.Get("Location"))
+	job := waitForStatus(t, ts.URL+resp.Header.Get("Location"), StatusCanceled)
+	is.Equal(job["started"], nil)
+}
+
+func TestTimeLimit(t *testing.T) {
+	is := is.New(t)
+	s, ts := newTestServer(2, 2)
+	defer ts.Close()
+	defer s.Shutdown()
+
+	start := time.Now()
+	resp, job := post(t, ts.URL+"/sim?wait=true", `{"cgp": "`+testCGP+`", "timeMs": 50}`)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(job["status"], string(StatusDone))
+	is.True(time.Since(start) < 2*time.Second)
+}
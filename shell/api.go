@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +21,8 @@ import (
 	"github.com/domino14/macondo/equity"
 	"github.com/domino14/macondo/game"
 	"github.com/domino14/macondo/gcgio"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/stats"
 	"github.com/domino14/macondo/tilemapping"
 )
 
@@ -62,13 +65,26 @@ func (sc *ShellController) gid(cmd *shellcmd) (*Response, error) {
 }
 
 func (sc *ShellController) newGame(cmd *shellcmd) (*Response, error) {
+	nplayers := 2
+	if len(cmd.args) > 0 {
+		var err error
+		nplayers, err = strconv.Atoi(cmd.args[0])
+		if err != nil {
+			return nil, err
+		}
+		if nplayers < 2 || nplayers > game.MaxPlayers {
+			return nil, fmt.Errorf("number of players must be between 2 and %d", game.MaxPlayers)
+		}
+	}
 	players := []*pb.PlayerInfo{
 		{Nickname: "arcadio", RealName: "José Arcadio Buendía"},
 		{Nickname: "úrsula", RealName: "Úrsula Iguarán Buendía"},
-	}
-	if frand.Intn(2) == 1 {
-		players[0], players[1] = players[1], players[0]
-	}
+		{Nickname: "aureliano", RealName: "Coronel Aureliano Buendía"},
+		{Nickname: "amaranta", RealName: "Amaranta Buendía"},
+	}[:nplayers]
+	frand.Shuffle(len(players), func(i, j int) {
+		players[i], players[j] = players[j], players[i]
+	})
 
 	opts := sc.options.GameOptions
 	conf := &bot.BotConfig{Config: *sc.config}
@@ -110,6 +126,42 @@ func (sc *ShellController) load(cmd *shellcmd) (*Response, error) {
 	return msg(sc.game.ToDisplayText()), nil
 }
 
+func (sc *ShellController) gcgcheck(cmd *shellcmd) (*Response, error) {
+	if len(cmd.args) != 1 {
+		return nil, errors.New("please provide the path to a gcg")
+	}
+	filename := cmd.args[0]
+	lexName := cmd.options["lex"]
+	if lexName == "" {
+		history, _, err := gcgio.ParseGCGLenient(sc.config, filename)
+		if err != nil {
+			return nil, err
+		}
+		lexName = history.Lexicon
+	}
+	k, err := kwg.Get(sc.config, lexName)
+	if err != nil {
+		return nil, err
+	}
+	_, diags, err := gcgio.ValidateGCG(sc.config, filename, &kwg.Lexicon{KWG: *k})
+	if err != nil {
+		return nil, err
+	}
+	if len(diags) == 0 {
+		return msg(fmt.Sprintf("no problems found, checking words in %v", lexName)), nil
+	}
+	var s strings.Builder
+	errs := 0
+	for _, d := range diags {
+		if d.Severity == gcgio.Error {
+			errs++
+		}
+		fmt.Fprintln(&s, d.String())
+	}
+	fmt.Fprintf(&s, "%d errors and %d warnings, checking words in %v", errs, len(diags)-errs, lexName)
+	return msg(s.String()), nil
+}
+
 func (sc *ShellController) show(cmd *shellcmd) (*Response, error) {
 	return msg(sc.game.ToDisplayText()), nil
 }
@@ -194,6 +246,90 @@ func (sc *ShellController) turn(cmd *shellcmd) (*Response, error) {
 	return msg(sc.game.ToDisplayText()), nil
 }
 
+func (sc *ShellController) variation(cmd *shellcmd) (*Response, error) {
+	if sc.game == nil {
+		return nil, errors.New("please load a game first with the `load` command")
+	}
+	if len(cmd.args) == 0 {
+		return msg(sc.variationList()), nil
+	}
+	var err error
+	switch cmd.args[0] {
+	case "new":
+		var v *game.Variation
+		v, err = sc.variations.Branch()
+		if err == nil {
+			sc.showMessage(fmt.Sprintf("Started variation %d at turn %d", v.ID, v.BranchTurn))
+		}
+	case "main":
+		// Go back to the mainline, at the point where we left it.
+		turn := sc.variations.Divergence(sc.variations.Current())
+		err = sc.variations.Switch(game.MainlineID, turn)
+	case "del":
+		if len(cmd.args) < 2 {
+			return nil, errors.New("need a variation to delete")
+		}
+		var id int
+		id, err = strconv.Atoi(cmd.args[1])
+		if err != nil {
+			return nil, err
+		}
+		err = sc.variations.Delete(id)
+		if err != nil {
+			return nil, err
+		}
+		return msg(sc.variationList()), nil
+	default:
+		var id int
+		id, err = strconv.Atoi(cmd.args[0])
+		if err != nil {
+			return nil, err
+		}
+		var v *game.Variation
+		v, err = sc.variations.Variation(id)
+		if err != nil {
+			return nil, err
+		}
+		// Show the first move of the variation by default.
+		turn := v.BranchTurn + 1
+		if len(cmd.args) > 1 {
+			turn, err = strconv.Atoi(cmd.args[1])
+			if err != nil {
+				return nil, err
+			}
+		} else if turn > len(v.Events()) {
+			turn = len(v.Events())
+		}
+		err = sc.variations.Switch(id, turn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sc.curPlayList = nil
+	sc.simmer.Reset()
+	sc.rangefinder.Reset()
+	sc.curTurnNum = sc.game.Turn()
+	return msg(sc.game.ToDisplayText()), nil
+}
+
+func (sc *ShellController) variationList() string {
+	var s strings.Builder
+	cur := sc.variations.Current()
+	for _, v := range sc.variations.Variations() {
+		marker := " "
+		if v == cur {
+			marker = "*"
+		}
+		if v.ID == game.MainlineID {
+			fmt.Fprintf(&s, "%s main: %d turns\n", marker, len(v.Events()))
+			continue
+		}
+		fmt.Fprintf(&s, "%s %4d: from %d at turn %d: %s\n", marker, v.ID, v.ParentID,
+			v.BranchTurn, sc.variations.Describe(v, v.BranchTurn))
+	}
+	return s.String()
+}
+
 func (sc *ShellController) rack(cmd *shellcmd) (*Response, error) {
 	if cmd.args == nil {
 		return nil, errors.New("need argument for rack")
@@ -262,6 +398,9 @@ func (sc *ShellController) selftest(cmd *shellcmd) (*Response, error) {
 
 func (sc *ShellController) challenge(cmd *shellcmd) (*Response, error) {
 	fields := cmd.args
+	if err := sc.branchIfNeeded(); err != nil {
+		return nil, err
+	}
 	if len(fields) > 0 {
 		addlBonus, err := strconv.Atoi(fields[0])
 		if err != nil {
@@ -466,7 +605,19 @@ func (sc *ShellController) export(cmd *shellcmd) (*Response, error) {
 		return nil, errors.New("please provide a filename to save to")
 	}
 	filename := cmd.args[0]
-	contents, err := gcgio.GameHistoryToGCG(sc.game.History(), true)
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		// Game documents have no notes, so only the mainline is written.
+		bts, err := gcgio.GameHistoryToGameDocument(sc.config, sc.variations.MainlineHistory())
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filename, bts, 0644); err != nil {
+			return nil, err
+		}
+		return msg("game document written to " + filename), nil
+	}
+	// Always export the mainline; variations are written as notes.
+	contents, err := gcgio.GameHistoryToGCG(sc.variations.MainlineHistory(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -543,3 +694,85 @@ func (sc *ShellController) leave(cmd *shellcmd) (*Response, error) {
 	res := els.LeaveValue(leave)
 	return msg(strconv.FormatFloat(res, 'f', 3, 64)), nil
 }
+
+func (sc *ShellController) prob(cmd *shellcmd) (*Response, error) {
+	if sc.game == nil {
+		return nil, errors.New("please load or create a game first")
+	}
+	g := sc.game
+	alph := g.Alphabet()
+	pool := stats.NewPool(g.UnseenTiles(g.PlayerOnTurn()), int(alph.NumLetters()))
+
+	var kept []tilemapping.MachineLetter
+	var err error
+	if cmd.options["keep"] != "" {
+		kept, err = tilemapping.ToMachineLetters(strings.ToUpper(cmd.options["keep"]), alph)
+		if err != nil {
+			return nil, err
+		}
+		rack := tilemapping.NewRack(alph)
+		rack.Set(g.RackFor(g.PlayerOnTurn()).TilesOn())
+		for _, ml := range kept {
+			if !rack.Has(ml.IntrinsicTileIdx()) {
+				return nil, fmt.Errorf("cannot keep %s: it is not on the rack %s",
+					cmd.options["keep"], g.RackLettersFor(g.PlayerOnTurn()))
+			}
+			rack.Take(ml.IntrinsicTileIdx())
+		}
+	}
+	draw := g.RackSize() - len(kept)
+	if draw > g.Bag().TilesRemaining() {
+		draw = g.Bag().TilesRemaining()
+	}
+	if cmd.options["draw"] != "" {
+		draw, err = strconv.Atoi(cmd.options["draw"])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if draw < 0 {
+		return nil, errors.New("cannot draw a negative number of tiles")
+	}
+	top := 20
+	if cmd.options["top"] != "" {
+		top, err = strconv.Atoi(cmd.options["top"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "Drawing %d of %d unseen tiles", draw, pool.Size())
+	if len(kept) > 0 {
+		fmt.Fprintf(&s, ", keeping %s", tilemapping.MachineWord(kept).UserVisible(alph))
+	}
+	s.WriteString("\n")
+
+	if len(cmd.args) == 0 {
+		outcomes, count, err := stats.RackDistribution(pool, draw, kept, top)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range outcomes {
+			fmt.Fprintf(&s, "%-10s %8.4f%%\n", o.Rack.UserVisible(alph), 100*o.Probability)
+		}
+		fmt.Fprintf(&s, "%d possible racks\n", count)
+		return msg(s.String()), nil
+	}
+
+	conds := make([]stats.DrawCondition, len(cmd.args))
+	descs := make([]string, len(cmd.args))
+	for i, arg := range cmd.args {
+		conds[i], err = stats.ParseDrawCondition(strings.ToUpper(arg), alph)
+		if err != nil {
+			return nil, err
+		}
+		descs[i] = conds[i].String(alph)
+	}
+	p, err := stats.DrawProbability(pool, draw, kept, conds...)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&s, "%s: %.4f%%\n", strings.Join(descs, " and "), 100*p)
+	return msg(s.String()), nil
+}
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
@@ -115,6 +116,7 @@ type ShellController struct {
 	gameRunnerTicker  *time.Ticker
 
 	curTurnNum     int
+	variations     *game.VariationTree
 	gen            movegen.MoveGenerator
 	backupgen      movegen.MoveGenerator // used for endgame engine
 	curMode        Mode
@@ -228,7 +230,7 @@ func (sc *ShellController) initGameDataStructures() error {
 	sc.simmer = &montecarlo.Simmer{}
 	c, err := equity.NewCombinedStaticCalculator(
 		sc.game.LexiconName(),
-		sc.config, "", equity.PEGAdjustmentFilename)
+		sc.config, "", equity.PEGAdjustmentFilename, sc.game.RackSize())
 	if err != nil {
 		return err
 	}
@@ -244,6 +246,7 @@ func (sc *ShellController) initGameDataStructures() error {
 
 	sc.rangefinder = &rangefinder.RangeFinder{}
 	sc.rangefinder.Init(sc.game.Game, []equity.EquityCalculator{c}, sc.config)
+	sc.variations = game.NewVariationTree(sc.game.Game)
 	return nil
 }
 
@@ -313,6 +316,11 @@ func (sc *ShellController) loadGCG(args []string) error {
 			return err
 		}
 
+	} else if strings.EqualFold(filepath.Ext(args[0]), ".json") {
+		history, err = gcgio.ParseGameDocument(sc.config, args[0])
+		if err != nil {
+			return err
+		}
 	} else {
 		history, err = gcgio.ParseGCG(sc.config, args[0])
 		if err != nil {
@@ -331,6 +339,11 @@ func (sc *ShellController) loadGCG(args []string) error {
 	if err != nil {
 		return err
 	}
+	if tc := game.HistoryTimeControl(history); tc != nil {
+		if err = rules.SetTimeControl(*tc); err != nil {
+			return err
+		}
+	}
 	g, err := game.NewFromHistory(history, rules, 0)
 	if err != nil {
 		return err
@@ -487,9 +500,28 @@ func (sc *ShellController) getMoveFromList(playerid int, play string) (*move.Mov
 	return sc.curPlayList[idx], nil
 }
 
+// branchIfNeeded starts a new variation if a move is about to be added
+// anywhere but at the end of the current line, so that the rest of the
+// line is kept.
+func (sc *ShellController) branchIfNeeded() error {
+	if sc.game.Turn() >= len(sc.game.History().Events) {
+		return nil
+	}
+	v, err := sc.variations.Branch()
+	if err != nil {
+		return err
+	}
+	sc.showMessage(fmt.Sprintf("Started variation %d at turn %d", v.ID, v.BranchTurn))
+	return nil
+}
+
 func (sc *ShellController) commitMove(m *move.Move) error {
+	err := sc.branchIfNeeded()
+	if err != nil {
+		return err
+	}
 	// Play the actual move on the board, draw tiles, etc.
-	err := sc.game.PlayMove(m, true, 0)
+	err = sc.game.PlayMove(m, true, 0)
 	if err != nil {
 		return err
 	}
@@ -656,6 +688,29 @@ func (sc *ShellController) handleAutoplay(args []string, options map[string]stri
 	if numthreads < 1 {
 		return errors.New("need at least one thread")
 	}
+	var timeControl *game.TimeControl
+	if options["time"] != "" {
+		initial, err := time.ParseDuration(options["time"])
+		if err != nil {
+			return err
+		}
+		var increment, maxOvertime time.Duration
+		if options["increment"] != "" {
+			increment, err = time.ParseDuration(options["increment"])
+			if err != nil {
+				return err
+			}
+		}
+		if options["maxovertime"] != "" {
+			maxOvertime, err = time.ParseDuration(options["maxovertime"])
+			if err != nil {
+				return err
+			}
+		}
+		tc := game.NewTimeControl(initial, increment)
+		tc.MaxOvertime = maxOvertime
+		timeControl = &tc
+	}
 	if len(args) == 1 {
 		if args[0] == "stop" {
 			if !sc.gameRunnerRunning {
@@ -680,7 +735,7 @@ func (sc *ShellController) handleAutoplay(args []string, options map[string]stri
 		[]automatic.AutomaticRunnerPlayer{
 			{LeaveFile: leavefile1, PEGFile: pegfile1, BotCode: botcode1, MinSimPlies: minsimplies1},
 			{LeaveFile: leavefile2, PEGFile: pegfile2, BotCode: botcode2, MinSimPlies: minsimplies2},
-		})
+		}, timeControl)
 
 	if err != nil {
 		return err
@@ -746,6 +801,8 @@ func (sc *ShellController) standardModeSwitch(line string, sig chan os.Signal) (
 		return sc.newGame(cmd)
 	case "load":
 		return sc.load(cmd)
+	case "gcgcheck":
+		return sc.gcgcheck(cmd)
 	case "n":
 		return sc.next(cmd)
 	case "p":
@@ -758,6 +815,8 @@ func (sc *ShellController) standardModeSwitch(line string, sig chan os.Signal) (
 		return sc.note(cmd)
 	case "turn":
 		return sc.turn(cmd)
+	case "var":
+		return sc.variation(cmd)
 	case "rack":
 		return sc.rack(cmd)
 	case "set":
@@ -796,6 +855,18 @@ func (sc *ShellController) standardModeSwitch(line string, sig chan os.Signal) (
 		return sc.gid(cmd)
 	case "leave":
 		return sc.leave(cmd)
+	case "prob":
+		return sc.prob(cmd)
+	case "anagram", "subanagram", "superanagram":
+		return sc.anagram(cmd)
+	case "pattern":
+		return sc.pattern(cmd)
+	case "hooks":
+		return sc.hooks(cmd)
+	case "judge":
+		return sc.judge(cmd)
+	case "quiz":
+		return sc.quiz(cmd)
 	default:
 		msg := fmt.Sprintf("command %v not found", strconv.Quote(cmd.cmd))
 		log.Info().Msg(msg)
@@ -0,0 +1,257 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/study"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+// defaultWordListLength is how many words the word study commands list,
+// unless told otherwise.
+const defaultWordListLength = 100
+
+// studyLexicon returns the lexicon and letter distribution of the current
+// game, or of the shell's settings if no game is loaded.
+func (sc *ShellController) studyLexicon() (*kwg.KWG, *tilemapping.LetterDistribution, error) {
+	if sc.game != nil {
+		gd, err := kwg.Get(sc.config, sc.game.LexiconName())
+		if err != nil {
+			return nil, nil, err
+		}
+		return gd, sc.game.Bag().LetterDistribution(), nil
+	}
+	gd, err := kwg.Get(sc.config, sc.config.DefaultLexicon)
+	if err != nil {
+		return nil, nil, err
+	}
+	dist, err := tilemapping.GetDistribution(sc.config, sc.config.DefaultLetterDistribution)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gd, dist, nil
+}
+
+func (sc *ShellController) anagram(cmd *shellcmd) (*Response, error) {
+	if len(cmd.args) != 1 {
+		return nil, fmt.Errorf("please provide the letters to %v", cmd.cmd)
+	}
+	gd, dist, err := sc.studyLexicon()
+	if err != nil {
+		return nil, err
+	}
+	da := kwg.KWGAnagrammer{}
+	if err := da.InitForString(gd, strings.ToUpper(cmd.args[0])); err != nil {
+		return nil, err
+	}
+	words := []tilemapping.MachineWord{}
+	collect := func(w tilemapping.MachineWord) error {
+		words = append(words, append(tilemapping.MachineWord{}, w...))
+		return nil
+	}
+	switch cmd.cmd {
+	case "anagram":
+		err = da.Anagram(gd, collect)
+	case "subanagram":
+		err = da.Subanagram(gd, collect)
+	case "superanagram":
+		err = da.Superanagram(gd, collect)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sc.wordList(cmd, words, dist)
+}
+
+func (sc *ShellController) pattern(cmd *shellcmd) (*Response, error) {
+	if len(cmd.args) != 1 {
+		return nil, errors.New("please provide a pattern, such as ?A[EIO]*S")
+	}
+	gd, dist, err := sc.studyLexicon()
+	if err != nil {
+		return nil, err
+	}
+	p, err := kwg.ParsePattern(cmd.args[0], gd.GetAlphabet())
+	if err != nil {
+		return nil, err
+	}
+	words := []tilemapping.MachineWord{}
+	err = p.Search(gd, func(w tilemapping.MachineWord) error {
+		words = append(words, append(tilemapping.MachineWord{}, w...))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sc.wordList(cmd, words, dist)
+}
+
+// wordList lists words from the longest to the shortest, and the most
+// probable first for each length.
+func (sc *ShellController) wordList(cmd *shellcmd, words []tilemapping.MachineWord,
+	dist *tilemapping.LetterDistribution) (*Response, error) {
+
+	top := defaultWordListLength
+	if cmd.options["top"] != "" {
+		var err error
+		top, err = strconv.Atoi(cmd.options["top"])
+		if err != nil {
+			return nil, err
+		}
+	}
+	alph := dist.TileMapping()
+	combos := make([]uint64, len(words))
+	visible := make([]string, len(words))
+	for i, w := range words {
+		combos[i] = bot.Combinations(dist, w)
+		visible[i] = w.UserVisible(alph)
+	}
+	idx := make([]int, len(words))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		i, j := idx[a], idx[b]
+		if len(words[i]) != len(words[j]) {
+			return len(words[i]) > len(words[j])
+		}
+		if combos[i] != combos[j] {
+			return combos[i] > combos[j]
+		}
+		return visible[i] < visible[j]
+	})
+
+	var s strings.Builder
+	for n, i := range idx {
+		if n == top {
+			fmt.Fprintf(&s, "... and %d more\n", len(idx)-top)
+			break
+		}
+		fmt.Fprintf(&s, "%-15s %12d\n", visible[i], combos[i])
+	}
+	fmt.Fprintf(&s, "%d words\n", len(words))
+	return msg(s.String()), nil
+}
+
+func (sc *ShellController) hooks(cmd *shellcmd) (*Response, error) {
+	if len(cmd.args) != 1 {
+		return nil, errors.New("please provide a word")
+	}
+	gd, _, err := sc.studyLexicon()
+	if err != nil {
+		return nil, err
+	}
+	alph := gd.GetAlphabet()
+	word, err := tilemapping.ToMachineWord(strings.ToUpper(cmd.args[0]), alph)
+	if err != nil {
+		return nil, err
+	}
+	letters := func(mls []tilemapping.MachineLetter) string {
+		if len(mls) == 0 {
+			return "-"
+		}
+		return tilemapping.MachineWord(mls).UserVisible(alph)
+	}
+	shown := word.UserVisible(alph)
+	if !kwg.FindMachineWord(gd, word) {
+		shown += "*"
+	}
+	return msg(fmt.Sprintf("%s %s %s", letters(kwg.FrontHooks(gd, word)), shown,
+		letters(kwg.BackHooks(gd, word)))), nil
+}
+
+func (sc *ShellController) judge(cmd *shellcmd) (*Response, error) {
+	if len(cmd.args) == 0 {
+		return nil, errors.New("please provide one or more words")
+	}
+	gd, _, err := sc.studyLexicon()
+	if err != nil {
+		return nil, err
+	}
+	alph := gd.GetAlphabet()
+	valid := true
+	var s strings.Builder
+	for _, arg := range cmd.args {
+		word, err := tilemapping.ToMachineWord(strings.ToUpper(arg), alph)
+		if err != nil {
+			return nil, err
+		}
+		if kwg.FindMachineWord(gd, word) {
+			fmt.Fprintf(&s, "%s is valid\n", word.UserVisible(alph))
+		} else {
+			valid = false
+			fmt.Fprintf(&s, "%s is not valid\n", word.UserVisible(alph))
+		}
+	}
+	if valid {
+		fmt.Fprintf(&s, "The play is VALID in %s\n", gd.LexiconName())
+	} else {
+		fmt.Fprintf(&s, "The play is NOT VALID in %s\n", gd.LexiconName())
+	}
+	return msg(s.String()), nil
+}
+
+func (sc *ShellController) quiz(cmd *shellcmd) (*Response, error) {
+	if len(cmd.args) != 1 {
+		return nil, errors.New("please provide a word length")
+	}
+	length, err := strconv.Atoi(cmd.args[0])
+	if err != nil {
+		return nil, err
+	}
+	from, to := 1, 0
+	if cmd.options["from"] != "" {
+		if from, err = strconv.Atoi(cmd.options["from"]); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.options["to"] != "" {
+		if to, err = strconv.Atoi(cmd.options["to"]); err != nil {
+			return nil, err
+		}
+	}
+	shuffle := false
+	if cmd.options["shuffle"] != "" {
+		if shuffle, err = strconv.ParseBool(cmd.options["shuffle"]); err != nil {
+			return nil, err
+		}
+	}
+	gd, dist, err := sc.studyLexicon()
+	if err != nil {
+		return nil, err
+	}
+	alphagrams, err := study.Alphagrams(gd, dist, length)
+	if err != nil {
+		return nil, err
+	}
+	cards := study.Quiz(alphagrams, from, to, shuffle)
+	alph := dist.TileMapping()
+
+	if filename := cmd.options["o"]; filename != "" {
+		f, err := os.Create(filename)
+		if err != nil {
+			return nil, err
+		}
+		if err = study.WriteCSV(f, alph, cards); err != nil {
+			f.Close()
+			return nil, err
+		}
+		if err = f.Close(); err != nil {
+			return nil, err
+		}
+		return msg(fmt.Sprintf("wrote %d flashcards to %s", len(cards), filename)), nil
+	}
+	var s strings.Builder
+	for _, c := range cards {
+		fmt.Fprintf(&s, "%6d %-15s %d\n", c.Probability, c.Letters.UserVisible(alph), len(c.Words))
+	}
+	fmt.Fprintf(&s, "%d alphagrams\n", len(cards))
+	return msg(s.String()), nil
+}
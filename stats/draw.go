@@ -0,0 +1,326 @@
+package stats
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var (
+	ErrDrawTooLarge     = errors.New("cannot draw more tiles than there are in the pool")
+	ErrEmptyCondition   = errors.New("a draw condition needs at least one letter")
+	ErrTileNotInPool    = errors.New("kept tile is not part of the alphabet")
+	ErrInvalidCondition = errors.New("invalid draw condition")
+)
+
+// A Pool is a multiset of tiles, such as the tiles unseen by a player. It
+// is indexed by machine letter and holds the number of copies of each tile.
+type Pool []int
+
+// NewPool creates a pool out of the given tiles. numLetters is the size of
+// the alphabet, including the blank.
+func NewPool(tiles []tilemapping.MachineLetter, numLetters int) Pool {
+	p := make(Pool, numLetters)
+	for _, t := range tiles {
+		p[t.IntrinsicTileIdx()]++
+	}
+	return p
+}
+
+// Size returns the number of tiles in the pool.
+func (p Pool) Size() int {
+	n := 0
+	for _, ct := range p {
+		n += ct
+	}
+	return n
+}
+
+// A DrawCondition requires the number of tiles drawn from a set of letters
+// to be between Min and Max, inclusive. For example, "at least one S or
+// blank" is the letters S and ? with a Min of 1 and no Max.
+type DrawCondition struct {
+	Letters []tilemapping.MachineLetter
+	Min     int
+	Max     int
+}
+
+// NoMax is the Max of a DrawCondition with no upper bound.
+const NoMax = math.MaxInt32
+
+// ParseDrawCondition parses a condition such as "S?" (at least one S or
+// blank), "!U" (no U), "E>=2", "AEIOU<=1" or "Z=0". The letters come
+// first, followed by an optional comparison with one of =, <, <=, >, >=.
+// Letters with no comparison mean at least one of them.
+func ParseDrawCondition(s string, tm *tilemapping.TileMapping) (DrawCondition, error) {
+	cond := DrawCondition{Min: 1, Max: NoMax}
+	letters := s
+	if strings.HasPrefix(s, "!") {
+		letters = s[1:]
+		cond.Min, cond.Max = 0, 0
+	} else if idx := strings.IndexAny(s, "<>="); idx != -1 {
+		letters = s[:idx]
+		op := s[idx:]
+		numStart := strings.IndexFunc(op, func(r rune) bool { return r >= '0' && r <= '9' })
+		if numStart == -1 {
+			return cond, fmt.Errorf("%w: %v has no count", ErrInvalidCondition, s)
+		}
+		n, err := strconv.Atoi(op[numStart:])
+		if err != nil {
+			return cond, fmt.Errorf("%w: %v", ErrInvalidCondition, s)
+		}
+		switch op[:numStart] {
+		case "=":
+			cond.Min, cond.Max = n, n
+		case "<":
+			cond.Min, cond.Max = 0, n-1
+		case "<=":
+			cond.Min, cond.Max = 0, n
+		case ">":
+			cond.Min = n + 1
+		case ">=":
+			cond.Min = n
+		default:
+			return cond, fmt.Errorf("%w: unknown comparison %v", ErrInvalidCondition, op[:numStart])
+		}
+	}
+	if letters == "" {
+		return cond, ErrEmptyCondition
+	}
+	mls, err := tilemapping.ToMachineLetters(letters, tm)
+	if err != nil {
+		return cond, err
+	}
+	for _, ml := range mls {
+		ml = ml.IntrinsicTileIdx()
+		if !containsLetter(cond.Letters, ml) {
+			cond.Letters = append(cond.Letters, ml)
+		}
+	}
+	return cond, nil
+}
+
+// String returns the condition in the form accepted by ParseDrawCondition.
+func (c DrawCondition) String(tm *tilemapping.TileMapping) string {
+	letters := tilemapping.MachineWord(c.Letters).UserVisible(tm)
+	switch {
+	case c.Min == 0 && c.Max == 0:
+		return "!" + letters
+	case c.Min == 1 && c.Max == NoMax:
+		return letters
+	case c.Max == NoMax:
+		return fmt.Sprintf("%s>=%d", letters, c.Min)
+	case c.Min == c.Max:
+		return fmt.Sprintf("%s=%d", letters, c.Max)
+	case c.Min == 0:
+		return fmt.Sprintf("%s<=%d", letters, c.Max)
+	default:
+		return fmt.Sprintf("%s>=%d,<=%d", letters, c.Min, c.Max)
+	}
+}
+
+func (c DrawCondition) satisfied(counts []int) bool {
+	n := 0
+	for _, ml := range c.Letters {
+		n += counts[ml]
+	}
+	return n >= c.Min && n <= c.Max
+}
+
+func containsLetter(mls []tilemapping.MachineLetter, ml tilemapping.MachineLetter) bool {
+	for _, l := range mls {
+		if l == ml {
+			return true
+		}
+	}
+	return false
+}
+
+// Choose returns the binomial coefficient n choose k.
+func Choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(n-k+i) / float64(i)
+	}
+	return c
+}
+
+func keptCounts(kept []tilemapping.MachineLetter, numLetters int) ([]int, error) {
+	counts := make([]int, numLetters)
+	for _, ml := range kept {
+		idx := int(ml.IntrinsicTileIdx())
+		if idx >= numLetters {
+			return nil, ErrTileNotInPool
+		}
+		counts[idx]++
+	}
+	return counts, nil
+}
+
+// DrawProbability returns the exact probability that a rack made up of the
+// kept tiles plus n tiles drawn at random from the pool satisfies every one
+// of the conditions. With no kept tiles, the conditions are about the drawn
+// tiles alone.
+func DrawProbability(pool Pool, n int, kept []tilemapping.MachineLetter,
+	conds ...DrawCondition) (float64, error) {
+
+	total := pool.Size()
+	if n > total {
+		return 0, ErrDrawTooLarge
+	}
+	counts, err := keptCounts(kept, len(pool))
+	if err != nil {
+		return 0, err
+	}
+	// Only the letters that show up in a condition need to be drawn one
+	// by one; every other tile is interchangeable.
+	relevant := []tilemapping.MachineLetter{}
+	for _, c := range conds {
+		for _, ml := range c.Letters {
+			if int(ml) >= len(pool) {
+				return 0, ErrTileNotInPool
+			}
+			if !containsLetter(relevant, ml) {
+				relevant = append(relevant, ml)
+			}
+		}
+	}
+	others := total
+	for _, ml := range relevant {
+		others -= pool[ml]
+	}
+
+	var ways float64
+	var draw func(i, left int, w float64)
+	draw = func(i, left int, w float64) {
+		if i == len(relevant) {
+			if left > others {
+				return
+			}
+			for _, c := range conds {
+				if !c.satisfied(counts) {
+					return
+				}
+			}
+			ways += w * Choose(others, left)
+			return
+		}
+		ml := relevant[i]
+		for k := 0; k <= pool[ml] && k <= left; k++ {
+			counts[ml] += k
+			draw(i+1, left-k, w*Choose(pool[ml], k))
+			counts[ml] -= k
+		}
+	}
+	draw(0, n, 1)
+	return ways / Choose(total, n), nil
+}
+
+// RackOutcome is a rack that can come up after a draw, and its probability.
+type RackOutcome struct {
+	Rack        tilemapping.MachineWord
+	Probability float64
+}
+
+// RackDistribution returns the top most likely racks that can be made of
+// the kept tiles plus n tiles drawn from the pool, along with their
+// probabilities, from most to least likely. It also returns how many racks
+// can be made in all. Drawing seven tiles from a full bag gives millions of
+// racks, so only the top ones are kept while they are counted.
+func RackDistribution(pool Pool, n int, kept []tilemapping.MachineLetter,
+	top int) ([]RackOutcome, int, error) {
+
+	total := pool.Size()
+	if n > total {
+		return nil, 0, ErrDrawTooLarge
+	}
+	if _, err := keptCounts(kept, len(pool)); err != nil {
+		return nil, 0, err
+	}
+	if top <= 0 {
+		return nil, 0, nil
+	}
+	denom := Choose(total, n)
+	best := &outcomeHeap{}
+	count := 0
+	drawn := make([]tilemapping.MachineLetter, 0, n)
+
+	var draw func(ml, left int, w float64)
+	draw = func(ml, left int, w float64) {
+		if left == 0 {
+			count++
+			if best.Len() == top && w <= (*best)[0].ways {
+				return
+			}
+			rack := make(tilemapping.MachineWord, 0, len(kept)+len(drawn))
+			for _, t := range kept {
+				rack = append(rack, t.IntrinsicTileIdx())
+			}
+			rack = append(rack, drawn...)
+			tilemapping.SortMW(rack)
+			heap.Push(best, rankedOutcome{rack: rack, ways: w, seq: count})
+			if best.Len() > top {
+				heap.Pop(best)
+			}
+			return
+		}
+		if ml == len(pool) {
+			return
+		}
+		mark := len(drawn)
+		for k := 0; ; k++ {
+			draw(ml+1, left-k, w*Choose(pool[ml], k))
+			if k == pool[ml] || k == left {
+				break
+			}
+			drawn = append(drawn, tilemapping.MachineLetter(ml))
+		}
+		drawn = drawn[:mark]
+	}
+	draw(0, n, 1)
+
+	outcomes := make([]RackOutcome, best.Len())
+	for i := len(outcomes) - 1; i >= 0; i-- {
+		o := heap.Pop(best).(rankedOutcome)
+		outcomes[i] = RackOutcome{Rack: o.rack, Probability: o.ways / denom}
+	}
+	return outcomes, count, nil
+}
+
+// rankedOutcome is a rack in the running for RackDistribution's top racks.
+// Of two equally likely racks, the one found first ranks higher.
+type rankedOutcome struct {
+	rack tilemapping.MachineWord
+	ways float64
+	seq  int
+}
+
+// outcomeHeap is a min-heap with the lowest-ranked outcome on top.
+type outcomeHeap []rankedOutcome
+
+func (h outcomeHeap) Len() int { return len(h) }
+func (h outcomeHeap) Less(i, j int) bool {
+	if h[i].ways != h[j].ways {
+		return h[i].ways < h[j].ways
+	}
+	return h[i].seq > h[j].seq
+}
+func (h outcomeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *outcomeHeap) Push(x any)   { *h = append(*h, x.(rankedOutcome)) }
+func (h *outcomeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
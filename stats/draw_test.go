@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func fullEnglishPool(is *is.I) (Pool, *tilemapping.TileMapping) {
+	ld, err := tilemapping.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	bag := ld.MakeBag()
+	return NewPool(bag.Peek(), int(ld.TileMapping().NumLetters())), ld.TileMapping()
+}
+
+func TestParseDrawCondition(t *testing.T) {
+	is := is.New(t)
+	_, tm := fullEnglishPool(is)
+	for _, s := range []string{"S?", "!U", "E>=2", "AEIOU<=1", "Z=0", "Q"} {
+		c, err := ParseDrawCondition(s, tm)
+		is.NoErr(err)
+		c2, err := ParseDrawCondition(c.String(tm), tm)
+		is.NoErr(err)
+		is.Equal(c, c2)
+	}
+	c, err := ParseDrawCondition("E>2", tm)
+	is.NoErr(err)
+	is.Equal(c.Min, 3)
+	is.Equal(c.Max, NoMax)
+	c, err = ParseDrawCondition("SS", tm)
+	is.NoErr(err)
+	is.Equal(len(c.Letters), 1)
+
+	_, err = ParseDrawCondition(">=1", tm)
+	is.Equal(err, ErrEmptyCondition)
+	_, err = ParseDrawCondition("E=>1", tm)
+	is.True(err != nil)
+}
+
+func TestDrawProbability(t *testing.T) {
+	is := is.New(t)
+	pool, tm := fullEnglishPool(is)
+	is.Equal(pool.Size(), 100)
+
+	sOrBlank, err := ParseDrawCondition("S?", tm)
+	is.NoErr(err)
+	p, err := DrawProbability(pool, 7, nil, sOrBlank)
+	is.NoErr(err)
+	// 4 Ss and 2 blanks.
+	is.True(fuzzyEqual(p, 1-Choose(94, 7)/Choose(100, 7)))
+
+	q, err := ParseDrawCondition("Q", tm)
+	is.NoErr(err)
+	noU, err := ParseDrawCondition("!U", tm)
+	is.NoErr(err)
+	p, err = DrawProbability(pool, 7, nil, q, noU)
+	is.NoErr(err)
+	is.True(fuzzyEqual(p, Choose(95, 6)/Choose(100, 7)))
+
+	// Keeping a U means we never end up with the Q without it.
+	u, err := tilemapping.ToMachineLetters("U", tm)
+	is.NoErr(err)
+	p, err = DrawProbability(pool, 6, u, q, noU)
+	is.NoErr(err)
+	is.Equal(p, 0.0)
+
+	// No conditions at all always hold.
+	p, err = DrawProbability(pool, 7, nil)
+	is.NoErr(err)
+	is.True(fuzzyEqual(p, 1))
+
+	_, err = DrawProbability(pool, 101, nil)
+	is.Equal(err, ErrDrawTooLarge)
+}
+
+func TestRackDistribution(t *testing.T) {
+	is := is.New(t)
+	_, tm := fullEnglishPool(is)
+	tiles, err := tilemapping.ToMachineLetters("AAB", tm)
+	is.NoErr(err)
+	pool := NewPool(tiles, int(tm.NumLetters()))
+	kept, err := tilemapping.ToMachineLetters("C", tm)
+	is.NoErr(err)
+
+	outcomes, count, err := RackDistribution(pool, 2, kept, 10)
+	is.NoErr(err)
+	is.Equal(count, 2)
+	is.Equal(len(outcomes), 2)
+	is.Equal(outcomes[0].Rack.UserVisible(tm), "ABC")
+	is.True(fuzzyEqual(outcomes[0].Probability, 2.0/3))
+	is.Equal(outcomes[1].Rack.UserVisible(tm), "AAC")
+	is.True(fuzzyEqual(outcomes[1].Probability, 1.0/3))
+
+	full, _ := fullEnglishPool(is)
+	outcomes, count, err = RackDistribution(full, 3, nil, 5000)
+	is.NoErr(err)
+	is.Equal(len(outcomes), count)
+	sum := 0.0
+	for _, o := range outcomes {
+		is.Equal(len(o.Rack), 3)
+		sum += o.Probability
+	}
+	is.True(fuzzyEqual(sum, 1))
+
+	// Only the top racks are kept, but all of them are counted.
+	top, topCount, err := RackDistribution(full, 3, nil, 10)
+	is.NoErr(err)
+	is.Equal(topCount, count)
+	is.Equal(len(top), 10)
+	for i := range top {
+		is.Equal(top[i], outcomes[i])
+	}
+}
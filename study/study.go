@@ -0,0 +1,122 @@
+// Package study makes word lists and flashcard quizzes for word study. Words
+// are grouped by alphagram, the letters of a word in alphabetical order, and
+// alphagrams are ordered by probability: how many ways their letters can be
+// drawn from a full bag of the letter distribution.
+package study
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lukechampine.com/frand"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var errWordLength = errors.New("words must be at least two letters long")
+
+// An Alphagram is a set of letters, and the words they make.
+type Alphagram struct {
+	// Letters are in the order of the alphabet.
+	Letters tilemapping.MachineWord
+	// Words are in alphabetical order.
+	Words []tilemapping.MachineWord
+	// Combinations is the number of ways to draw the letters from a full
+	// bag, using up to two blanks.
+	Combinations uint64
+	// Probability is the alphagram's rank among those of its length, from
+	// 1 for the most probable.
+	Probability int
+}
+
+// Alphagrams returns the alphagrams of all the words of a length in the
+// lexicon, from the most probable to the least. Alphagrams that are just as
+// probable are in the order of the alphabet.
+func Alphagrams(lex *kwg.KWG, dist *tilemapping.LetterDistribution, length int) ([]*Alphagram, error) {
+	if length < 2 {
+		return nil, errWordLength
+	}
+	p, err := kwg.ParsePattern(strings.Repeat("?", length), dist.TileMapping())
+	if err != nil {
+		return nil, err
+	}
+	byLetters := map[string]*Alphagram{}
+	alphagrams := []*Alphagram{}
+	err = p.Search(lex, func(w tilemapping.MachineWord) error {
+		word := append(tilemapping.MachineWord{}, w...)
+		letters := append(tilemapping.MachineWord{}, w...)
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		a, ok := byLetters[string(letters)]
+		if !ok {
+			a = &Alphagram{Letters: letters, Combinations: bot.Combinations(dist, letters)}
+			byLetters[string(letters)] = a
+			alphagrams = append(alphagrams, a)
+		}
+		a.Words = append(a.Words, word)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(alphagrams, func(i, j int) bool {
+		if alphagrams[i].Combinations != alphagrams[j].Combinations {
+			return alphagrams[i].Combinations > alphagrams[j].Combinations
+		}
+		return string(alphagrams[i].Letters) < string(alphagrams[j].Letters)
+	})
+	for i, a := range alphagrams {
+		a.Probability = i + 1
+	}
+	return alphagrams, nil
+}
+
+// Quiz returns the alphagrams with a probability from min to max, inclusive,
+// as flashcards. A max of 0 means there is no maximum. If shuffle is false,
+// the cards stay in order of probability.
+func Quiz(alphagrams []*Alphagram, min, max int, shuffle bool) []*Alphagram {
+	cards := []*Alphagram{}
+	for _, a := range alphagrams {
+		if a.Probability >= min && (max <= 0 || a.Probability <= max) {
+			cards = append(cards, a)
+		}
+	}
+	if shuffle {
+		frand.Shuffle(len(cards), func(i, j int) {
+			cards[i], cards[j] = cards[j], cards[i]
+		})
+	}
+	return cards
+}
+
+// WriteCSV writes flashcards as CSV, with a header. Each card has the
+// alphagram as its question and its words, separated by spaces, as its
+// answer, followed by its probability and combinations.
+func WriteCSV(w io.Writer, alph *tilemapping.TileMapping, cards []*Alphagram) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"alphagram", "words", "probability", "combinations"}); err != nil {
+		return err
+	}
+	for _, c := range cards {
+		words := make([]string, len(c.Words))
+		for i, word := range c.Words {
+			words[i] = word.UserVisible(alph)
+		}
+		err := cw.Write([]string{
+			c.Letters.UserVisible(alph),
+			strings.Join(words, " "),
+			strconv.Itoa(c.Probability),
+			strconv.FormatUint(c.Combinations, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,123 @@
+package study
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/macondo/ai/bot"
+	"github.com/domino14/macondo/config"
+	"github.com/domino14/macondo/kwg"
+	"github.com/domino14/macondo/tilemapping"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func TestAlphagramsEnglish(t *testing.T) {
+	is := is.New(t)
+	dist, err := tilemapping.GetDistribution(&DefaultConfig, "english")
+	is.NoErr(err)
+	alph := dist.TileMapping()
+	words := []tilemapping.MachineWord{}
+	for _, s := range []string{"AE", "ATE", "EAT", "ETA", "TAE", "TEA", "ZAX", "ZEE", "ZZZ"} {
+		w, err := tilemapping.ToMachineWord(s, alph)
+		is.NoErr(err)
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool { return string(words[i]) < string(words[j]) })
+	lex := kwg.FromWords(alph, words)
+
+	alphagrams, err := Alphagrams(lex, dist, 3)
+	is.NoErr(err)
+	is.Equal(len(alphagrams), 4)
+	visible := func(a *Alphagram) []string {
+		s := []string{a.Letters.UserVisible(alph)}
+		for _, w := range a.Words {
+			s = append(s, w.UserVisible(alph))
+		}
+		return s
+	}
+	is.Equal(visible(alphagrams[0]), []string{"AET", "ATE", "EAT", "ETA", "TAE", "TEA"})
+	is.Equal(visible(alphagrams[1]), []string{"EEZ", "ZEE"})
+	is.Equal(visible(alphagrams[2]), []string{"AXZ", "ZAX"})
+	// There is one Z, so ZZZ can only be drawn with both blanks.
+	is.Equal(visible(alphagrams[3]), []string{"ZZZ", "ZZZ"})
+	is.Equal(alphagrams[3].Combinations, uint64(1))
+	for i, a := range alphagrams {
+		is.Equal(a.Probability, i+1)
+		is.Equal(a.Combinations, bot.Combinations(dist, a.Letters))
+	}
+
+	_, err = Alphagrams(lex, dist, 1)
+	is.Equal(err, errWordLength)
+
+	cards := Quiz(alphagrams, 2, 3, false)
+	is.Equal(len(cards), 2)
+	is.Equal(cards[0].Probability, 2)
+	is.Equal(len(Quiz(alphagrams, 2, 0, true)), 3)
+
+	var buf bytes.Buffer
+	is.NoErr(WriteCSV(&buf, alph, cards))
+	records, err := csv.NewReader(&buf).ReadAll()
+	is.NoErr(err)
+	is.Equal(records, [][]string{
+		{"alphagram", "words", "probability", "combinations"},
+		{"EEZ", "ZEE", "2", "235"},
+		{"AXZ", "ZAX", "3", "58"},
+	})
+}
+
+// TestAlphagramsAllDistributions makes word lists from made-up words in
+// every letter distribution, including tiles of more than one letter.
+func TestAlphagramsAllDistributions(t *testing.T) {
+	is := is.New(t)
+	files, err := os.ReadDir(DefaultConfig.DataPath + "/letterdistributions")
+	is.NoErr(err)
+	is.True(len(files) > 0)
+	for _, f := range files {
+		dist, err := tilemapping.GetDistribution(&DefaultConfig, f.Name())
+		is.NoErr(err)
+		alph := dist.TileMapping()
+		n := tilemapping.MachineLetter(alph.NumLetters())
+		// Every pair of letters is a word, as is every letter doubled.
+		words := []tilemapping.MachineWord{}
+		multiRune := []string{}
+		for i := tilemapping.MachineLetter(1); i < n; i++ {
+			if utf8.RuneCountInString(alph.Letter(i)) > 1 {
+				multiRune = append(multiRune, alph.Letter(i))
+			}
+			for j := tilemapping.MachineLetter(1); j < n; j++ {
+				words = append(words, tilemapping.MachineWord{i, j})
+			}
+		}
+		lex := kwg.FromWords(alph, words)
+
+		alphagrams, err := Alphagrams(lex, dist, 2)
+		is.NoErr(err)
+		// n-1 doubled letters, and (n-1)(n-2)/2 pairs of different letters.
+		letters := int(n) - 1
+		is.Equal(len(alphagrams), letters+letters*(letters-1)/2)
+		for i, a := range alphagrams {
+			is.Equal(a.Probability, i+1)
+			if i > 0 {
+				is.True(a.Combinations <= alphagrams[i-1].Combinations)
+			}
+		}
+
+		var buf bytes.Buffer
+		is.NoErr(WriteCSV(&buf, alph, alphagrams))
+		out := buf.String()
+		records, err := csv.NewReader(&buf).ReadAll()
+		is.NoErr(err)
+		is.Equal(len(records), len(alphagrams)+1)
+		for _, l := range multiRune {
+			is.True(strings.Contains(out, l+l))
+		}
+	}
+}
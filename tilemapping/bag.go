@@ -185,10 +185,10 @@ func (b *Bag) rebuildTileSlice(numTilesInBag int) error {
 }
 
 // Redraw is basically a do-over; throw the current rack in the bag
-// and draw a new rack.
+// and draw a new rack. The new rack will have at most len(ml) tiles.
 func (b *Bag) Redraw(currentRack []MachineLetter, ml []MachineLetter) int {
 	b.PutBack(currentRack)
-	return b.DrawAtMost(7, ml)
+	return b.DrawAtMost(len(ml), ml)
 }
 
 // RemoveTiles removes the given tiles from the bag, and returns an error
@@ -1,14 +1,29 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"syscall/js"
 	"unsafe"
 
+	"github.com/domino14/macondo/analysis"
 	"github.com/domino14/macondo/analyzer"
 	"github.com/domino14/macondo/cache"
+	"github.com/domino14/macondo/config"
 )
 
+// analyst runs every request. Its config has no resolved paths, so the
+// lexica and other data files it needs must all be precached.
+var analyst = analysis.NewAnalyzer(defaultConfig())
+
+func defaultConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Load([]string{})
+	cfg.Debug = false
+	return cfg
+}
+
 func precache(this js.Value, args []js.Value) interface{} {
 	cache.Precache(args[0].String(), readJSBytes(args[1]))
 	return nil
@@ -29,10 +44,117 @@ func analyze(this js.Value, args []js.Value) interface{} {
 	return jsonMovesStr
 }
 
+// request returns the function that starts requests of a kind. The
+// function takes the request as JSON, in the same schema as the server's,
+// and for sims an optional function to call with the JSON of the progress
+// so far. It returns an object with a promise of the result's JSON, and a
+// cancel function that stops the request early. Like on the server, sims,
+// endgames and inferences then resolve with what they found so far.
+func request(kind string) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		req := &analysis.Request{}
+		var reqErr error
+		if len(args) == 0 {
+			reqErr = fmt.Errorf("%s needs a request", kind)
+		} else if reqErr = json.Unmarshal([]byte(args[0].String()), req); reqErr == nil {
+			reqErr = req.Validate(kind)
+		}
+		var onProgress js.Value
+		if len(args) > 1 && args[1].Type() == js.TypeFunction {
+			onProgress = args[1]
+		}
+
+		var ctx context.Context
+		var cancel context.CancelFunc
+		if tl := req.TimeLimit(); tl > 0 {
+			ctx, cancel = context.WithTimeout(context.Background(), tl)
+		} else {
+			ctx, cancel = context.WithCancel(context.Background())
+		}
+		// cancel can be called any time, even after the request is done,
+		// so it is never released.
+		cancelFunc := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			cancel()
+			return nil
+		})
+		promise := newPromise(func(resolve, reject js.Value) {
+			if reqErr != nil {
+				cancel()
+				reject.Invoke(jsError(reqErr))
+				return
+			}
+			go func() {
+				defer cancel()
+				res, err := run(ctx, kind, req, func(p any) {
+					if onProgress.Type() != js.TypeFunction {
+						return
+					}
+					if bts, err := json.Marshal(p); err == nil {
+						onProgress.Invoke(string(bts))
+					}
+				})
+				if err != nil {
+					reject.Invoke(jsError(err))
+					return
+				}
+				bts, err := json.Marshal(res)
+				if err != nil {
+					reject.Invoke(jsError(err))
+					return
+				}
+				resolve.Invoke(string(bts))
+			}()
+		})
+		return js.ValueOf(map[string]interface{}{
+			"result": promise,
+			"cancel": cancelFunc,
+		})
+	})
+}
+
+func run(ctx context.Context, kind string, req *analysis.Request, progress func(any)) (any, error) {
+	switch kind {
+	case analysis.KindGenerate:
+		return analyst.Generate(ctx, req)
+	case analysis.KindSim:
+		return analyst.Sim(ctx, req, func(p *analysis.SimResult) { progress(p) })
+	case analysis.KindEndgame:
+		return analyst.Endgame(ctx, req)
+	case analysis.KindInfer:
+		return analyst.Infer(ctx, req)
+	case analysis.KindLoad:
+		return analyst.Load(ctx, req)
+	case analysis.KindCheckWords:
+		return analyst.CheckWords(ctx, req)
+	}
+	return nil, fmt.Errorf("%s is not supported", kind)
+}
+
+// newPromise returns a JS promise that run settles.
+func newPromise(run func(resolve, reject js.Value)) js.Value {
+	var executor js.Func
+	executor = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		executor.Release()
+		run(args[0], args[1])
+		return nil
+	})
+	return js.Global().Get("Promise").New(executor)
+}
+
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
 func registerCallbacks() {
 	js.Global().Get("resMacondo").Invoke(map[string]interface{}{
-		"precache": js.FuncOf(precache),
-		"analyze":  js.FuncOf(analyze),
+		"precache":   js.FuncOf(precache),
+		"analyze":    js.FuncOf(analyze),
+		"generate":   request(analysis.KindGenerate),
+		"sim":        request(analysis.KindSim),
+		"endgame":    request(analysis.KindEndgame),
+		"infer":      request(analysis.KindInfer),
+		"load":       request(analysis.KindLoad),
+		"checkWords": request(analysis.KindCheckWords),
 	})
 }
 
@@ -39,15 +39,15 @@ func (z *Zobrist) Initialize(boardDim int) {
 	}
 	z.maxRackTable = make([][]uint64, tilemapping.MaxAlphabetSize+1)
 	for i := 0; i < tilemapping.MaxAlphabetSize+1; i++ {
-		z.maxRackTable[i] = make([]uint64, game.RackTileLimit)
-		for j := 0; j < game.RackTileLimit; j++ {
+		z.maxRackTable[i] = make([]uint64, game.MaxRackSize+1)
+		for j := 0; j < game.MaxRackSize+1; j++ {
 			z.maxRackTable[i][j] = frand.Uint64n(bignum) + 1
 		}
 	}
 	z.minRackTable = make([][]uint64, tilemapping.MaxAlphabetSize+1)
 	for i := 0; i < tilemapping.MaxAlphabetSize+1; i++ {
-		z.minRackTable[i] = make([]uint64, game.RackTileLimit)
-		for j := 0; j < game.RackTileLimit; j++ {
+		z.minRackTable[i] = make([]uint64, game.MaxRackSize+1)
+		for j := 0; j < game.MaxRackSize+1; j++ {
 			z.minRackTable[i][j] = frand.Uint64n(bignum) + 1
 		}
 	}